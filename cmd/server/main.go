@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"syscall"
 	"time"
@@ -15,9 +18,21 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/joho/godotenv"
 
+	"nvivas/backend/tictactoe-go-server/internal/archive"
+	"nvivas/backend/tictactoe-go-server/internal/challenge"
 	"nvivas/backend/tictactoe-go-server/internal/client"
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/friends"
+	"nvivas/backend/tictactoe-go-server/internal/game"
 	"nvivas/backend/tictactoe-go-server/internal/hub"
+	"nvivas/backend/tictactoe-go-server/internal/leaderboard"
 	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/matchmaking"
+	"nvivas/backend/tictactoe-go-server/internal/profile"
+	"nvivas/backend/tictactoe-go-server/internal/rating"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/session"
+	"nvivas/backend/tictactoe-go-server/internal/store"
 )
 
 const (
@@ -33,6 +48,13 @@ const (
 	// Valores por defecto para límites de recursos
 	defaultMaxTotalClients = 1000 // Valor predeterminado para el máximo de clientes
 	defaultMaxRooms        = 500  // Valor predeterminado para el máximo de salas
+
+	// Persistencia de salas
+	defaultDataDir         = "data"
+	defaultSnapshotSeconds = 10
+
+	// Partidas terminadas que se conservan en memoria para repetirlas
+	recentGamesInMemory = 500
 )
 
 // Instancia global del Hub
@@ -46,6 +68,40 @@ var cancel context.CancelFunc
 var maxTotalClients int
 var maxRooms int
 
+// Gestor de tokens de sesión
+var sessions *session.Manager
+
+// Almacén de datos persistentes, nil si no se pudo abrir
+var dataStore *store.FileStore
+var snapshotInterval time.Duration
+
+// Archivo de partidas terminadas
+var gameArchive *archive.Archive
+
+// Puntuaciones de los jugadores y clasificación por temporadas
+var ratingBook *rating.Book
+var seasonBoard *leaderboard.Board
+
+// Perfiles públicos de los jugadores
+var profileBook *profile.Book
+
+// Listas de amigos de los jugadores
+var friendBook *friends.Book
+
+// Límites de vida de las salas inactivas
+var reapPolicy room.ReapPolicy
+var reapInterval time.Duration
+
+// Tolerancia de la cola de partida rápida
+var matchPolicy matchmaking.Policy
+var matchInterval time.Duration
+
+// Tiempo para presentarse a una partida de torneo antes de perderla
+var tournamentNoShow time.Duration
+
+// Tiempo que espera un reto antes de caducar
+var challengeTimeout time.Duration
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  wsReadBufferSize,
 	WriteBufferSize: wsWriteBufferSize,
@@ -86,8 +142,24 @@ func handleConnections(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	// Recuperar la identidad del jugador si trae un token de sesión válido
+	clientID := uuid.NewString()
+	var sessionErr error
+	if token := r.URL.Query().Get("session"); token != "" {
+		if playerID, err := mainHub.VerifySession(token); err == nil {
+			clientID = playerID
+		} else {
+			sessionErr = err
+		}
+	}
+
 	// Crear una instancia de Client con el contexto global
-	c := client.NewClient(uuid.NewString(), mainHub, conn, ctx)
+	c := client.NewClient(clientID, mainHub, conn, ctx)
+
+	// Avisar si el token no sirvió: el cliente continúa con una identidad nueva
+	if sessionErr != nil {
+		errors.InvalidSession(c.Send, sessionErr.Error(), c.GetID())
+	}
 
 	// Registrar al cliente en el Hub
 	mainHub.Register <- c
@@ -102,6 +174,84 @@ func handleConnections(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleGetGame devuelve el historial de una partida terminada (GET /games/{id})
+func handleGetGame(w http.ResponseWriter, r *http.Request) {
+	gameID := r.PathValue("id")
+
+	record, found, err := gameArchive.Get(gameID)
+	if err != nil {
+		logger.Error("Error leyendo partida archivada", logger.Fields{
+			"gameID": gameID,
+			"error":  err.Error(),
+		})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(record); err != nil {
+		logger.Warn("Error enviando partida archivada", logger.Fields{
+			"gameID": gameID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// handleGetLeaderboard devuelve una página de la clasificación (GET /leaderboard).
+// Admite variant, season, offset, limit y playerId para incluir la posición de un jugador
+func handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	variant := params.Get("variant")
+	if variant == "" {
+		variant = game.VariantClassic
+	}
+	if _, ok := game.LookupVariant(variant); !ok {
+		http.Error(w, "Unknown variant", http.StatusBadRequest)
+		return
+	}
+
+	numbers := make(map[string]int, 3)
+	for _, name := range []string{"season", "offset", "limit"} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return
+		}
+		numbers[name] = n
+	}
+
+	page, err := seasonBoard.Query(variant, numbers["season"], numbers["offset"], numbers["limit"], params.Get("playerId"))
+	if stderrors.Is(err, leaderboard.ErrSeasonNotFound) {
+		http.Error(w, "Season not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error("Error consultando la clasificación", logger.Fields{
+			"variant": variant,
+			"error":   err.Error(),
+		})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		logger.Warn("Error enviando la clasificación", logger.Fields{
+			"variant": variant,
+			"error":   err.Error(),
+		})
+	}
+}
+
 // loadEnv carga variables de entorno desde .env si existe
 func loadEnv() {
 	// Intentar cargar .env, pero no fallar si no existe
@@ -115,6 +265,136 @@ func loadEnv() {
 		"maxTotalClients": maxTotalClients,
 		"maxRooms":        maxRooms,
 	})
+
+	// Secreto para firmar los tokens de sesión
+	secret := []byte(os.Getenv("TICTACTOE_SESSION_SECRET"))
+	if len(secret) == 0 {
+		generated, err := session.GenerateSecret()
+		if err != nil {
+			logger.Fatal("No se pudo generar el secreto de sesión", logger.Fields{"error": err.Error()})
+		}
+		secret = generated
+		logger.Warn("TICTACTOE_SESSION_SECRET no configurado, las sesiones no sobrevivirán a un reinicio", nil)
+	}
+
+	sessionTTL := time.Duration(getEnvInt("TICTACTOE_SESSION_TTL_HOURS", int(session.DefaultTTL/time.Hour))) * time.Hour
+	sessions = session.NewManager(secret, sessionTTL)
+
+	// Directorio donde se guardan las salas entre reinicios
+	dataDir := os.Getenv("TICTACTOE_DATA_DIR")
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	snapshotInterval = time.Duration(getEnvInt("TICTACTOE_SNAPSHOT_SECONDS", defaultSnapshotSeconds)) * time.Second
+
+	var err error
+	dataStore, err = store.NewFileStore(dataDir)
+	if err != nil {
+		logger.Error("No se pudo abrir el directorio de datos, la persistencia queda desactivada", logger.Fields{
+			"dataDir": dataDir,
+			"error":   err.Error(),
+		})
+		dataStore = nil
+	}
+
+	// Las partidas terminadas se guardan en su propio subdirectorio
+	var gamesStore *store.FileStore
+	if dataStore != nil {
+		gamesStore, err = store.NewFileStore(filepath.Join(dataDir, "games"))
+		if err != nil {
+			logger.Error("No se pudo abrir el directorio de partidas, se archivarán solo en memoria", logger.Fields{
+				"dataDir": dataDir,
+				"error":   err.Error(),
+			})
+			gamesStore = nil
+		}
+	}
+	gameArchive = archive.New(gamesStore, recentGamesInMemory)
+
+	// Las puntuaciones de los jugadores, un documento por variante
+	var ratingsStore *store.FileStore
+	if dataStore != nil {
+		ratingsStore, err = store.NewFileStore(filepath.Join(dataDir, "ratings"))
+		if err != nil {
+			logger.Error("No se pudo abrir el directorio de puntuaciones, se guardarán solo en memoria", logger.Fields{
+				"dataDir": dataDir,
+				"error":   err.Error(),
+			})
+			ratingsStore = nil
+		}
+	}
+	ratingBook, err = rating.NewBook(ratingsStore, game.VariantNames())
+	if err != nil {
+		logger.Error("No se pudieron cargar las puntuaciones, se guardarán solo en memoria", logger.Fields{
+			"dataDir": dataDir,
+			"error":   err.Error(),
+		})
+		ratingBook, _ = rating.NewBook(nil, nil)
+	}
+
+	// Clasificación por temporadas; el calendario decide cuándo se cierra cada una
+	schedule, err := leaderboard.ParseSchedule(getEnvString("TICTACTOE_SEASON_SCHEDULE", leaderboard.ScheduleMonthly))
+	if err != nil {
+		logger.Warn("Calendario de temporada inválido, usando temporadas mensuales", logger.Fields{
+			"error": err.Error(),
+		})
+		schedule, _ = leaderboard.ParseSchedule(leaderboard.ScheduleMonthly)
+	}
+	var leaderboardStore *store.FileStore
+	if dataStore != nil {
+		leaderboardStore, err = store.NewFileStore(filepath.Join(dataDir, "leaderboard"))
+		if err != nil {
+			logger.Error("No se pudo abrir el directorio de la clasificación, se guardará solo en memoria", logger.Fields{
+				"dataDir": dataDir,
+				"error":   err.Error(),
+			})
+			leaderboardStore = nil
+		}
+	}
+	seasonBoard, err = leaderboard.New(leaderboardStore, ratingBook, schedule, time.Now())
+	if err != nil {
+		logger.Error("No se pudo recuperar la temporada en curso, se empieza una nueva en memoria", logger.Fields{
+			"error": err.Error(),
+		})
+		seasonBoard, _ = leaderboard.New(nil, ratingBook, schedule, time.Now())
+	}
+
+	// Perfiles de los jugadores, en un único documento del directorio de datos
+	profileBook, err = profile.NewBook(dataStore)
+	if err != nil {
+		logger.Error("No se pudieron cargar los perfiles, se guardarán solo en memoria", logger.Fields{
+			"dataDir": dataDir,
+			"error":   err.Error(),
+		})
+		profileBook, _ = profile.NewBook(nil)
+	}
+
+	// Listas de amigos, también en un único documento
+	friendBook, err = friends.NewBook(dataStore)
+	if err != nil {
+		logger.Error("No se pudieron cargar las listas de amigos, se guardarán solo en memoria", logger.Fields{
+			"dataDir": dataDir,
+			"error":   err.Error(),
+		})
+		friendBook, _ = friends.NewBook(nil)
+	}
+
+	// Salas vacías, esperando rival o terminadas se eliminan pasado su límite (0 lo desactiva)
+	reapPolicy = hub.DefaultReapPolicy()
+	reapPolicy.EmptyTTL = getEnvSeconds("TICTACTOE_EMPTY_ROOM_TTL_SECONDS", reapPolicy.EmptyTTL)
+	reapPolicy.WaitingTTL = getEnvSeconds("TICTACTOE_WAITING_ROOM_TTL_SECONDS", reapPolicy.WaitingTTL)
+	reapPolicy.FinishedTTL = getEnvSeconds("TICTACTOE_FINISHED_ROOM_TTL_SECONDS", reapPolicy.FinishedTTL)
+	reapInterval = getEnvSeconds("TICTACTOE_REAPER_INTERVAL_SECONDS", hub.DefaultReapInterval)
+
+	// Diferencia de puntuación aceptada en partida rápida y cuánto crece por segundo de espera
+	matchPolicy = matchmaking.DefaultPolicy()
+	matchPolicy.InitialTolerance = float64(getEnvInt("TICTACTOE_MATCH_INITIAL_TOLERANCE", int(matchPolicy.InitialTolerance)))
+	matchPolicy.WideningPerSecond = float64(getEnvInt("TICTACTOE_MATCH_WIDENING_PER_SECOND", int(matchPolicy.WideningPerSecond)))
+	matchPolicy.MaxTolerance = float64(getEnvInt("TICTACTOE_MATCH_MAX_TOLERANCE", int(matchPolicy.MaxTolerance)))
+	matchInterval = getEnvSeconds("TICTACTOE_MATCH_INTERVAL_SECONDS", hub.DefaultMatchInterval)
+
+	tournamentNoShow = getEnvSeconds("TICTACTOE_TOURNAMENT_NO_SHOW_SECONDS", hub.DefaultNoShowTimeout)
+	challengeTimeout = getEnvSeconds("TICTACTOE_CHALLENGE_TIMEOUT_SECONDS", challenge.DefaultTimeout)
 }
 
 // getEnvInt obtiene un valor entero de una variable de entorno o devuelve el valor predeterminado
@@ -138,6 +418,23 @@ func getEnvInt(name string, defaultValue int) int {
 	return value
 }
 
+// getEnvString obtiene una variable de entorno o devuelve el valor predeterminado
+func getEnvString(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvSeconds obtiene una duración en segundos de una variable de entorno o devuelve el valor predeterminado
+func getEnvSeconds(name string, defaultValue time.Duration) time.Duration {
+	seconds := getEnvInt(name, int(defaultValue/time.Second))
+	if seconds < 0 {
+		return defaultValue
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // getPort determina el puerto del servidor basado en flags, variables de entorno
 // o el valor por defecto
 func getPort() string {
@@ -177,12 +474,28 @@ func main() {
 	// Crear e iniciar el Hub con el contexto global
 	mainHub = hub.NewHub()
 	mainHub.SetLimits(maxRooms) // Configurar límite de salas
+	mainHub.SetSessionManager(sessions)
+	mainHub.SetArchive(gameArchive)
+	mainHub.SetRatings(ratingBook)
+	mainHub.SetLeaderboard(seasonBoard)
+	mainHub.SetProfiles(profileBook)
+	mainHub.SetFriends(friendBook)
+	mainHub.SetReaper(reapPolicy, reapInterval)
+	mainHub.SetMatchmaking(matchPolicy, matchInterval)
+	mainHub.SetTournaments(tournamentNoShow)
+	mainHub.SetChallenges(challengeTimeout)
+	if dataStore != nil {
+		mainHub.SetStore(dataStore, snapshotInterval)
+		mainHub.RestoreRooms()
+	}
 	go mainHub.Run()
 
 	logger.Info("Hub iniciado", nil)
 
 	// Configurar rutas
 	http.HandleFunc("/ws", handleConnections)
+	http.HandleFunc("GET /games/{id}", handleGetGame)
+	http.HandleFunc("GET /leaderboard", handleGetLeaderboard)
 
 	// Configurar servidor con opciones de cierre controlado
 	server := &http.Server{
@@ -217,6 +530,10 @@ func main() {
 	<-done
 	logger.Info("Recibida señal de apagado, iniciando shutdown", nil)
 
+	// Cerrar el hub antes de cancelar el contexto: su bucle tiene que seguir vivo para hacer
+	// el guardado final de las salas
+	mainHub.Close()
+
 	// Cancelar contexto para que todas las goroutines terminen
 	cancel()
 
@@ -224,9 +541,6 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
 
-	// Cerrar el hub
-	mainHub.Close()
-
 	// Cerrar servidor HTTP con timeout
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Error durante el shutdown del servidor", logger.Fields{"error": err.Error()})
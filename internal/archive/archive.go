@@ -0,0 +1,140 @@
+// Package archive conserva el historial de las partidas terminadas para poder repetirlas
+// una vez eliminada su sala
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// documentPrefix antecede al ID de la partida en el nombre de su documento
+const documentPrefix = "game-"
+
+// Archive guarda las partidas terminadas. Mantiene en memoria las más recientes y, si tiene
+// almacén, escribe cada partida en disco. Es seguro para uso concurrente
+type Archive struct {
+	store *store.FileStore // nil: solo memoria
+	limit int              // Partidas que se conservan en memoria
+
+	mu     sync.RWMutex
+	recent map[string]models.GameRecord
+	order  []string // IDs en orden de llegada, para descartar las más antiguas
+}
+
+// New crea un archivo de partidas. s puede ser nil para guardar solo en memoria
+func New(s *store.FileStore, limit int) *Archive {
+	return &Archive{
+		store:  s,
+		limit:  limit,
+		recent: make(map[string]models.GameRecord),
+	}
+}
+
+// Save archiva una partida terminada
+func (a *Archive) Save(record models.GameRecord) error {
+	if record.ID == "" {
+		return errors.New("partida sin ID")
+	}
+
+	a.mu.Lock()
+	if _, exists := a.recent[record.ID]; !exists {
+		a.order = append(a.order, record.ID)
+	}
+	a.recent[record.ID] = record
+	for a.limit > 0 && len(a.order) > a.limit {
+		delete(a.recent, a.order[0])
+		a.order = a.order[1:]
+	}
+	a.mu.Unlock()
+
+	if a.store == nil {
+		return nil
+	}
+	return a.store.Save(documentPrefix+record.ID, record)
+}
+
+// Get devuelve una partida archivada. Devuelve false si no existe
+func (a *Archive) Get(gameID string) (models.GameRecord, bool, error) {
+	a.mu.RLock()
+	record, ok := a.recent[gameID]
+	a.mu.RUnlock()
+	if ok || a.store == nil {
+		return record, ok, nil
+	}
+
+	found, err := a.store.Load(documentPrefix+gameID, &record)
+	if errors.Is(err, store.ErrInvalidName) {
+		return models.GameRecord{}, false, nil
+	}
+	if err != nil || !found {
+		return models.GameRecord{}, false, err
+	}
+	return record, true, nil
+}
+
+// FromEvents construye el historial de la última partida terminada de un registro de sala.
+// Los datos de la sala (ID, código, variante) los completa quien la archiva
+func FromEvents(events []roomlog.Event) (models.GameRecord, error) {
+	start := -1
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == roomlog.EventGameStarted {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return models.GameRecord{}, errors.New("el registro no contiene ninguna partida")
+	}
+
+	// Asientos y tablero en el momento de empezar la partida
+	atStart, err := roomlog.ReplayRoom(events[:start+1])
+	if err != nil {
+		return models.GameRecord{}, err
+	}
+
+	started := events[start]
+	record := models.GameRecord{
+		ID:        started.GameID,
+		BoardSize: atStart.Game.Board.Size(),
+		WinLength: atStart.Game.WinLength,
+		Players:   make(map[string]string, len(atStart.Game.PlayerSymbols)),
+		FirstTurn: started.Symbol,
+		Moves:     []models.RecordedMove{},
+		StartedAt: started.At.UnixMilli(),
+	}
+	for playerID, symbol := range atStart.Game.PlayerSymbols {
+		record.Players[playerID] = symbol
+	}
+
+	for _, e := range events[start+1:] {
+		switch e.Type {
+		case roomlog.EventMoveApplied:
+			if e.Move == nil {
+				continue
+			}
+			record.Moves = append(record.Moves, models.RecordedMove{
+				Number:   len(record.Moves) + 1,
+				PlayerID: e.PlayerID,
+				Symbol:   e.Symbol,
+				Row:      e.Move.Row,
+				Col:      e.Move.Col,
+				At:       e.At.UnixMilli(),
+			})
+
+		case roomlog.EventGameOver:
+			record.Winner = e.PlayerID
+			record.WinnerSymbol = e.Symbol
+			record.IsDraw = e.IsDraw
+			record.Reason = e.Reason
+			record.EndedAt = e.At.UnixMilli()
+			return record, nil
+		}
+	}
+
+	return models.GameRecord{}, fmt.Errorf("la partida %s no ha terminado", started.GameID)
+}
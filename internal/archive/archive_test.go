@@ -0,0 +1,116 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+func moveEvent(playerID, symbol string, row, col int) roomlog.Event {
+	return roomlog.Event{Type: roomlog.EventMoveApplied, PlayerID: playerID, Symbol: symbol, Move: &models.MovePayload{Row: row, Col: col}}
+}
+
+func TestFromEventsUsesLastGame(t *testing.T) {
+	l := roomlog.NewLog(nil)
+	l.Append(roomlog.Event{Type: roomlog.EventRoomCreated, BoardSize: 3, WinLength: 3})
+	l.Append(roomlog.Event{Type: roomlog.EventPlayerJoined, PlayerID: "p1", Symbol: "X"})
+	l.Append(roomlog.Event{Type: roomlog.EventPlayerJoined, PlayerID: "p2", Symbol: "O"})
+
+	// Primera partida, terminada por abandono
+	l.Append(roomlog.Event{Type: roomlog.EventGameStarted, GameID: "g1", Symbol: "X"})
+	l.Append(moveEvent("p1", "X", 0, 0))
+	l.Append(roomlog.Event{Type: roomlog.EventGameOver, GameID: "g1", PlayerID: "p2", Symbol: "O", Reason: "abandonment"})
+	l.Append(roomlog.Event{Type: roomlog.EventPlayerLeft, PlayerID: "p1"})
+	l.Append(roomlog.Event{Type: roomlog.EventPlayerJoined, PlayerID: "p3", Symbol: "X"})
+	l.Append(roomlog.Event{Type: roomlog.EventGameReset})
+
+	// Segunda partida, en tablas tras dos jugadas registradas
+	l.Append(roomlog.Event{Type: roomlog.EventGameStarted, GameID: "g2", Symbol: "O"})
+	l.Append(moveEvent("p2", "O", 1, 1))
+	l.Append(moveEvent("p3", "X", 0, 0))
+	l.Append(roomlog.Event{Type: roomlog.EventGameOver, GameID: "g2", IsDraw: true, Reason: "draw"})
+
+	record, err := FromEvents(l.Events())
+	if err != nil {
+		t.Fatalf("Error construyendo el historial: %v", err)
+	}
+	if record.ID != "g2" || record.FirstTurn != "O" || !record.IsDraw || record.Reason != "draw" {
+		t.Errorf("Historial incorrecto: %+v", record)
+	}
+	if record.Players["p3"] != "X" || record.Players["p2"] != "O" || len(record.Players) != 2 {
+		t.Errorf("Jugadores incorrectos: %v", record.Players)
+	}
+	if len(record.Moves) != 2 || record.Moves[1].Number != 2 || record.Moves[1].PlayerID != "p3" {
+		t.Errorf("Jugadas incorrectas: %+v", record.Moves)
+	}
+	if record.BoardSize != 3 || record.StartedAt == 0 || record.EndedAt < record.StartedAt {
+		t.Errorf("Datos de partida incorrectos: %+v", record)
+	}
+}
+
+func TestFromEventsUnfinished(t *testing.T) {
+	events := []roomlog.Event{
+		{Type: roomlog.EventRoomCreated, BoardSize: 3, WinLength: 3},
+		{Type: roomlog.EventGameStarted, GameID: "g1", Symbol: "X", At: time.Now()},
+	}
+	if _, err := FromEvents(events); err == nil {
+		t.Error("Se esperaba error para una partida sin terminar")
+	}
+	if _, err := FromEvents(events[:1]); err == nil {
+		t.Error("Se esperaba error para un registro sin partidas")
+	}
+}
+
+func TestArchiveSaveAndGet(t *testing.T) {
+	s, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creando almacén: %v", err)
+	}
+
+	a := New(s, 1)
+	for _, id := range []string{"g1", "g2"} {
+		if err := a.Save(models.GameRecord{ID: id, Reason: "win"}); err != nil {
+			t.Fatalf("Error archivando %s: %v", id, err)
+		}
+	}
+
+	// g1 ya no está en memoria, pero se lee del disco
+	a.mu.RLock()
+	_, inMemory := a.recent["g1"]
+	a.mu.RUnlock()
+	if inMemory {
+		t.Error("La partida más antigua debería haber salido de memoria")
+	}
+
+	record, found, err := a.Get("g1")
+	if err != nil || !found || record.ID != "g1" {
+		t.Errorf("Partida no recuperada del disco: found=%v err=%v", found, err)
+	}
+
+	// Un archivo nuevo sobre el mismo almacén encuentra las partidas
+	if _, found, _ := New(s, 10).Get("g2"); !found {
+		t.Error("La partida debería sobrevivir a un reinicio")
+	}
+
+	if _, found, err := a.Get("../rooms"); found || err != nil {
+		t.Errorf("Un ID inválido debería tratarse como inexistente: found=%v err=%v", found, err)
+	}
+}
+
+func TestArchiveMemoryOnly(t *testing.T) {
+	a := New(nil, 10)
+	if err := a.Save(models.GameRecord{}); err == nil {
+		t.Error("Se esperaba error para una partida sin ID")
+	}
+
+	a.Save(models.GameRecord{ID: "g1"})
+	if _, found, _ := a.Get("g1"); !found {
+		t.Error("La partida debería estar en memoria")
+	}
+	if _, found, _ := a.Get("g2"); found {
+		t.Error("No debería encontrarse una partida inexistente")
+	}
+}
@@ -0,0 +1,153 @@
+// Package challenge guarda los retos pendientes entre jugadores conectados: quién reta a
+// quién, con qué configuración y hasta cuándo puede responderse
+package challenge
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+const (
+	// DefaultTimeout es cuánto espera un reto a que el retado responda
+	DefaultTimeout = time.Minute
+
+	// MaxPerPlayer limita los retos pendientes que un jugador puede enviar a la vez
+	MaxPerPlayer = 5
+)
+
+// Errores al crear un reto
+var (
+	ErrSelf      = errors.New("no puedes retarte a ti mismo")
+	ErrDuplicate = errors.New("ya tienes un reto pendiente con este jugador")
+	ErrTooMany   = errors.New("tienes demasiados retos pendientes")
+)
+
+// Challenge es un reto pendiente entre dos conexiones concretas: si cualquiera de ellas se
+// cierra, el reto debe cancelarse
+type Challenge struct {
+	ID         string
+	Challenger interfaces.Client
+	Target     interfaces.Client
+	Settings   models.RoomSettings
+	ExpiresAt  time.Time
+}
+
+// Involves indica si la conexión es el retador o el retado
+func (c *Challenge) Involves(client interfaces.Client) bool {
+	return c.Challenger == client || c.Target == client
+}
+
+// Registry guarda los retos pendientes. No es seguro para uso concurrente: el Hub lo usa
+// solo desde su bucle
+type Registry struct {
+	timeout    time.Duration
+	challenges map[string]*Challenge // ID del reto -> reto
+}
+
+// NewRegistry crea un registro vacío cuyos retos caducan pasado timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout:    timeout,
+		challenges: make(map[string]*Challenge),
+	}
+}
+
+// Timeout devuelve cuánto espera un reto a que el retado responda
+func (r *Registry) Timeout() time.Duration {
+	return r.timeout
+}
+
+// Create registra un reto de challenger a target. Un jugador no puede retarse a sí mismo,
+// ni retar dos veces al mismo jugador, ni tener más de MaxPerPlayer retos enviados
+func (r *Registry) Create(challenger, target interfaces.Client, settings models.RoomSettings, now time.Time) (*Challenge, error) {
+	if challenger.GetID() == target.GetID() {
+		return nil, ErrSelf
+	}
+
+	sent := 0
+	for _, c := range r.challenges {
+		if c.Challenger != challenger {
+			continue
+		}
+		if c.Target == target {
+			return nil, ErrDuplicate
+		}
+		sent++
+	}
+	if sent >= MaxPerPlayer {
+		return nil, ErrTooMany
+	}
+
+	c := &Challenge{
+		ID:         uuid.NewString(),
+		Challenger: challenger,
+		Target:     target,
+		Settings:   settings,
+		ExpiresAt:  now.Add(r.timeout),
+	}
+	r.challenges[c.ID] = c
+	return c, nil
+}
+
+// Get devuelve un reto pendiente, o nil si no existe
+func (r *Registry) Get(id string) *Challenge {
+	return r.challenges[id]
+}
+
+// Remove retira un reto. Devuelve el reto, o nil si no estaba pendiente
+func (r *Registry) Remove(id string) *Challenge {
+	c, ok := r.challenges[id]
+	if !ok {
+		return nil
+	}
+	delete(r.challenges, id)
+	return c
+}
+
+// Involving devuelve los retos pendientes en los que participa la conexión, del que antes
+// caduca al que más tarda
+func (r *Registry) Involving(client interfaces.Client) []*Challenge {
+	var found []*Challenge
+	for _, c := range r.challenges {
+		if c.Involves(client) {
+			found = append(found, c)
+		}
+	}
+	sortByExpiry(found)
+	return found
+}
+
+// Expired retira y devuelve los retos que nadie ha respondido antes de now
+func (r *Registry) Expired(now time.Time) []*Challenge {
+	var expired []*Challenge
+	for id, c := range r.challenges {
+		if !now.Before(c.ExpiresAt) {
+			expired = append(expired, c)
+			delete(r.challenges, id)
+		}
+	}
+	sortByExpiry(expired)
+	return expired
+}
+
+// Len devuelve cuántos retos hay pendientes
+func (r *Registry) Len() int {
+	return len(r.challenges)
+}
+
+// sortByExpiry ordena los retos por caducidad y, a igualdad, por ID
+func sortByExpiry(challenges []*Challenge) {
+	sort.Slice(challenges, func(i, j int) bool {
+		a, b := challenges[i], challenges[j]
+		if !a.ExpiresAt.Equal(b.ExpiresAt) {
+			return a.ExpiresAt.Before(b.ExpiresAt)
+		}
+		return a.ID < b.ID
+	})
+}
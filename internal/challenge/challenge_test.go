@@ -0,0 +1,169 @@
+package challenge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// stubClient es un cliente mínimo que solo aporta su ID
+type stubClient struct {
+	id string
+}
+
+func (c *stubClient) GetID() string                  { return c.id }
+func (c *stubClient) GetSendChannel() chan []byte    { return nil }
+func (c *stubClient) GetConnection() *websocket.Conn { return nil }
+func (c *stubClient) SetRoom(interface{})            {}
+func (c *stubClient) GetRoom() interface{}           { return nil }
+func (c *stubClient) Close()                         {}
+
+// TestCreateRules verifica las reglas para enviar un reto
+func TestCreateRules(t *testing.T) {
+	now := time.Now()
+	ana, bea := &stubClient{id: "ana"}, &stubClient{id: "bea"}
+
+	cases := []struct {
+		name    string
+		setup   func(r *Registry)
+		from    *stubClient
+		to      *stubClient
+		wantErr error
+	}{
+		{
+			name: "reto válido",
+			from: ana, to: bea,
+		},
+		{
+			name: "a sí mismo",
+			from: ana, to: ana,
+			wantErr: ErrSelf,
+		},
+		{
+			name: "otra conexión del mismo jugador",
+			from: ana, to: &stubClient{id: "ana"},
+			wantErr: ErrSelf,
+		},
+		{
+			name:  "ya retado",
+			setup: func(r *Registry) { r.Create(ana, bea, models.RoomSettings{}, now) },
+			from:  ana, to: bea,
+			wantErr: ErrDuplicate,
+		},
+		{
+			name:  "el retado puede retar de vuelta",
+			setup: func(r *Registry) { r.Create(bea, ana, models.RoomSettings{}, now) },
+			from:  ana, to: bea,
+		},
+		{
+			name: "límite de retos enviados",
+			setup: func(r *Registry) {
+				for i := 0; i < MaxPerPlayer; i++ {
+					r.Create(ana, &stubClient{id: fmt.Sprintf("p%d", i)}, models.RoomSettings{}, now)
+				}
+			},
+			from: ana, to: bea,
+			wantErr: ErrTooMany,
+		},
+		{
+			name: "los retos recibidos no cuentan para el límite",
+			setup: func(r *Registry) {
+				for i := 0; i < MaxPerPlayer; i++ {
+					r.Create(&stubClient{id: fmt.Sprintf("p%d", i)}, ana, models.RoomSettings{}, now)
+				}
+			},
+			from: ana, to: bea,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRegistry(time.Minute)
+			if tc.setup != nil {
+				tc.setup(r)
+			}
+			before := r.Len()
+
+			c, err := r.Create(tc.from, tc.to, models.RoomSettings{Variant: "classic"}, now)
+			if err != tc.wantErr {
+				t.Fatalf("Se esperaba %v, obtenido %v", tc.wantErr, err)
+			}
+			if err != nil {
+				if r.Len() != before {
+					t.Error("Un reto rechazado no debería registrarse")
+				}
+				return
+			}
+			if r.Get(c.ID) != c || !c.ExpiresAt.Equal(now.Add(time.Minute)) || c.Settings.Variant != "classic" {
+				t.Errorf("Reto mal registrado: %+v", c)
+			}
+		})
+	}
+}
+
+// TestExpired verifica que solo caduquen los retos vencidos y que se retiren al caducar
+func TestExpired(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry(time.Minute)
+	ana, bea, carla := &stubClient{id: "ana"}, &stubClient{id: "bea"}, &stubClient{id: "carla"}
+
+	first, _ := r.Create(ana, bea, models.RoomSettings{}, now)
+	second, _ := r.Create(ana, carla, models.RoomSettings{}, now.Add(10*time.Second))
+	third, _ := r.Create(bea, carla, models.RoomSettings{}, now.Add(30*time.Second))
+
+	cases := []struct {
+		at   time.Duration
+		want []*Challenge
+	}{
+		{59 * time.Second, nil},
+		{60 * time.Second, []*Challenge{first}},
+		{75 * time.Second, []*Challenge{second}},
+		{75 * time.Second, nil},
+		{2 * time.Minute, []*Challenge{third}},
+	}
+	for _, tc := range cases {
+		got := r.Expired(now.Add(tc.at))
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("Expired(+%v) = %v, se esperaba %v", tc.at, got, tc.want)
+		}
+		for _, c := range got {
+			if r.Get(c.ID) != nil {
+				t.Errorf("El reto %s debería haberse retirado al caducar", c.ID)
+			}
+		}
+	}
+	if r.Len() != 0 {
+		t.Errorf("No deberían quedar retos, quedan %d", r.Len())
+	}
+}
+
+// TestInvolving verifica qué retos se cancelan cuando una conexión se cierra o se sienta
+// a jugar: los que envió y los que recibió, pero no los de otra conexión del mismo jugador
+func TestInvolving(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry(time.Minute)
+	ana, bea, carla := &stubClient{id: "ana"}, &stubClient{id: "bea"}, &stubClient{id: "carla"}
+	oldAna := &stubClient{id: "ana"}
+
+	sent, _ := r.Create(ana, bea, models.RoomSettings{}, now)
+	received, _ := r.Create(carla, ana, models.RoomSettings{}, now.Add(time.Second))
+	r.Create(bea, carla, models.RoomSettings{}, now)
+	r.Create(oldAna, carla, models.RoomSettings{}, now)
+
+	got := r.Involving(ana)
+	if fmt.Sprint(got) != fmt.Sprint([]*Challenge{sent, received}) {
+		t.Fatalf("Involving(ana) = %v, se esperaba %v", got, []*Challenge{sent, received})
+	}
+	for _, c := range got {
+		if r.Remove(c.ID) != c {
+			t.Errorf("Remove(%s) debería devolver el reto", c.ID)
+		}
+	}
+	if r.Remove(sent.ID) != nil || len(r.Involving(ana)) != 0 || r.Len() != 2 {
+		t.Errorf("Quedan retos de ana o faltan los demás: %d pendientes", r.Len())
+	}
+}
@@ -3,14 +3,17 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/game"
 	"nvivas/backend/tictactoe-go-server/internal/interfaces"
 	"nvivas/backend/tictactoe-go-server/internal/logger"
 	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/roomlist"
 	"nvivas/backend/tictactoe-go-server/pkg/models"
 )
 
@@ -27,12 +30,19 @@ const (
 
 // Client representa una conexión de cliente WebSocket
 type Client struct {
-	ID   string
+	ID   string // Usar GetID: puede cambiar al reanudar una sesión
 	Hub  interfaces.Hub
 	Room interface{} // Se reemplazará con *room.Room cuando se use
 	Conn *websocket.Conn
 	Send chan []byte
 
+	// Protege ID, que el Hub reemplaza al reanudar una sesión
+	idMu sync.RWMutex
+
+	// Cómo quiere recibir GAME_UPDATE (tablero completo o delta); lo leen las salas
+	updateMode   string
+	updateModeMu sync.RWMutex
+
 	// Context para control de cancelación
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -44,13 +54,14 @@ func NewClient(id string, hub interfaces.Hub, conn *websocket.Conn, parentCtx co
 	ctx, cancel := context.WithCancel(parentCtx)
 
 	return &Client{
-		ID:     id,
-		Hub:    hub,
-		Room:   nil,
-		Conn:   conn,
-		Send:   make(chan []byte, 256), // Buffer para mensajes pendientes
-		ctx:    ctx,
-		cancel: cancel,
+		ID:         id,
+		Hub:        hub,
+		Room:       nil,
+		Conn:       conn,
+		Send:       make(chan []byte, 256), // Buffer para mensajes pendientes
+		updateMode: room.UpdateModeFull,
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
@@ -60,14 +71,37 @@ func (c *Client) Close() {
 	c.Conn.Close()
 	// No cerramos el canal Send aquí para evitar data races
 	// La cancelación del contexto debería ser suficiente para que las goroutines terminen
-	logger.Info("Cliente cerrado", logger.Fields{"clientID": c.ID})
+	logger.Info("Cliente cerrado", logger.Fields{"clientID": c.GetID()})
 }
 
 // GetID implements interfaces.Client
 func (c *Client) GetID() string {
+	c.idMu.RLock()
+	defer c.idMu.RUnlock()
 	return c.ID
 }
 
+// SetID reemplaza la identidad del cliente al reanudar una sesión
+func (c *Client) SetID(id string) {
+	c.idMu.Lock()
+	c.ID = id
+	c.idMu.Unlock()
+}
+
+// UpdateMode devuelve cómo quiere recibir el cliente las actualizaciones de partida
+func (c *Client) UpdateMode() string {
+	c.updateModeMu.RLock()
+	defer c.updateModeMu.RUnlock()
+	return c.updateMode
+}
+
+// SetUpdateMode cambia cómo recibe el cliente las actualizaciones de partida
+func (c *Client) SetUpdateMode(mode string) {
+	c.updateModeMu.Lock()
+	c.updateMode = mode
+	c.updateModeMu.Unlock()
+}
+
 // GetSendChannel implements interfaces.Client
 func (c *Client) GetSendChannel() chan []byte {
 	return c.Send
@@ -93,7 +127,7 @@ func (c *Client) ReadPump() {
 	defer func() {
 		// Cuando ReadPump termina, desregistrar cliente y cerrar conexiones
 		logger.Info("ReadPump terminando, desregistrando cliente", logger.Fields{
-			"clientID": c.ID,
+			"clientID": c.GetID(),
 		})
 
 		if c.Hub != nil {
@@ -128,7 +162,7 @@ func (c *Client) ReadPump() {
 		case <-c.ctx.Done():
 			// Contexto cancelado, terminar
 			logger.Info("Contexto cancelado, terminando ReadPump", logger.Fields{
-				"clientID": c.ID,
+				"clientID": c.GetID(),
 			})
 			return
 
@@ -141,7 +175,7 @@ func (c *Client) ReadPump() {
 					websocket.CloseAbnormalClosure) {
 					logger.Error("Error en conexión WebSocket", logger.Fields{
 						"error":    err.Error(),
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 				}
 				return // Salir del bucle si hay error
@@ -150,11 +184,11 @@ func (c *Client) ReadPump() {
 			// Verificar tamaño del mensaje
 			if len(message) > maxMessageSize {
 				logger.Warn("Mensaje excede el tamaño máximo permitido", logger.Fields{
-					"clientID":    c.ID,
+					"clientID":    c.GetID(),
 					"messageSize": len(message),
 					"maxAllowed":  maxMessageSize,
 				})
-				errors.MessageTooLarge(c.Send, c.ID)
+				errors.MessageTooLarge(c.Send, c.GetID())
 				continue
 			}
 
@@ -163,20 +197,48 @@ func (c *Client) ReadPump() {
 			if err := json.Unmarshal(message, &envelope); err != nil {
 				logger.Error("Error deserializando mensaje", logger.Fields{
 					"error":    err.Error(),
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 
 				// Enviar mensaje de error al cliente
-				errors.InvalidMessage(c.Send, c.ID)
+				errors.InvalidMessage(c.Send, c.GetID())
 				continue
 			}
 
 			// Manejar el mensaje según su tipo
 			switch envelope.Type {
 			case "CREATE_ROOM":
+				// Deserializar la configuración partiendo de los valores por defecto,
+				// de modo que los campos omitidos conserven su valor por defecto
+				createPayload := models.CreateRoomPayload{Settings: room.DefaultSettings()}
+				if len(envelope.Payload) > 0 {
+					if err := json.Unmarshal(envelope.Payload, &createPayload); err != nil {
+						logger.Error("Error deserializando payload CREATE_ROOM", logger.Fields{
+							"error":    err.Error(),
+							"clientID": c.GetID(),
+						})
+
+						errors.InvalidPayload(c.Send, "create room", c.GetID())
+						continue
+					}
+				}
+
+				// Validar la configuración y obtener la configuración efectiva
+				settings, err := room.ValidateSettings(createPayload.Settings)
+				if err != nil {
+					logger.Warn("Configuración de sala inválida", logger.Fields{
+						"error":    err.Error(),
+						"clientID": c.GetID(),
+					})
+
+					errors.InvalidPayload(c.Send, "settings: "+err.Error(), c.GetID())
+					continue
+				}
+
 				// Si el cliente solicita crear una sala, enviar al hub
 				logger.Info("Cliente solicita crear sala", logger.Fields{
-					"clientID": c.ID,
+					"clientID": c.GetID(),
+					"variant":  settings.Variant,
 				})
 
 				if c.Hub != nil {
@@ -185,17 +247,17 @@ func (c *Client) ReadPump() {
 					// c.SetRoom(nil)
 
 					hub, ok := c.Hub.(interface {
-						CreateRoom(client interfaces.Client)
+						CreateRoom(client interfaces.Client, settings models.RoomSettings)
 					})
 					if ok {
-						hub.CreateRoom(c)
+						hub.CreateRoom(c, settings)
 					} else {
 						logger.Error("Hub no tiene método CreateRoom", logger.Fields{
-							"clientID": c.ID,
+							"clientID": c.GetID(),
 						})
 
 						// Enviar mensaje de error al cliente
-						errors.Internal(c.Send, c.ID)
+						errors.Internal(c.Send, c.GetID())
 					}
 				}
 
@@ -205,19 +267,31 @@ func (c *Client) ReadPump() {
 				if err := json.Unmarshal(envelope.Payload, &joinPayload); err != nil {
 					logger.Error("Error deserializando payload JOIN_ROOM", logger.Fields{
 						"error":    err.Error(),
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 
 					// Enviar mensaje de error al cliente
-					errors.InvalidPayload(c.Send, "join room", c.ID)
+					errors.InvalidPayload(c.Send, "join room", c.GetID())
 					continue
 				}
 
+				// Se acepta tanto el ID interno como el código corto de la sala
+				if joinPayload.RoomID == "" {
+					joinPayload.RoomID = joinPayload.RoomCode
+				}
+
 				logger.Info("Cliente solicita unirse a sala", logger.Fields{
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 					"roomID":   joinPayload.RoomID,
+					"spectate": joinPayload.Spectate,
 				})
 
+				// Unirse como espectador
+				if joinPayload.Spectate && c.Hub != nil {
+					c.Hub.SpectateRoom(joinPayload.RoomID, c)
+					continue
+				}
+
 				if c.Hub != nil {
 					// Ya no desregistramos al cliente aquí
 					// c.Hub.UnregisterClient(c)
@@ -231,11 +305,11 @@ func (c *Client) ReadPump() {
 						hub.JoinRoom(joinPayload.RoomID, c)
 					} else {
 						logger.Error("Hub no tiene método JoinRoom", logger.Fields{
-							"clientID": c.ID,
+							"clientID": c.GetID(),
 						})
 
 						// Enviar mensaje de error al cliente
-						errors.Internal(c.Send, c.ID)
+						errors.Internal(c.Send, c.GetID())
 					}
 				}
 
@@ -243,10 +317,10 @@ func (c *Client) ReadPump() {
 				// Verificar que el cliente está en una sala
 				if c.Room == nil {
 					logger.Warn("Cliente intentó hacer un movimiento sin estar en una sala", logger.Fields{
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 
-					errors.NotInRoom(c.Send, c.ID)
+					errors.NotInRoom(c.Send, c.GetID())
 					continue
 				}
 
@@ -255,11 +329,11 @@ func (c *Client) ReadPump() {
 				if err := json.Unmarshal(envelope.Payload, &movePayload); err != nil {
 					logger.Error("Error deserializando payload MAKE_MOVE", logger.Fields{
 						"error":    err.Error(),
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 
 					// Enviar mensaje de error al cliente
-					errors.InvalidPayload(c.Send, "make move", c.ID)
+					errors.InvalidPayload(c.Send, "make move", c.GetID())
 					continue
 				}
 
@@ -272,63 +346,548 @@ func (c *Client) ReadPump() {
 					roomObj.ReceiveMove <- playerMove
 
 					logger.Info("Movimiento enviado a sala", logger.Fields{
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 						"roomID":   roomObj.ID,
 						"row":      movePayload.Move.Row,
 						"col":      movePayload.Move.Col,
 					})
 				} else {
 					logger.Error("Room no es del tipo esperado", logger.Fields{
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 
 					// Enviar mensaje de error al cliente
-					errors.Internal(c.Send, c.ID)
+					errors.Internal(c.Send, c.GetID())
 				}
 
+			case "KICK_PLAYER", "LOCK_ROOM", "UNLOCK_ROOM", "TRANSFER_HOST":
+				// Acciones de anfitrión: las valida la propia sala
+				c.sendRoomCommand(envelope)
+
+			case "PLAYER_READY":
+				// Confirmación del ready-check
+				c.sendRoomCommand(envelope)
+
+			case "COIN_FLIP_COMMIT", "COIN_FLIP_REVEAL":
+				// Sorteo commit-reveal del primer movimiento
+				c.sendRoomCommand(envelope)
+
+			case "REQUEST_PAUSE", "ACCEPT_PAUSE":
+				// Pausa de mutuo acuerdo
+				c.sendRoomCommand(envelope)
+
+			case "SET_UPDATE_MODE":
+				// Cliente elige entre tablero completo o actualizaciones delta
+				c.setUpdateMode(envelope)
+
+			case "RESYNC":
+				// Estado completo de la sala tras detectar un salto de versión
+				c.sendRoomCommand(envelope)
+
+			case "RESUME":
+				// Reanuda la partida en pausa
+				c.sendRoomCommand(envelope)
+
+			case "RESUME_SESSION":
+				// Recuperar la identidad de una sesión anterior con su token
+				c.resumeSession(envelope)
+
 			case "LIST_ROOMS":
-				// Cliente solicita listar las salas disponibles
+				// Cliente solicita listar las salas disponibles, con filtros, orden y página opcionales
+				var listPayload models.ListRoomsPayload
+				if len(envelope.Payload) > 0 {
+					if err := json.Unmarshal(envelope.Payload, &listPayload); err != nil {
+						errors.InvalidPayload(c.Send, "list rooms", c.GetID())
+						continue
+					}
+				}
+				query, err := roomlist.Validate(listPayload)
+				if err != nil {
+					errors.InvalidPayload(c.Send, "list rooms: "+err.Error(), c.GetID())
+					continue
+				}
+
 				logger.Info("Cliente solicita listar salas", logger.Fields{
-					"clientID": c.ID,
+					"clientID": c.GetID(),
+					"sort":     query.Sort,
 				})
 
 				if c.Hub != nil {
 					// Solicitar al hub que envíe la lista de salas al cliente
 					hub, ok := c.Hub.(interface {
-						ListRooms(client interfaces.Client)
+						ListRooms(client interfaces.Client, query models.ListRoomsPayload)
 					})
 					if ok {
-						hub.ListRooms(c)
+						hub.ListRooms(c, query)
 					} else {
 						logger.Error("Hub no tiene método ListRooms", logger.Fields{
-							"clientID": c.ID,
+							"clientID": c.GetID(),
 						})
 
 						// Enviar mensaje de error al cliente
-						errors.Internal(c.Send, c.ID)
+						errors.Internal(c.Send, c.GetID())
 					}
 				}
 
+			case "GET_REPLAY":
+				// Cliente solicita la repetición de una partida terminada
+				var replayPayload models.GetReplayPayload
+				if err := json.Unmarshal(envelope.Payload, &replayPayload); err != nil || replayPayload.GameID == "" {
+					errors.InvalidPayload(c.Send, "get replay", c.GetID())
+					continue
+				}
+
+				hub, ok := c.Hub.(interface {
+					SendReplay(client interfaces.Client, gameID string)
+				})
+				if ok {
+					hub.SendReplay(c, replayPayload.GameID)
+				} else {
+					logger.Error("Hub no tiene método SendReplay", logger.Fields{
+						"clientID": c.GetID(),
+					})
+
+					errors.Internal(c.Send, c.GetID())
+				}
+
+			case "SUBSCRIBE_LOBBY", "UNSUBSCRIBE_LOBBY":
+				// Avisos en vivo de las salas públicas y de los jugadores conectados
+				hub, ok := c.Hub.(interface {
+					SubscribeLobby(client interfaces.Client)
+					UnsubscribeLobby(client interfaces.Client)
+				})
+				if !ok {
+					logger.Error("Hub no tiene métodos de vestíbulo", logger.Fields{
+						"clientID": c.GetID(),
+					})
+
+					errors.Internal(c.Send, c.GetID())
+					continue
+				}
+
+				if envelope.Type == "SUBSCRIBE_LOBBY" {
+					hub.SubscribeLobby(c)
+				} else {
+					hub.UnsubscribeLobby(c)
+				}
+
+			case "GET_LEADERBOARD":
+				// Cliente solicita una página de la clasificación
+				c.getLeaderboard(envelope)
+
+			case "FIND_MATCH":
+				// Cliente entra en la cola de partida rápida
+				c.findMatch(envelope)
+
+			case "CANCEL_MATCH":
+				// Cliente sale de la cola de partida rápida
+				hub, ok := c.Hub.(interface {
+					CancelMatch(client interfaces.Client)
+				})
+				if ok {
+					hub.CancelMatch(c)
+				} else {
+					logger.Error("Hub no tiene método CancelMatch", logger.Fields{
+						"clientID": c.GetID(),
+					})
+
+					errors.Internal(c.Send, c.GetID())
+				}
+
+			case "CREATE_TOURNAMENT":
+				// Cliente abre un torneo como organizador
+				c.createTournament(envelope)
+
+			case "JOIN_TOURNAMENT", "LEAVE_TOURNAMENT", "START_TOURNAMENT", "WATCH_TOURNAMENT", "UNWATCH_TOURNAMENT", "LIST_TOURNAMENTS":
+				// Inscripción, inicio y seguimiento de torneos
+				c.tournamentAction(envelope)
+
+			case "SET_PROFILE":
+				// Cliente elige su nombre visible, avatar y país
+				c.setProfile(envelope)
+
+			case "CHALLENGE_PLAYER":
+				// Cliente reta a otro jugador conectado
+				c.challengePlayer(envelope)
+
+			case "ACCEPT_CHALLENGE", "DECLINE_CHALLENGE":
+				// Respuesta a un reto pendiente
+				c.challengeAction(envelope)
+
+			case "ADD_FRIEND", "REMOVE_FRIEND", "LIST_FRIENDS":
+				// Gestión de la lista de amigos
+				c.friendAction(envelope)
+
+			case "SET_PRESENCE":
+				// Cliente elige cómo le ven sus amigos
+				c.setPresence(envelope)
+
 			default:
 				logger.Warn("Tipo de mensaje desconocido", logger.Fields{
 					"messageType": envelope.Type,
-					"clientID":    c.ID,
+					"clientID":    c.GetID(),
 				})
 
 				// Enviar mensaje de error al cliente
-				errors.UnknownMessageType(c.Send, envelope.Type, c.ID)
+				errors.UnknownMessageType(c.Send, envelope.Type, c.GetID())
 			}
 		}
 	}
 }
 
+// resumeSession verifica el token de un mensaje RESUME_SESSION y pide al Hub que devuelva
+// al cliente su identidad y su asiento
+func (c *Client) resumeSession(envelope models.Envelope) {
+	var resumePayload models.ResumePayload
+	if err := json.Unmarshal(envelope.Payload, &resumePayload); err != nil || resumePayload.SessionToken == "" {
+		errors.InvalidPayload(c.Send, "resume session", c.GetID())
+		return
+	}
+
+	// Solo se puede reanudar antes de entrar en una sala con la identidad nueva
+	if c.Room != nil {
+		errors.InvalidSession(c.Send, "No se puede reanudar una sesión estando en una sala", c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		VerifySession(token string) (string, error)
+		ResumeSession(client interfaces.Client, playerID string)
+	})
+	if !ok {
+		logger.Error("Hub no tiene métodos de sesión", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+
+	playerID, err := hub.VerifySession(resumePayload.SessionToken)
+	if err != nil {
+		errors.InvalidSession(c.Send, err.Error(), c.GetID())
+		return
+	}
+
+	hub.ResumeSession(c, playerID)
+}
+
+// setUpdateMode aplica el modo de actualización pedido por el cliente y lo confirma
+func (c *Client) setUpdateMode(envelope models.Envelope) {
+	var modePayload models.SetUpdateModePayload
+	if err := json.Unmarshal(envelope.Payload, &modePayload); err != nil {
+		errors.InvalidPayload(c.Send, "set update mode", c.GetID())
+		return
+	}
+
+	if modePayload.Mode != room.UpdateModeFull && modePayload.Mode != room.UpdateModeDelta {
+		errors.InvalidPayload(c.Send, "mode debe ser 'full' o 'delta'", c.GetID())
+		return
+	}
+
+	c.SetUpdateMode(modePayload.Mode)
+
+	modeMsg := models.UpdateModeResponse{
+		Type: "UPDATE_MODE",
+		Mode: modePayload.Mode,
+	}
+	if modePayload.Mode == room.UpdateModeDelta {
+		modeMsg.CheckpointEvery = room.DeltaCheckpointEvery
+	}
+	msgBytes, _ := json.Marshal(modeMsg)
+
+	select {
+	case c.Send <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar UPDATE_MODE, canal posiblemente cerrado", logger.Fields{
+			"clientID": c.GetID(),
+		})
+	}
+
+	logger.Info("Modo de actualización cambiado", logger.Fields{
+		"clientID": c.GetID(),
+		"mode":     modePayload.Mode,
+	})
+}
+
+// getLeaderboard valida la consulta de GET_LEADERBOARD y pide al Hub la página
+func (c *Client) getLeaderboard(envelope models.Envelope) {
+	var query models.GetLeaderboardPayload
+	if len(envelope.Payload) > 0 {
+		if err := json.Unmarshal(envelope.Payload, &query); err != nil {
+			errors.InvalidPayload(c.Send, "get leaderboard", c.GetID())
+			return
+		}
+	}
+
+	if query.Variant == "" {
+		query.Variant = game.VariantClassic
+	}
+	if _, ok := game.LookupVariant(query.Variant); !ok || query.Season < 0 {
+		errors.InvalidPayload(c.Send, "get leaderboard", c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		SendLeaderboard(client interfaces.Client, query models.GetLeaderboardPayload)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método SendLeaderboard", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.SendLeaderboard(c, query)
+}
+
+// findMatch valida la configuración pedida en FIND_MATCH y pide al Hub que añada al
+// cliente a la cola de partida rápida
+func (c *Client) findMatch(envelope models.Envelope) {
+	var findPayload models.FindMatchPayload
+	if len(envelope.Payload) > 0 {
+		if err := json.Unmarshal(envelope.Payload, &findPayload); err != nil {
+			errors.InvalidPayload(c.Send, "find match", c.GetID())
+			return
+		}
+	}
+
+	// Se valida como la configuración de una sala para resolver el tamaño por defecto
+	settings := room.DefaultSettings()
+	if findPayload.Variant != "" {
+		settings.Variant = findPayload.Variant
+	}
+	settings.BoardSize = findPayload.BoardSize
+	settings.TimeControl = findPayload.TimeControl
+
+	settings, err := room.ValidateSettings(settings)
+	if err != nil {
+		errors.InvalidPayload(c.Send, "find match: "+err.Error(), c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		FindMatch(client interfaces.Client, settings models.RoomSettings)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método FindMatch", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.FindMatch(c, settings)
+}
+
+// createTournament valida un mensaje CREATE_TOURNAMENT y pide al Hub que abra el torneo.
+// La configuración de las salas parte de los valores por defecto, como en CREATE_ROOM
+func (c *Client) createTournament(envelope models.Envelope) {
+	createPayload := models.CreateTournamentPayload{Settings: room.DefaultSettings()}
+	if err := json.Unmarshal(envelope.Payload, &createPayload); err != nil {
+		errors.InvalidPayload(c.Send, "create tournament", c.GetID())
+		return
+	}
+
+	settings, err := room.ValidateSettings(createPayload.Settings)
+	if err != nil {
+		errors.InvalidPayload(c.Send, "create tournament: settings: "+err.Error(), c.GetID())
+		return
+	}
+	createPayload.Settings = settings
+
+	hub, ok := c.Hub.(interface {
+		CreateTournament(client interfaces.Client, payload models.CreateTournamentPayload)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método CreateTournament", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.CreateTournament(c, createPayload)
+}
+
+// tournamentAction reenvía al Hub una acción sobre un torneo existente o LIST_TOURNAMENTS
+func (c *Client) tournamentAction(envelope models.Envelope) {
+	var tournamentPayload models.TournamentPayload
+	if envelope.Type != "LIST_TOURNAMENTS" {
+		if err := json.Unmarshal(envelope.Payload, &tournamentPayload); err != nil || tournamentPayload.TournamentID == "" {
+			errors.InvalidPayload(c.Send, "tournament", c.GetID())
+			return
+		}
+	}
+
+	hub, ok := c.Hub.(interface {
+		TournamentAction(client interfaces.Client, action string, tournamentID string)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método TournamentAction", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.TournamentAction(c, envelope.Type, tournamentPayload.TournamentID)
+}
+
+// setProfile reenvía al Hub el perfil de un mensaje SET_PROFILE; el Hub lo valida
+func (c *Client) setProfile(envelope models.Envelope) {
+	var profilePayload models.SetProfilePayload
+	if err := json.Unmarshal(envelope.Payload, &profilePayload); err != nil {
+		errors.InvalidPayload(c.Send, "set profile", c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		SetProfile(client interfaces.Client, payload models.SetProfilePayload)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método SetProfile", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.SetProfile(c, profilePayload)
+}
+
+// challengePlayer valida un mensaje CHALLENGE_PLAYER y pide al Hub que envíe el reto.
+// La configuración parte de los valores por defecto, como en CREATE_ROOM
+func (c *Client) challengePlayer(envelope models.Envelope) {
+	challengePayload := models.ChallengePlayerPayload{Settings: room.DefaultSettings()}
+	if err := json.Unmarshal(envelope.Payload, &challengePayload); err != nil || challengePayload.TargetID == "" {
+		errors.InvalidPayload(c.Send, "challenge player", c.GetID())
+		return
+	}
+
+	settings, err := room.ValidateSettings(challengePayload.Settings)
+	if err != nil {
+		errors.InvalidPayload(c.Send, "challenge player: settings: "+err.Error(), c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		ChallengePlayer(client interfaces.Client, targetID string, settings models.RoomSettings)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método ChallengePlayer", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.ChallengePlayer(c, challengePayload.TargetID, settings)
+}
+
+// challengeAction reenvía al Hub la respuesta a un reto (ACCEPT_CHALLENGE o DECLINE_CHALLENGE)
+func (c *Client) challengeAction(envelope models.Envelope) {
+	var challengePayload models.ChallengePayload
+	if err := json.Unmarshal(envelope.Payload, &challengePayload); err != nil || challengePayload.ChallengeID == "" {
+		errors.InvalidPayload(c.Send, "challenge", c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		ChallengeAction(client interfaces.Client, action string, challengeID string)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método ChallengeAction", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.ChallengeAction(c, envelope.Type, challengePayload.ChallengeID)
+}
+
+// friendAction reenvía al Hub un mensaje de la lista de amigos (ADD_FRIEND, REMOVE_FRIEND o
+// LIST_FRIENDS). LIST_FRIENDS no necesita payload
+func (c *Client) friendAction(envelope models.Envelope) {
+	var friendPayload models.FriendPayload
+	if envelope.Type != "LIST_FRIENDS" {
+		if err := json.Unmarshal(envelope.Payload, &friendPayload); err != nil || friendPayload.PlayerID == "" {
+			errors.InvalidPayload(c.Send, "friend", c.GetID())
+			return
+		}
+	}
+
+	hub, ok := c.Hub.(interface {
+		FriendAction(client interfaces.Client, action string, playerID string)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método FriendAction", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.FriendAction(c, envelope.Type, friendPayload.PlayerID)
+}
+
+// setPresence valida un mensaje SET_PRESENCE y pide al Hub que avise a los amigos
+func (c *Client) setPresence(envelope models.Envelope) {
+	var presencePayload models.SetPresencePayload
+	if err := json.Unmarshal(envelope.Payload, &presencePayload); err != nil {
+		errors.InvalidPayload(c.Send, "set presence", c.GetID())
+		return
+	}
+	switch presencePayload.Status {
+	case "online", "away", "invisible":
+	default:
+		errors.InvalidPayload(c.Send, "set presence: status debe ser online, away o invisible", c.GetID())
+		return
+	}
+
+	hub, ok := c.Hub.(interface {
+		SetPresence(client interfaces.Client, status string)
+	})
+	if !ok {
+		logger.Error("Hub no tiene método SetPresence", logger.Fields{
+			"clientID": c.GetID(),
+		})
+		errors.Internal(c.Send, c.GetID())
+		return
+	}
+	hub.SetPresence(c, presencePayload.Status)
+}
+
+// sendRoomCommand reenvía una acción de sala a la sala en la que está el cliente
+func (c *Client) sendRoomCommand(envelope models.Envelope) {
+	roomObj, ok := c.Room.(*room.Room)
+	if !ok || roomObj == nil {
+		logger.Warn("Cliente intentó una acción de sala sin estar en una sala", logger.Fields{
+			"clientID":    c.GetID(),
+			"messageType": envelope.Type,
+		})
+
+		errors.NotInRoom(c.Send, c.GetID())
+		return
+	}
+
+	submitted := roomObj.Submit(&room.Command{
+		Client:  c,
+		Type:    envelope.Type,
+		Payload: envelope.Payload,
+	})
+	if !submitted {
+		errors.NotInRoom(c.Send, c.GetID())
+		return
+	}
+
+	logger.Info("Acción enviada a sala", logger.Fields{
+		"clientID":    c.GetID(),
+		"roomID":      roomObj.ID,
+		"messageType": envelope.Type,
+	})
+}
+
 // WritePump maneja el envío de mensajes al WebSocket
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.Conn.Close()
-		logger.Info("WritePump terminado", logger.Fields{"clientID": c.ID})
+		logger.Info("WritePump terminado", logger.Fields{"clientID": c.GetID()})
 	}()
 
 	for {
@@ -336,7 +895,7 @@ func (c *Client) WritePump() {
 		case <-c.ctx.Done():
 			// Contexto cancelado, terminar
 			logger.Info("Contexto cancelado, terminando WritePump", logger.Fields{
-				"clientID": c.ID,
+				"clientID": c.GetID(),
 			})
 			return
 
@@ -346,7 +905,7 @@ func (c *Client) WritePump() {
 			if !ok {
 				// El canal Send está cerrado
 				logger.Info("Canal Send cerrado, enviando mensaje de cierre", logger.Fields{
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
@@ -356,7 +915,7 @@ func (c *Client) WritePump() {
 			if err != nil {
 				logger.Error("Error obteniendo writer de WebSocket", logger.Fields{
 					"error":    err.Error(),
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 				return
 			}
@@ -364,7 +923,7 @@ func (c *Client) WritePump() {
 			if _, err := w.Write(message); err != nil {
 				logger.Error("Error escribiendo mensaje", logger.Fields{
 					"error":    err.Error(),
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 				return
 			}
@@ -376,7 +935,7 @@ func (c *Client) WritePump() {
 				if _, err := w.Write(msg); err != nil {
 					logger.Error("Error escribiendo mensaje encolado", logger.Fields{
 						"error":    err.Error(),
-						"clientID": c.ID,
+						"clientID": c.GetID(),
 					})
 				}
 			}
@@ -384,7 +943,7 @@ func (c *Client) WritePump() {
 			if err := w.Close(); err != nil {
 				logger.Error("Error cerrando writer de WebSocket", logger.Fields{
 					"error":    err.Error(),
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 				return
 			}
@@ -395,11 +954,11 @@ func (c *Client) WritePump() {
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				logger.Error("Error enviando ping", logger.Fields{
 					"error":    err.Error(),
-					"clientID": c.ID,
+					"clientID": c.GetID(),
 				})
 				return
 			}
-			logger.Debug("Ping enviado", logger.Fields{"clientID": c.ID})
+			logger.Debug("Ping enviado", logger.Fields{"clientID": c.GetID()})
 		}
 	}
 }
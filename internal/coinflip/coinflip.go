@@ -0,0 +1,76 @@
+package coinflip
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	// MinNonceBytes es el tamaño mínimo del nonce que aporta cada jugador
+	MinNonceBytes = 16
+
+	// MaxNonceBytes limita el tamaño del nonce aceptado
+	MaxNonceBytes = 64
+)
+
+var (
+	// ErrInvalidNonce indica que el nonce no es hexadecimal o no tiene un tamaño válido
+	ErrInvalidNonce = errors.New("el nonce debe ser hexadecimal de entre 16 y 64 bytes")
+
+	// ErrInvalidCommitment indica que el compromiso no es un SHA-256 en hexadecimal
+	ErrInvalidCommitment = errors.New("el compromiso debe ser un SHA-256 en hexadecimal")
+
+	// ErrMismatch indica que el nonce revelado no corresponde al compromiso
+	ErrMismatch = errors.New("el nonce revelado no coincide con el compromiso")
+)
+
+// Commit calcula el compromiso (SHA-256 en hexadecimal) de un nonce
+func Commit(nonce []byte) string {
+	sum := sha256.Sum256(nonce)
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseNonce decodifica un nonce hexadecimal y valida su tamaño
+func ParseNonce(nonceHex string) ([]byte, error) {
+	nonce, err := hex.DecodeString(nonceHex)
+	if err != nil || len(nonce) < MinNonceBytes || len(nonce) > MaxNonceBytes {
+		return nil, ErrInvalidNonce
+	}
+	return nonce, nil
+}
+
+// NormalizeCommitment valida un compromiso y lo devuelve en minúsculas
+func NormalizeCommitment(commitment string) (string, error) {
+	commitment = strings.ToLower(commitment)
+	decoded, err := hex.DecodeString(commitment)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", ErrInvalidCommitment
+	}
+	return commitment, nil
+}
+
+// Verify comprueba que el nonce revelado corresponda al compromiso publicado
+func Verify(commitment string, nonce []byte) error {
+	expected := Commit(nonce)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(commitment))) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Decide combina con XOR los nonces de ambos jugadores y devuelve el bit menos
+// significativo del resultado: 0 significa que empieza el creador y 1 el rival.
+// Los nonces de distinto tamaño se combinan alineados por el final
+func Decide(creatorNonce, joinerNonce []byte) int {
+	var last byte
+	if len(creatorNonce) > 0 {
+		last ^= creatorNonce[len(creatorNonce)-1]
+	}
+	if len(joinerNonce) > 0 {
+		last ^= joinerNonce[len(joinerNonce)-1]
+	}
+	return int(last & 1)
+}
@@ -0,0 +1,57 @@
+package coinflip
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCommitAndVerify(t *testing.T) {
+	nonce := bytes.Repeat([]byte{0xAB}, 32)
+	commitment := Commit(nonce)
+
+	if err := Verify(commitment, nonce); err != nil {
+		t.Errorf("Verificación inesperadamente fallida: %v", err)
+	}
+
+	other := bytes.Repeat([]byte{0xAC}, 32)
+	if err := Verify(commitment, other); err != ErrMismatch {
+		t.Errorf("Se esperaba ErrMismatch, se obtuvo %v", err)
+	}
+}
+
+func TestParseNonce(t *testing.T) {
+	if _, err := ParseNonce("zz"); err != ErrInvalidNonce {
+		t.Error("Se esperaba error para un nonce no hexadecimal")
+	}
+	if _, err := ParseNonce(hex.EncodeToString([]byte{1, 2, 3})); err != ErrInvalidNonce {
+		t.Error("Se esperaba error para un nonce demasiado corto")
+	}
+	if _, err := ParseNonce(hex.EncodeToString(bytes.Repeat([]byte{1}, 16))); err != nil {
+		t.Errorf("Error inesperado: %v", err)
+	}
+}
+
+func TestNormalizeCommitment(t *testing.T) {
+	commitment := Commit([]byte("nonce"))
+	normalized, err := NormalizeCommitment(strings.ToUpper(commitment))
+	if err != nil || normalized != commitment {
+		t.Errorf("Compromiso válido rechazado: %v", err)
+	}
+	if _, err := NormalizeCommitment("abcd"); err != ErrInvalidCommitment {
+		t.Error("Se esperaba error para un compromiso corto")
+	}
+}
+
+func TestDecide(t *testing.T) {
+	a := []byte{0x00, 0x01}
+	b := []byte{0x00, 0x00}
+
+	if Decide(a, b) != 1 {
+		t.Error("0x01 XOR 0x00 debería dar 1")
+	}
+	if Decide(a, a) != 0 {
+		t.Error("Un valor XOR sí mismo debería dar 0")
+	}
+}
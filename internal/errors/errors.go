@@ -20,6 +20,30 @@ const (
 	ErrorUnknownMessageType = "ERROR_UNKNOWN_MESSAGE_TYPE"
 	ErrorMessageTooLarge    = "ERROR_MESSAGE_TOO_LARGE"
 	ErrorServerCapacity     = "ERROR_SERVER_CAPACITY"
+	ErrorSpectatorsDisabled = "ERROR_SPECTATORS_NOT_ALLOWED"
+	ErrorSpectatorsFull     = "ERROR_SPECTATORS_FULL"
+	ErrorNotHost            = "ERROR_NOT_HOST"
+	ErrorRoomLocked         = "ERROR_ROOM_LOCKED"
+	ErrorKicked             = "ERROR_KICKED"
+	ErrorInvalidTarget      = "ERROR_INVALID_TARGET"
+	ErrorGameNotStarted     = "ERROR_GAME_NOT_STARTED"
+	ErrorNoReadyCheck       = "ERROR_NO_READY_CHECK"
+	ErrorInvalidCoinFlip    = "ERROR_INVALID_COIN_FLIP"
+	ErrorInvalidSession     = "ERROR_INVALID_SESSION"
+	ErrorGamePaused         = "ERROR_GAME_PAUSED"
+	ErrorInvalidPause       = "ERROR_INVALID_PAUSE"
+	ErrorGameNotFound       = "ERROR_GAME_NOT_FOUND"
+	ErrorAlreadyInQueue     = "ERROR_ALREADY_IN_QUEUE"
+	ErrorNotInQueue         = "ERROR_NOT_IN_QUEUE"
+	ErrorSeasonNotFound     = "ERROR_SEASON_NOT_FOUND"
+	ErrorTournamentNotFound = "ERROR_TOURNAMENT_NOT_FOUND"
+	ErrorNotOrganizer       = "ERROR_NOT_ORGANIZER"
+	ErrorInvalidTournament  = "ERROR_INVALID_TOURNAMENT_ACTION"
+	ErrorPlayerNotFound     = "ERROR_PLAYER_NOT_FOUND"
+	ErrorPlayerBusy         = "ERROR_PLAYER_BUSY"
+	ErrorChallengeNotFound  = "ERROR_CHALLENGE_NOT_FOUND"
+	ErrorNameTaken          = "ERROR_NAME_TAKEN"
+	ErrorInvalidFriend      = "ERROR_INVALID_FRIEND_ACTION"
 )
 
 // SendError sends a structured error message to the client
@@ -116,3 +140,123 @@ func MessageTooLarge(channel chan []byte, clientID string) {
 func ServerCapacity(ch chan []byte, clientID string) {
 	SendError(ch, ErrorServerCapacity, "El servidor está a capacidad máxima. Intente más tarde.", clientID)
 }
+
+// SpectatorsNotAllowed envía un error cuando la sala no admite espectadores
+func SpectatorsNotAllowed(channel chan []byte, clientID string) {
+	SendError(channel, ErrorSpectatorsDisabled, "La sala no admite espectadores", clientID)
+}
+
+// SpectatorsFull envía un error cuando la sala alcanzó su límite de espectadores
+func SpectatorsFull(channel chan []byte, clientID string) {
+	SendError(channel, ErrorSpectatorsFull, "La sala alcanzó el máximo de espectadores", clientID)
+}
+
+// NotHost envía un error cuando un cliente que no es anfitrión intenta una acción de anfitrión
+func NotHost(channel chan []byte, clientID string) {
+	SendError(channel, ErrorNotHost, "Solo el anfitrión de la sala puede realizar esta acción", clientID)
+}
+
+// RoomLocked envía un error cuando la sala está bloqueada por el anfitrión
+func RoomLocked(channel chan []byte, clientID string) {
+	SendError(channel, ErrorRoomLocked, "La sala está bloqueada", clientID)
+}
+
+// Kicked envía un error cuando un cliente expulsado intenta volver a la sala
+func Kicked(channel chan []byte, clientID string) {
+	SendError(channel, ErrorKicked, "Fuiste expulsado de esta sala", clientID)
+}
+
+// InvalidTarget envía un error cuando el objetivo de una acción de anfitrión no es válido
+func InvalidTarget(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidTarget, message, clientID)
+}
+
+// GameNotStarted envía un error cuando se intenta mover antes de que empiece la partida
+func GameNotStarted(channel chan []byte, clientID string) {
+	SendError(channel, ErrorGameNotStarted, "La partida todavía no ha comenzado", clientID)
+}
+
+// NoReadyCheck envía un error cuando se confirma sin un ready-check en curso
+func NoReadyCheck(channel chan []byte, clientID string) {
+	SendError(channel, ErrorNoReadyCheck, "No hay ninguna confirmación de inicio pendiente", clientID)
+}
+
+// InvalidCoinFlip envía un error cuando un mensaje del sorteo no corresponde a su fase o no es válido
+func InvalidCoinFlip(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidCoinFlip, message, clientID)
+}
+
+// InvalidSession envía un error cuando no se puede reanudar una sesión
+func InvalidSession(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidSession, message, clientID)
+}
+
+// GamePaused envía un error cuando se intenta mover con la partida en pausa
+func GamePaused(channel chan []byte, clientID string) {
+	SendError(channel, ErrorGamePaused, "La partida está en pausa", clientID)
+}
+
+// InvalidPause envía un error cuando una solicitud de pausa o reanudación no es válida
+func InvalidPause(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidPause, message, clientID)
+}
+
+// GameNotFound envía un error cuando se pide la repetición de una partida que no está archivada
+func GameNotFound(channel chan []byte, clientID string) {
+	SendError(channel, ErrorGameNotFound, "La partida solicitada no existe", clientID)
+}
+
+// AlreadyInQueue envía un error cuando se pide FIND_MATCH estando ya en la cola
+func AlreadyInQueue(channel chan []byte, clientID string) {
+	SendError(channel, ErrorAlreadyInQueue, "Ya estás buscando partida", clientID)
+}
+
+// NotInQueue envía un error cuando se pide CANCEL_MATCH sin estar en la cola
+func NotInQueue(channel chan []byte, clientID string) {
+	SendError(channel, ErrorNotInQueue, "No estás buscando partida", clientID)
+}
+
+// SeasonNotFound envía un error cuando se pide la clasificación de una temporada que no existe
+func SeasonNotFound(channel chan []byte, clientID string) {
+	SendError(channel, ErrorSeasonNotFound, "La temporada solicitada no existe", clientID)
+}
+
+// TournamentNotFound envía un error cuando se pide una acción sobre un torneo que no existe
+func TournamentNotFound(channel chan []byte, clientID string) {
+	SendError(channel, ErrorTournamentNotFound, "El torneo solicitado no existe", clientID)
+}
+
+// NotOrganizer envía un error cuando alguien que no organiza el torneo intenta empezarlo
+func NotOrganizer(channel chan []byte, clientID string) {
+	SendError(channel, ErrorNotOrganizer, "Solo el organizador puede hacer esto", clientID)
+}
+
+// InvalidTournamentAction envía un error cuando una acción de torneo no es posible en su estado actual
+func InvalidTournamentAction(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidTournament, message, clientID)
+}
+
+// PlayerNotFound envía un error cuando el jugador buscado no está conectado
+func PlayerNotFound(channel chan []byte, clientID string) {
+	SendError(channel, ErrorPlayerNotFound, "El jugador no está conectado", clientID)
+}
+
+// PlayerBusy envía un error cuando un reto no es posible porque uno de los jugadores está ocupado
+func PlayerBusy(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorPlayerBusy, message, clientID)
+}
+
+// ChallengeNotFound envía un error cuando se responde a un reto que no existe o ya no está pendiente
+func ChallengeNotFound(channel chan []byte, clientID string) {
+	SendError(channel, ErrorChallengeNotFound, "El reto no existe o ya no está pendiente", clientID)
+}
+
+// NameTaken envía un error cuando el nombre visible pedido ya lo usa otro jugador
+func NameTaken(channel chan []byte, clientID string) {
+	SendError(channel, ErrorNameTaken, "Ese nombre ya está en uso", clientID)
+}
+
+// InvalidFriendAction envía un error cuando no se puede añadir o quitar un amigo
+func InvalidFriendAction(channel chan []byte, message string, clientID string) {
+	SendError(channel, ErrorInvalidFriend, message, clientID)
+}
@@ -0,0 +1,230 @@
+// Package friends guarda las listas de amigos de los jugadores. Añadir a alguien es
+// unilateral; la amistad es mutua cuando los dos se han añadido, y solo entonces se
+// comparte la presencia
+package friends
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+// MaxFriends limita el tamaño de la lista de amigos de un jugador
+const MaxFriends = 200
+
+// documentName es el nombre del documento de amigos en el almacén
+const documentName = "friends"
+
+// Errores de las listas de amigos
+var (
+	ErrSelf           = errors.New("no puedes añadirte a ti mismo")
+	ErrAlreadyFriend  = errors.New("ya está en tu lista de amigos")
+	ErrNotFriend      = errors.New("no está en tu lista de amigos")
+	ErrTooManyFriends = errors.New("la lista de amigos está llena")
+)
+
+// Book guarda las listas de amigos. Se cargan del almacén al crearlo. Es seguro para uso
+// concurrente
+type Book struct {
+	store *store.FileStore // nil: solo memoria
+
+	mu        sync.RWMutex
+	lists     map[string]map[string]bool // jugador -> a quién ha añadido
+	followers map[string]map[string]bool // jugador -> quién lo ha añadido
+
+	saveMu sync.Mutex // Serializa las escrituras para que la última gane
+}
+
+// NewBook crea el registro de amigos y recupera las listas guardadas. s puede ser nil para
+// guardar solo en memoria
+func NewBook(s *store.FileStore) (*Book, error) {
+	b := &Book{
+		store:     s,
+		lists:     make(map[string]map[string]bool),
+		followers: make(map[string]map[string]bool),
+	}
+	if s == nil {
+		return b, nil
+	}
+
+	saved := make(map[string][]string)
+	if _, err := s.Load(documentName, &saved); err != nil {
+		return nil, err
+	}
+	for playerID, friendIDs := range saved {
+		for _, friendID := range friendIDs {
+			b.link(playerID, friendID)
+		}
+	}
+	return b, nil
+}
+
+// Add añade friendID a la lista de playerID
+func (b *Book) Add(playerID, friendID string) error {
+	if playerID == friendID {
+		return ErrSelf
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.lists[playerID][friendID] {
+		return ErrAlreadyFriend
+	}
+	if len(b.lists[playerID]) >= MaxFriends {
+		return ErrTooManyFriends
+	}
+	b.link(playerID, friendID)
+	return nil
+}
+
+// Remove quita friendID de la lista de playerID. La lista del otro jugador no cambia
+func (b *Book) Remove(playerID, friendID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.lists[playerID][friendID] {
+		return ErrNotFriend
+	}
+	delete(b.lists[playerID], friendID)
+	delete(b.followers[friendID], playerID)
+	if len(b.lists[playerID]) == 0 {
+		delete(b.lists, playerID)
+	}
+	if len(b.followers[friendID]) == 0 {
+		delete(b.followers, friendID)
+	}
+	return nil
+}
+
+// Friends devuelve, ordenados, los jugadores que playerID ha añadido
+func (b *Book) Friends(playerID string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedKeys(b.lists[playerID])
+}
+
+// Requests devuelve, ordenados, los jugadores que han añadido a playerID sin que este los
+// haya añadido
+func (b *Book) Requests(playerID string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var requests []string
+	for followerID := range b.followers[playerID] {
+		if !b.lists[playerID][followerID] {
+			requests = append(requests, followerID)
+		}
+	}
+	sort.Strings(requests)
+	return requests
+}
+
+// Mutual indica si los dos jugadores se han añadido mutuamente
+func (b *Book) Mutual(a, c string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lists[a][c] && b.lists[c][a]
+}
+
+// MutualFriends devuelve, ordenados, los amigos mutuos de playerID
+func (b *Book) MutualFriends(playerID string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var mutual []string
+	for friendID := range b.lists[playerID] {
+		if b.lists[friendID][playerID] {
+			mutual = append(mutual, friendID)
+		}
+	}
+	sort.Strings(mutual)
+	return mutual
+}
+
+// Players devuelve, ordenados, los jugadores que aparecen en alguna lista: los que han
+// añadido a alguien y los añadidos
+func (b *Book) Players() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	players := make(map[string]bool, len(b.lists)+len(b.followers))
+	for playerID := range b.lists {
+		players[playerID] = true
+	}
+	for playerID := range b.followers {
+		players[playerID] = true
+	}
+	return sortedKeys(players)
+}
+
+// Forget borra la lista de cada jugador indicado y lo quita de las listas de los demás.
+// Devuelve true si algo ha cambiado
+func (b *Book) Forget(playerIDs []string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	changed := false
+	for _, playerID := range playerIDs {
+		for friendID := range b.lists[playerID] {
+			delete(b.followers[friendID], playerID)
+			if len(b.followers[friendID]) == 0 {
+				delete(b.followers, friendID)
+			}
+			changed = true
+		}
+		for followerID := range b.followers[playerID] {
+			delete(b.lists[followerID], playerID)
+			if len(b.lists[followerID]) == 0 {
+				delete(b.lists, followerID)
+			}
+			changed = true
+		}
+		delete(b.lists, playerID)
+		delete(b.followers, playerID)
+	}
+	return changed
+}
+
+// Save escribe en disco las listas actuales
+func (b *Book) Save() error {
+	if b.store == nil {
+		return nil
+	}
+
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	b.mu.RLock()
+	saved := make(map[string][]string, len(b.lists))
+	for playerID, friendIDs := range b.lists {
+		saved[playerID] = sortedKeys(friendIDs)
+	}
+	b.mu.RUnlock()
+
+	return b.store.Save(documentName, saved)
+}
+
+// link añade la relación en los dos índices. Se llama con b.mu tomado o al cargar
+func (b *Book) link(playerID, friendID string) {
+	if b.lists[playerID] == nil {
+		b.lists[playerID] = make(map[string]bool)
+	}
+	if b.followers[friendID] == nil {
+		b.followers[friendID] = make(map[string]bool)
+	}
+	b.lists[playerID][friendID] = true
+	b.followers[friendID][playerID] = true
+}
+
+// sortedKeys devuelve las claves de un conjunto, ordenadas
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
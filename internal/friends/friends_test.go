@@ -0,0 +1,105 @@
+package friends
+
+import (
+	"fmt"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+// TestMutualFriends verifica que la amistad sea mutua solo cuando los dos se han añadido
+// y que quien añade sin ser correspondido aparezca como solicitud
+func TestMutualFriends(t *testing.T) {
+	b, _ := NewBook(nil)
+
+	if err := b.Add("ana", "ana"); err != ErrSelf {
+		t.Errorf("Se esperaba ErrSelf, obtenido %v", err)
+	}
+	b.Add("ana", "bea")
+	if err := b.Add("ana", "bea"); err != ErrAlreadyFriend {
+		t.Errorf("Se esperaba ErrAlreadyFriend, obtenido %v", err)
+	}
+	if b.Mutual("ana", "bea") || len(b.MutualFriends("ana")) != 0 {
+		t.Error("Una amistad sin corresponder no es mutua")
+	}
+	if requests := b.Requests("bea"); fmt.Sprint(requests) != "[ana]" {
+		t.Errorf("bea debería tener la solicitud de ana: %v", requests)
+	}
+
+	b.Add("bea", "ana")
+	if !b.Mutual("bea", "ana") || fmt.Sprint(b.MutualFriends("ana")) != "[bea]" || len(b.Requests("bea")) != 0 {
+		t.Error("ana y bea deberían ser amigas mutuas")
+	}
+
+	if err := b.Remove("ana", "bea"); err != nil {
+		t.Fatalf("Error quitando amiga: %v", err)
+	}
+	if err := b.Remove("ana", "bea"); err != ErrNotFriend {
+		t.Errorf("Se esperaba ErrNotFriend, obtenido %v", err)
+	}
+	// bea conserva a ana en su lista, que pasa a ser una solicitud para ana
+	if b.Mutual("ana", "bea") || fmt.Sprint(b.Friends("bea")) != "[ana]" || fmt.Sprint(b.Requests("ana")) != "[bea]" {
+		t.Error("Quitar a una amiga solo cambia la propia lista")
+	}
+}
+
+// TestFriendLimit verifica el límite de la lista de amigos
+func TestFriendLimit(t *testing.T) {
+	b, _ := NewBook(nil)
+	for i := 0; i < MaxFriends; i++ {
+		if err := b.Add("ana", fmt.Sprintf("p%d", i)); err != nil {
+			t.Fatalf("Error añadiendo amigo %d: %v", i, err)
+		}
+	}
+	if err := b.Add("ana", "otro"); err != ErrTooManyFriends {
+		t.Errorf("Se esperaba ErrTooManyFriends, obtenido %v", err)
+	}
+}
+
+// TestForget verifica que olvidar a un jugador borre su lista y lo quite de las ajenas
+func TestForget(t *testing.T) {
+	b, _ := NewBook(nil)
+	b.Add("ana", "bea")
+	b.Add("bea", "ana")
+	b.Add("carl", "ana")
+	b.Add("carl", "dan")
+
+	if fmt.Sprint(b.Players()) != "[ana bea carl dan]" {
+		t.Errorf("Jugadores incorrectos: %v", b.Players())
+	}
+	if !b.Forget([]string{"ana"}) {
+		t.Fatal("Olvidar a ana debería cambiar las listas")
+	}
+	if len(b.Friends("ana")) != 0 || len(b.Friends("bea")) != 0 || fmt.Sprint(b.Friends("carl")) != "[dan]" {
+		t.Errorf("ana debería desaparecer de todas las listas: bea %v, carl %v", b.Friends("bea"), b.Friends("carl"))
+	}
+	if fmt.Sprint(b.Players()) != "[carl dan]" {
+		t.Errorf("Jugadores incorrectos tras olvidar: %v", b.Players())
+	}
+	if b.Forget([]string{"ana", "erik"}) {
+		t.Error("Olvidar a jugadores sin listas no debería cambiar nada")
+	}
+}
+
+// TestBookPersistence verifica que las listas y las solicitudes se recuperen del almacén
+func TestBookPersistence(t *testing.T) {
+	s, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error abriendo almacén: %v", err)
+	}
+	b, _ := NewBook(s)
+	b.Add("ana", "bea")
+	b.Add("bea", "ana")
+	b.Add("ana", "carla")
+	if err := b.Save(); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	restored, err := NewBook(s)
+	if err != nil {
+		t.Fatalf("Error cargando: %v", err)
+	}
+	if !restored.Mutual("ana", "bea") || fmt.Sprint(restored.Friends("ana")) != "[bea carla]" || fmt.Sprint(restored.Requests("carla")) != "[ana]" {
+		t.Errorf("Listas no recuperadas: %v, %v", restored.Friends("ana"), restored.Requests("carla"))
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import "time"
+
+// Clock lleva el tiempo restante de cada jugador en partidas con control de tiempo
+type Clock struct {
+	Remaining map[string]time.Duration // Tiempo restante por símbolo
+	Increment time.Duration            // Tiempo añadido tras cada jugada
+	Running   string                   // Símbolo cuyo reloj está corriendo, vacío si está detenido
+	StartedAt time.Time                // Momento en que arrancó el reloj en marcha
+}
+
+// NewClock crea un reloj con el mismo tiempo inicial para X y O
+func NewClock(initial, increment time.Duration) *Clock {
+	return &Clock{
+		Remaining: map[string]time.Duration{
+			"X": initial,
+			"O": initial,
+		},
+		Increment: increment,
+	}
+}
+
+// Start pone en marcha el reloj del símbolo indicado
+func (c *Clock) Start(symbol string, now time.Time) {
+	c.Stop(now)
+	c.Running = symbol
+	c.StartedAt = now
+}
+
+// Stop detiene el reloj en marcha descontando el tiempo consumido
+func (c *Clock) Stop(now time.Time) {
+	if c.Running == "" {
+		return
+	}
+
+	c.Remaining[c.Running] = c.RemainingFor(c.Running, now)
+	c.Running = ""
+	c.StartedAt = time.Time{}
+}
+
+// Switch detiene el reloj del jugador que acaba de mover, le suma el incremento
+// y arranca el reloj de next
+func (c *Clock) Switch(next string, now time.Time) {
+	if c.Running != "" {
+		moved := c.Running
+		c.Stop(now)
+		c.Remaining[moved] += c.Increment
+	}
+	c.Start(next, now)
+}
+
+// RemainingFor devuelve el tiempo restante del símbolo en el instante now
+func (c *Clock) RemainingFor(symbol string, now time.Time) time.Duration {
+	remaining := c.Remaining[symbol]
+	if symbol == c.Running {
+		remaining -= now.Sub(c.StartedAt)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// Flagged indica si al jugador en marcha se le acabó el tiempo
+func (c *Clock) Flagged(now time.Time) (string, bool) {
+	if c.Running == "" {
+		return "", false
+	}
+	if c.RemainingFor(c.Running, now) > 0 {
+		return "", false
+	}
+	return c.Running, true
+}
+
+// Millis devuelve el tiempo restante de cada símbolo en milisegundos
+func (c *Clock) Millis(now time.Time) map[string]int64 {
+	millis := make(map[string]int64, len(c.Remaining))
+	for symbol := range c.Remaining {
+		millis[symbol] = c.RemainingFor(symbol, now).Milliseconds()
+	}
+	return millis
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Now()
+	clock := NewClock(60*time.Second, 2*time.Second)
+
+	clock.Start("X", start)
+
+	// X piensa 10 segundos y mueve
+	clock.Switch("O", start.Add(10*time.Second))
+
+	if got := clock.RemainingFor("X", start.Add(10*time.Second)); got != 52*time.Second {
+		t.Errorf("Tiempo restante de X incorrecto, esperado 52s, obtenido %v", got)
+	}
+	if clock.Running != "O" {
+		t.Errorf("Reloj en marcha incorrecto, esperado 'O', obtenido '%s'", clock.Running)
+	}
+
+	// El reloj detenido no debe consumir tiempo
+	clock.Stop(start.Add(15 * time.Second))
+	if got := clock.RemainingFor("O", start.Add(time.Hour)); got != 55*time.Second {
+		t.Errorf("Tiempo restante de O incorrecto, esperado 55s, obtenido %v", got)
+	}
+}
+
+func TestClockFlagged(t *testing.T) {
+	start := time.Now()
+	clock := NewClock(5*time.Second, 0)
+	clock.Start("O", start)
+
+	if _, flagged := clock.Flagged(start.Add(4 * time.Second)); flagged {
+		t.Error("El reloj no debería haber caído todavía")
+	}
+
+	symbol, flagged := clock.Flagged(start.Add(6 * time.Second))
+	if !flagged || symbol != "O" {
+		t.Errorf("Se esperaba que cayera el reloj de 'O', obtenido '%s' (%v)", symbol, flagged)
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"fmt"
 )
 
-// Board representa un tablero de 3x3 para el juego
-type Board [3][3]string
+// Board representa el tablero del juego como una matriz cuadrada (3x3 en la variante clásica)
+type Board [][]string
 
 // GameState contiene el estado completo del juego
 type GameState struct {
 	Board             Board             // Tablero actual
+	WinLength         int               // Fichas consecutivas necesarias para ganar
 	CurrentTurnSymbol string            // Símbolo del jugador actual ("X" o "O")
 	PlayerSymbols     map[string]string // Mapa de ID de cliente a símbolo
 	Winner            string            // Símbolo del ganador, vacío si no hay ganador
@@ -18,10 +19,17 @@ type GameState struct {
 	IsDraw            bool              // Indica si el juego terminó en empate
 }
 
-// NewGameState crea un nuevo estado de juego inicializado
+// NewGameState crea un nuevo estado de juego inicializado con el tablero clásico de 3x3
 func NewGameState() *GameState {
+	return NewGameStateWithSize(ClassicBoardSize, ClassicBoardSize)
+}
+
+// NewGameStateWithSize crea un estado de juego con un tablero de size x size
+// en el que se necesitan winLength fichas en línea para ganar
+func NewGameStateWithSize(size, winLength int) *GameState {
 	return &GameState{
-		Board:             Board{},                 // Tablero vacío
+		Board:             NewBoard(size),          // Tablero vacío
+		WinLength:         winLength,               // Fichas en línea para ganar
 		CurrentTurnSymbol: "X",                     // X siempre comienza
 		PlayerSymbols:     make(map[string]string), // Mapa vacío de jugadores
 		Winner:            "",                      // Sin ganador inicial
@@ -30,6 +38,29 @@ func NewGameState() *GameState {
 	}
 }
 
+// NewBoard crea un tablero vacío de size x size
+func NewBoard(size int) Board {
+	board := make(Board, size)
+	for i := range board {
+		board[i] = make([]string, size)
+	}
+	return board
+}
+
+// Size devuelve la dimensión del tablero
+func (b Board) Size() int {
+	return len(b)
+}
+
+// Copy devuelve una copia independiente del tablero
+func (b Board) Copy() Board {
+	board := make(Board, len(b))
+	for i := range b {
+		board[i] = append([]string(nil), b[i]...)
+	}
+	return board
+}
+
 // ApplyMove aplica un movimiento al estado del juego
 func ApplyMove(gs *GameState, playerSymbol string, row, col int) error {
 	// Verificar si el juego ya terminó
@@ -43,7 +74,8 @@ func ApplyMove(gs *GameState, playerSymbol string, row, col int) error {
 	}
 
 	// Verificar si la posición está dentro del tablero
-	if row < 0 || row > 2 || col < 0 || col > 2 {
+	size := gs.Board.Size()
+	if row < 0 || row >= size || col < 0 || col >= size {
 		return errors.New("posición fuera del tablero")
 	}
 
@@ -66,57 +98,65 @@ func ApplyMove(gs *GameState, playerSymbol string, row, col int) error {
 		gs.IsGameOver = true
 	} else {
 		// Cambiar el turno al otro jugador
-		if gs.CurrentTurnSymbol == "X" {
-			gs.CurrentTurnSymbol = "O"
-		} else {
-			gs.CurrentTurnSymbol = "X"
-		}
+		gs.CurrentTurnSymbol = OppositeSymbol(gs.CurrentTurnSymbol)
 	}
 
 	return nil
 }
 
+// OppositeSymbol devuelve el símbolo del rival
+func OppositeSymbol(symbol string) string {
+	if symbol == "X" {
+		return "O"
+	}
+	return "X"
+}
+
 // CheckWin verifica si hay un ganador o empate
 func CheckWin(gs *GameState) (winnerSymbol string, isDraw bool) {
 	board := gs.Board
+	size := board.Size()
 
-	// Comprobar filas
-	for i := 0; i < 3; i++ {
-		if board[i][0] != "" && board[i][0] == board[i][1] && board[i][1] == board[i][2] {
-			return board[i][0], false
-		}
+	winLength := gs.WinLength
+	if winLength <= 0 || winLength > size {
+		winLength = size
 	}
 
-	// Comprobar columnas
-	for i := 0; i < 3; i++ {
-		if board[0][i] != "" && board[0][i] == board[1][i] && board[1][i] == board[2][i] {
-			return board[0][i], false
-		}
-	}
+	// Direcciones a comprobar desde cada casilla: fila, columna,
+	// diagonal principal y diagonal secundaria
+	directions := [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
 
-	// Comprobar diagonal principal
-	if board[0][0] != "" && board[0][0] == board[1][1] && board[1][1] == board[2][2] {
-		return board[0][0], false
-	}
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			symbol := board[row][col]
+			if symbol == "" {
+				continue
+			}
 
-	// Comprobar diagonal secundaria
-	if board[0][2] != "" && board[0][2] == board[1][1] && board[1][1] == board[2][0] {
-		return board[0][2], false
+			for _, d := range directions {
+				count := 1
+				r, c := row+d[0], col+d[1]
+				for count < winLength && r >= 0 && r < size && c >= 0 && c < size && board[r][c] == symbol {
+					count++
+					r += d[0]
+					c += d[1]
+				}
+
+				if count == winLength {
+					return symbol, false
+				}
+			}
+		}
 	}
 
 	// Comprobar empate (si no hay casillas vacías)
-	isDraw = true
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if board[i][j] == "" {
-				isDraw = false
-				break
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			if board[row][col] == "" {
+				return "", false
 			}
 		}
-		if !isDraw {
-			break
-		}
 	}
 
-	return "", isDraw
+	return "", true
 }
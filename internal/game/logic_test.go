@@ -248,3 +248,57 @@ func TestGameComplete(t *testing.T) {
 		t.Error("El juego no debería ser empate")
 	}
 }
+
+func TestLargerBoards(t *testing.T) {
+	t.Run("Victoria con cinco en línea", func(t *testing.T) {
+		gs := NewGameStateWithSize(15, 5)
+		for i := 0; i < 5; i++ {
+			gs.Board[3+i][7+i] = "O"
+		}
+
+		winner, isDraw := CheckWin(gs)
+
+		if winner != "O" {
+			t.Errorf("Se esperaba ganador 'O', se obtuvo '%s'", winner)
+		}
+		if isDraw {
+			t.Error("No debería indicar empate en una victoria")
+		}
+	})
+
+	t.Run("Cuatro en línea no basta en gomoku", func(t *testing.T) {
+		gs := NewGameStateWithSize(15, 5)
+		for i := 0; i < 4; i++ {
+			gs.Board[10][i] = "X"
+		}
+
+		winner, _ := CheckWin(gs)
+
+		if winner != "" {
+			t.Errorf("No debería haber ganador, se obtuvo '%s'", winner)
+		}
+	})
+
+	t.Run("Diagonal secundaria en tablero mediano", func(t *testing.T) {
+		gs := NewGameStateWithSize(6, 4)
+		for i := 0; i < 4; i++ {
+			gs.Board[i][5-i] = "X"
+		}
+
+		winner, _ := CheckWin(gs)
+
+		if winner != "X" {
+			t.Errorf("Se esperaba ganador 'X', se obtuvo '%s'", winner)
+		}
+	})
+
+	t.Run("Movimiento fuera de un tablero grande", func(t *testing.T) {
+		gs := NewGameStateWithSize(9, 5)
+		if err := ApplyMove(gs, "X", 8, 8); err != nil {
+			t.Errorf("Error inesperado en la última casilla: %v", err)
+		}
+		if err := ApplyMove(gs, "O", 9, 0); err == nil {
+			t.Error("Se esperaba error por movimiento fuera del tablero")
+		}
+	})
+}
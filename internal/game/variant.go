@@ -0,0 +1,60 @@
+package game
+
+const (
+	// ClassicBoardSize es el tamaño del tablero del tres en raya tradicional
+	ClassicBoardSize = 3
+
+	// VariantClassic es el tres en raya tradicional de 3x3
+	VariantClassic = "classic"
+
+	// VariantFourInARow requiere cuatro fichas en línea en tableros medianos
+	VariantFourInARow = "four-in-a-row"
+
+	// VariantGomoku requiere cinco fichas en línea en tableros grandes
+	VariantGomoku = "gomoku"
+)
+
+// Variant describe las reglas de una variante del juego
+type Variant struct {
+	Name        string // Nombre de la variante
+	DefaultSize int    // Tamaño de tablero por defecto
+	MinSize     int    // Tamaño mínimo permitido
+	MaxSize     int    // Tamaño máximo permitido
+	WinLength   int    // Fichas consecutivas necesarias para ganar
+}
+
+// variants contiene las variantes soportadas por el servidor
+var variants = map[string]Variant{
+	VariantClassic: {
+		Name:        VariantClassic,
+		DefaultSize: ClassicBoardSize,
+		MinSize:     ClassicBoardSize,
+		MaxSize:     ClassicBoardSize,
+		WinLength:   3,
+	},
+	VariantFourInARow: {
+		Name:        VariantFourInARow,
+		DefaultSize: 6,
+		MinSize:     4,
+		MaxSize:     8,
+		WinLength:   4,
+	},
+	VariantGomoku: {
+		Name:        VariantGomoku,
+		DefaultSize: 15,
+		MinSize:     9,
+		MaxSize:     19,
+		WinLength:   5,
+	},
+}
+
+// LookupVariant devuelve la variante con el nombre indicado
+func LookupVariant(name string) (Variant, bool) {
+	v, ok := variants[name]
+	return v, ok
+}
+
+// VariantNames devuelve los nombres de las variantes soportadas
+func VariantNames() []string {
+	return []string{VariantClassic, VariantFourInARow, VariantGomoku}
+}
@@ -0,0 +1,295 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/challenge"
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// challengeCheckInterval es cada cuánto se buscan retos caducados
+const challengeCheckInterval = time.Second
+
+// Motivos por los que un reto deja de estar pendiente (CHALLENGE_DECLINED y CHALLENGE_CANCELLED)
+const (
+	challengeDeclined     = "declined"
+	challengeWithdrawn    = "withdrawn"
+	challengeExpired      = "expired"
+	challengeDisconnected = "disconnected"
+	challengeBusy         = "busy"
+)
+
+// ChallengeRequest representa un mensaje de reto de un cliente (CHALLENGE_PLAYER,
+// ACCEPT_CHALLENGE o DECLINE_CHALLENGE)
+type ChallengeRequest struct {
+	Client      interfaces.Client
+	Type        string
+	TargetID    string              // Solo en CHALLENGE_PLAYER
+	Settings    models.RoomSettings // Solo en CHALLENGE_PLAYER, ya validada
+	ChallengeID string              // En ACCEPT_CHALLENGE y DECLINE_CHALLENGE
+}
+
+// SetChallenges configura cuánto espera un reto a que el retado responda. Debe llamarse
+// antes de Run
+func (h *Hub) SetChallenges(timeout time.Duration) {
+	h.challenges = challenge.NewRegistry(timeout)
+}
+
+// ChallengePlayer pide al Hub que rete a un jugador conectado (mensaje CHALLENGE_PLAYER).
+// La configuración debe venir ya validada
+func (h *Hub) ChallengePlayer(client interfaces.Client, targetID string, settings models.RoomSettings) {
+	h.ChallengeChan <- &ChallengeRequest{
+		Client:   client,
+		Type:     "CHALLENGE_PLAYER",
+		TargetID: targetID,
+		Settings: settings,
+	}
+}
+
+// ChallengeAction pide al Hub que acepte o rechace un reto (ACCEPT_CHALLENGE o DECLINE_CHALLENGE)
+func (h *Hub) ChallengeAction(client interfaces.Client, action string, challengeID string) {
+	h.ChallengeChan <- &ChallengeRequest{
+		Client:      client,
+		Type:        action,
+		ChallengeID: challengeID,
+	}
+}
+
+// handleChallengeRequest atiende los mensajes de reto. Solo se llama desde Run
+func (h *Hub) handleChallengeRequest(req *ChallengeRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	if req.Type == "CHALLENGE_PLAYER" {
+		h.createChallenge(client, req.TargetID, req.Settings)
+		return
+	}
+
+	c := h.challenges.Get(req.ChallengeID)
+	if c == nil {
+		errors.ChallengeNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	switch req.Type {
+	case "ACCEPT_CHALLENGE":
+		if client != c.Target {
+			errors.ChallengeNotFound(client.GetSendChannel(), client.GetID())
+			return
+		}
+		h.acceptChallenge(c)
+
+	case "DECLINE_CHALLENGE":
+		// El retado lo rechaza; el retador, en cambio, lo retira
+		switch client {
+		case c.Target:
+			h.closeChallenge(c, "CHALLENGE_DECLINED", challengeDeclined)
+		case c.Challenger:
+			h.closeChallenge(c, "CHALLENGE_CANCELLED", challengeWithdrawn)
+		default:
+			errors.ChallengeNotFound(client.GetSendChannel(), client.GetID())
+		}
+	}
+}
+
+// createChallenge envía un reto al jugador indicado si los dos están libres
+func (h *Hub) createChallenge(client interfaces.Client, targetID string, settings models.RoomSettings) {
+	if targetID == client.GetID() {
+		errors.InvalidTarget(client.GetSendChannel(), "No puedes retarte a ti mismo", client.GetID())
+		return
+	}
+	target := h.clientByID(targetID)
+	if target == nil {
+		errors.PlayerNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+	if h.findActiveSeat(client.GetID()) != nil {
+		errors.PlayerBusy(client.GetSendChannel(), "No puedes retar a nadie mientras juegas en una sala", client.GetID())
+		return
+	}
+	if h.findActiveSeat(targetID) != nil {
+		errors.PlayerBusy(client.GetSendChannel(), "El jugador está jugando en una sala", client.GetID())
+		return
+	}
+
+	// Las partidas por reto no aparecen en LIST_ROOMS
+	settings.Visibility = room.VisibilityPrivate
+	c, err := h.challenges.Create(client, target, settings, time.Now())
+	switch {
+	case stderrors.Is(err, challenge.ErrDuplicate):
+		errors.PlayerBusy(client.GetSendChannel(), "Ya tienes un reto pendiente con este jugador", client.GetID())
+		return
+	case stderrors.Is(err, challenge.ErrTooMany):
+		errors.PlayerBusy(client.GetSendChannel(), "Tienes demasiados retos pendientes", client.GetID())
+		return
+	case err != nil:
+		errors.Internal(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	challengeMsg := models.ChallengeResponse{
+		Type:              "CHALLENGE_RECEIVED",
+		ChallengeID:       c.ID,
+		ChallengerID:      client.GetID(),
+		TargetID:          targetID,
+		ChallengerProfile: h.playerProfile(client.GetID()),
+		TargetProfile:     h.playerProfile(targetID),
+		Settings:          settings,
+		ExpiresAt:         c.ExpiresAt.UnixMilli(),
+	}
+	msgBytes, _ := json.Marshal(challengeMsg)
+	select {
+	case target.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar CHALLENGE_RECEIVED, canal posiblemente cerrado", logger.Fields{
+			"clientID":    targetID,
+			"challengeID": c.ID,
+		})
+	}
+
+	challengeMsg.Type = "CHALLENGE_SENT"
+	msgBytes, _ = json.Marshal(challengeMsg)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+
+	logger.Info("Reto enviado", logger.Fields{
+		"challengeID":  c.ID,
+		"challengerID": client.GetID(),
+		"targetID":     targetID,
+		"variant":      settings.Variant,
+	})
+}
+
+// acceptChallenge crea la sala privada del reto y sienta a los dos jugadores. El retador
+// ocupa el asiento del creador, así que su símbolo preferido se respeta
+func (h *Hub) acceptChallenge(c *challenge.Challenge) {
+	// Sentarse en otra sala ya cancela los retos; esto cubre asientos que llegan por otra vía,
+	// como volver a una sala tras reconectar
+	if h.findActiveSeat(c.Challenger.GetID()) != nil || h.findActiveSeat(c.Target.GetID()) != nil {
+		h.closeChallenge(c, "CHALLENGE_CANCELLED", challengeBusy)
+		return
+	}
+	if h.maxRooms > 0 && len(h.Rooms) >= h.maxRooms {
+		// El reto sigue pendiente por si se libera sitio antes de que caduque
+		errors.ServerCapacity(c.Target.GetSendChannel(), c.Target.GetID())
+		return
+	}
+
+	newRoom, err := h.newRoom(c.Settings)
+	if err != nil {
+		logger.Error("No se pudo crear la sala del reto", logger.Fields{
+			"error":       err.Error(),
+			"challengeID": c.ID,
+		})
+		errors.Internal(c.Target.GetSendChannel(), c.Target.GetID())
+		return
+	}
+	h.challenges.Remove(c.ID)
+
+	players := []interfaces.Client{c.Challenger, c.Target}
+	symbols := map[interfaces.Client]string{
+		c.Challenger: newRoom.CreatorSymbol(),
+		c.Target:     game.OppositeSymbol(newRoom.CreatorSymbol()),
+	}
+	opponents := map[interfaces.Client]interfaces.Client{
+		c.Challenger: c.Target,
+		c.Target:     c.Challenger,
+	}
+
+	for _, client := range players {
+		acceptedMsg := models.ChallengeAcceptedResponse{
+			Type:            "CHALLENGE_ACCEPTED",
+			ChallengeID:     c.ID,
+			RoomID:          newRoom.ID,
+			RoomCode:        newRoom.Code,
+			PlayerID:        client.GetID(),
+			OpponentID:      opponents[client].GetID(),
+			OpponentProfile: h.playerProfile(opponents[client].GetID()),
+			Symbol:          symbols[client],
+			Settings:        newRoom.Settings,
+		}
+		msgBytes, _ := json.Marshal(acceptedMsg)
+		select {
+		case client.GetSendChannel() <- msgBytes:
+		default:
+			logger.Warn("No se pudo enviar CHALLENGE_ACCEPTED, canal posiblemente cerrado", logger.Fields{
+				"clientID": client.GetID(),
+				"roomID":   newRoom.ID,
+			})
+		}
+	}
+
+	// Empezar la partida cancela la búsqueda de partida rápida y los demás retos de ambos
+	for _, client := range players {
+		if h.leaveQueue(client) {
+			h.sendMatchCancelled(client)
+		}
+		h.cancelChallenges(client, challengeBusy)
+	}
+	for _, client := range players {
+		client.SetRoom(newRoom)
+		newRoom.Register <- client
+	}
+
+	logger.Info("Reto aceptado", logger.Fields{
+		"challengeID":  c.ID,
+		"roomID":       newRoom.ID,
+		"challengerID": c.Challenger.GetID(),
+		"targetID":     c.Target.GetID(),
+	})
+}
+
+// closeChallenge retira un reto pendiente y avisa a los dos jugadores con el tipo y motivo dados
+func (h *Hub) closeChallenge(c *challenge.Challenge, msgType, reason string) {
+	h.challenges.Remove(c.ID)
+
+	closedMsg := models.ChallengeClosedResponse{
+		Type:        msgType,
+		ChallengeID: c.ID,
+		Reason:      reason,
+	}
+	msgBytes, _ := json.Marshal(closedMsg)
+	for _, client := range []interfaces.Client{c.Challenger, c.Target} {
+		// Una conexión cerrada ya no está en Clients y su canal puede estar cerrado
+		if _, ok := h.Clients[client]; !ok {
+			continue
+		}
+		select {
+		case client.GetSendChannel() <- msgBytes:
+		default:
+		}
+	}
+
+	logger.Info("Reto cerrado", logger.Fields{
+		"challengeID":  c.ID,
+		"challengerID": c.Challenger.GetID(),
+		"targetID":     c.Target.GetID(),
+		"reason":       reason,
+	})
+}
+
+// cancelChallenges cancela los retos pendientes en los que participa la conexión, porque
+// se ha cerrado o porque se ha sentado a jugar. Solo se llama desde Run
+func (h *Hub) cancelChallenges(client interfaces.Client, reason string) {
+	for _, c := range h.challenges.Involving(client) {
+		h.closeChallenge(c, "CHALLENGE_CANCELLED", reason)
+	}
+}
+
+// expireChallenges cancela los retos que nadie ha respondido a tiempo. Solo se llama desde Run
+func (h *Hub) expireChallenges(now time.Time) {
+	for _, c := range h.challenges.Expired(now) {
+		h.closeChallenge(c, "CHALLENGE_CANCELLED", challengeExpired)
+	}
+}
@@ -0,0 +1,275 @@
+package hub
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// fakeClient es un cliente en memoria que guarda los mensajes que recibe
+type fakeClient struct {
+	id   string
+	send chan []byte
+
+	mu   sync.Mutex
+	room interface{}
+}
+
+func newFakeClient(id string) *fakeClient {
+	return &fakeClient{id: id, send: make(chan []byte, 64)}
+}
+
+func (f *fakeClient) GetID() string                  { return f.id }
+func (f *fakeClient) GetSendChannel() chan []byte    { return f.send }
+func (f *fakeClient) GetConnection() *websocket.Conn { return nil }
+func (f *fakeClient) Close()                         {}
+
+func (f *fakeClient) SetRoom(r interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.room = r
+}
+
+func (f *fakeClient) GetRoom() interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.room
+}
+
+// waitForMessage lee mensajes del cliente hasta encontrar uno del tipo indicado
+func waitForMessage(t *testing.T, c *fakeClient, msgType string) map[string]interface{} {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case msgBytes := <-c.send:
+			var msg map[string]interface{}
+			if err := json.Unmarshal(msgBytes, &msg); err != nil {
+				t.Fatalf("Mensaje inválido: %v", err)
+			}
+			if msg["type"] == msgType {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("No se recibió %s para %s", msgType, c.id)
+			return nil
+		}
+	}
+}
+
+// TestMain inicializa el logger, necesario para ejecutar el bucle del Hub
+func TestMain(m *testing.M) {
+	logger.Initialize()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// startChallenge arranca un Hub con dos jugadores conectados y un reto de ana a bea.
+// Devuelve el Hub, los jugadores y el ID del reto
+func startChallenge(t *testing.T, timeout time.Duration) (*Hub, *fakeClient, *fakeClient, string) {
+	t.Helper()
+	h := NewHub()
+	h.SetChallenges(timeout)
+	go h.Run()
+	t.Cleanup(h.Close)
+
+	ana, bea := newFakeClient("ana"), newFakeClient("bea")
+	h.Register <- ana
+	h.Register <- bea
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.ChallengePlayer(ana, "bea", settings)
+	received := waitForMessage(t, bea, "CHALLENGE_RECEIVED")
+	challengeID, _ := received["challengeId"].(string)
+	if challengeID == "" {
+		t.Fatalf("CHALLENGE_RECEIVED sin challengeId: %v", received)
+	}
+	return h, ana, bea, challengeID
+}
+
+// TestChallengeExpires verifica que un reto sin respuesta se cancele al caducar
+func TestChallengeExpires(t *testing.T) {
+	_, ana, bea, challengeID := startChallenge(t, 10*time.Millisecond)
+
+	for _, c := range []*fakeClient{ana, bea} {
+		cancelled := waitForMessage(t, c, "CHALLENGE_CANCELLED")
+		if cancelled["challengeId"] != challengeID || cancelled["reason"] != challengeExpired {
+			t.Errorf("Cancelación incorrecta para %s: %v", c.id, cancelled)
+		}
+	}
+}
+
+// TestChallengeCancelledOnDisconnect verifica que el reto se cancele si el retado se desconecta
+func TestChallengeCancelledOnDisconnect(t *testing.T) {
+	h, ana, bea, challengeID := startChallenge(t, time.Minute)
+
+	h.UnregisterClient(bea)
+	cancelled := waitForMessage(t, ana, "CHALLENGE_CANCELLED")
+	if cancelled["challengeId"] != challengeID || cancelled["reason"] != challengeDisconnected {
+		t.Errorf("Cancelación incorrecta: %v", cancelled)
+	}
+}
+
+// TestChallengeCancelledWhenTargetSits verifica que el retado que se sienta en otra sala ya
+// no pueda aceptar el reto y conserve su asiento
+func TestChallengeCancelledWhenTargetSits(t *testing.T) {
+	h, ana, bea, challengeID := startChallenge(t, time.Minute)
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.CreateRoom(bea, settings)
+	for _, c := range []*fakeClient{ana, bea} {
+		cancelled := waitForMessage(t, c, "CHALLENGE_CANCELLED")
+		if cancelled["challengeId"] != challengeID || cancelled["reason"] != challengeBusy {
+			t.Errorf("Cancelación incorrecta para %s: %v", c.id, cancelled)
+		}
+	}
+	created := waitForMessage(t, bea, "ROOM_CREATED")
+
+	h.ChallengeAction(bea, "ACCEPT_CHALLENGE", challengeID)
+	waitForMessage(t, bea, "ERROR_CHALLENGE_NOT_FOUND")
+
+	if seat, ok := bea.GetRoom().(*room.Room); !ok || seat.ID != created["roomId"] {
+		t.Errorf("bea debería seguir en su sala %v", created["roomId"])
+	}
+	if ana.GetRoom() != nil {
+		t.Error("ana no debería estar en ninguna sala")
+	}
+}
+
+// TestChallengeBusyTargetCannotAccept verifica que un reto no se acepte si el retado ya
+// tiene asiento en otra sala, aunque el reto siga pendiente. Los manejadores se llaman
+// directamente, como haría Run, para sentar al retado sin cancelar sus retos
+func TestChallengeBusyTargetCannotAccept(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+
+	ana, bea := newFakeClient("ana"), newFakeClient("bea")
+	h.Clients[ana] = true
+	h.Clients[bea] = true
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.handleChallengeRequest(&ChallengeRequest{Client: ana, Type: "CHALLENGE_PLAYER", TargetID: "bea", Settings: settings})
+	received := waitForMessage(t, bea, "CHALLENGE_RECEIVED")
+	challengeID, _ := received["challengeId"].(string)
+
+	seatRoom, err := h.newRoom(settings)
+	if err != nil {
+		t.Fatalf("Error creando sala: %v", err)
+	}
+	seatRoom.Register <- bea
+	deadline := time.Now().Add(2 * time.Second)
+	for h.findPlayerRoom("bea") == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("bea no llegó a sentarse")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	h.handleChallengeRequest(&ChallengeRequest{Client: bea, Type: "ACCEPT_CHALLENGE", ChallengeID: challengeID})
+	for _, c := range []*fakeClient{ana, bea} {
+		cancelled := waitForMessage(t, c, "CHALLENGE_CANCELLED")
+		if cancelled["challengeId"] != challengeID || cancelled["reason"] != challengeBusy {
+			t.Errorf("Cancelación incorrecta para %s: %v", c.id, cancelled)
+		}
+	}
+	if len(h.Rooms) != 1 || h.challenges.Len() != 0 || ana.GetRoom() != nil {
+		t.Errorf("No debería crearse la sala del reto: %d salas, %d retos", len(h.Rooms), h.challenges.Len())
+	}
+}
+
+// startGame sienta a los dos jugadores en una sala nueva y espera a que empiece la partida
+func startGame(t *testing.T, h *Hub, x, o *fakeClient) *room.Room {
+	t.Helper()
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	r, err := h.newRoom(settings)
+	if err != nil {
+		t.Fatalf("Error creando sala: %v", err)
+	}
+	x.SetRoom(r)
+	r.Register <- x
+	o.SetRoom(r)
+	r.Register <- o
+	waitForMessage(t, x, "GAME_START")
+	return r
+}
+
+// winGame hace que x, que mueve primero, gane la partida en curso completando la primera fila
+func winGame(t *testing.T, r *room.Room, x, o *fakeClient) {
+	t.Helper()
+	moves := []struct {
+		client *fakeClient
+		row    int
+		col    int
+	}{
+		{x, 0, 0}, {o, 1, 0}, {x, 0, 1}, {o, 1, 1}, {x, 0, 2},
+	}
+	for _, m := range moves {
+		r.ReceiveMove <- &models.PlayerMove{Client: m.client, MoveData: models.MovePayload{Row: m.row, Col: m.col}}
+	}
+	waitForMessage(t, x, "GAME_OVER")
+	waitUntil(t, "la sala no publicó el final de la partida", func() bool {
+		return !isActive(r.Info())
+	})
+}
+
+// waitUntil espera a que se cumpla la condición, que depende de bucles de sala
+func waitUntil(t *testing.T, failure string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(failure)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// finishGame juega una partida completa en una sala nueva. La sala se queda terminada con
+// los dos asientos ocupados
+func finishGame(t *testing.T, h *Hub, x, o *fakeClient) *room.Room {
+	t.Helper()
+	r := startGame(t, h, x, o)
+	winGame(t, r, x, o)
+	return r
+}
+
+// TestChallengeAfterFinishedGame verifica que un asiento en una sala terminada no cuente
+// como partida en curso: los dos jugadores pueden retarse y aceptar
+func TestChallengeAfterFinishedGame(t *testing.T) {
+	h := NewHub()
+	h.SetChallenges(time.Minute)
+	t.Cleanup(h.cancel)
+
+	ana, bea := newFakeClient("ana"), newFakeClient("bea")
+	h.Clients[ana] = true
+	h.Clients[bea] = true
+	finished := finishGame(t, h, ana, bea)
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.handleChallengeRequest(&ChallengeRequest{Client: ana, Type: "CHALLENGE_PLAYER", TargetID: "bea", Settings: settings})
+	received := waitForMessage(t, bea, "CHALLENGE_RECEIVED")
+	challengeID, _ := received["challengeId"].(string)
+
+	h.handleChallengeRequest(&ChallengeRequest{Client: bea, Type: "ACCEPT_CHALLENGE", ChallengeID: challengeID})
+	accepted := waitForMessage(t, ana, "CHALLENGE_ACCEPTED")
+	roomID, _ := accepted["roomId"].(string)
+	if roomID == "" || roomID == finished.ID {
+		t.Fatalf("Se esperaba una sala nueva para el reto, obtenido %q", roomID)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for seat := h.findPlayerRoom("bea"); seat == nil || seat.ID != roomID; seat = h.findPlayerRoom("bea") {
+		if time.Now().After(deadline) {
+			t.Fatal("El asiento de la partida en curso debería tener prioridad sobre la sala terminada")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,382 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/friends"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// presenceCheckInterval es cada cuánto se revisa si ha cambiado la presencia de algún
+// jugador (por ejemplo, al empezar o terminar una partida)
+const presenceCheckInterval = time.Second
+
+// Estados de presencia que ven los amigos (FRIEND_PRESENCE)
+const (
+	PresenceOnline  = "online"
+	PresenceInGame  = "in-game"
+	PresenceAway    = "away"
+	PresenceOffline = "offline"
+)
+
+// PresenceInvisible es la elección de un jugador que quiere aparecer desconectado
+const PresenceInvisible = "invisible"
+
+// FriendRequest representa un mensaje de la lista de amigos de un cliente (ADD_FRIEND,
+// REMOVE_FRIEND, LIST_FRIENDS o SET_PRESENCE)
+type FriendRequest struct {
+	Client   interfaces.Client
+	Type     string
+	PlayerID string // En ADD_FRIEND y REMOVE_FRIEND
+	Status   string // Solo en SET_PRESENCE, ya validado
+}
+
+// presence es lo que los amigos mutuos de un jugador ven de él
+type presence struct {
+	status string
+	roomID string // Solo en partida y en una sala pública
+}
+
+// presenceSnapshot es el estado del Hub con el que se calcula la presencia de varios
+// jugadores sin recorrer clientes y salas por cada uno
+type presenceSnapshot struct {
+	clients map[string]interfaces.Client // jugador -> conexión
+	rooms   map[string]*room.Room        // jugador -> sala en la que juega
+}
+
+// SetFriends configura el registro de listas de amigos. Debe llamarse antes de Run
+func (h *Hub) SetFriends(book *friends.Book) {
+	h.friends = book
+}
+
+// FriendAction pide al Hub que añada, quite o liste amigos (ADD_FRIEND, REMOVE_FRIEND o
+// LIST_FRIENDS)
+func (h *Hub) FriendAction(client interfaces.Client, action string, playerID string) {
+	h.FriendChan <- &FriendRequest{
+		Client:   client,
+		Type:     action,
+		PlayerID: playerID,
+	}
+}
+
+// SetPresence pide al Hub que cambie cómo ve al cliente su lista de amigos (SET_PRESENCE).
+// El estado debe venir ya validado
+func (h *Hub) SetPresence(client interfaces.Client, status string) {
+	h.FriendChan <- &FriendRequest{
+		Client: client,
+		Type:   "SET_PRESENCE",
+		Status: status,
+	}
+}
+
+// handleFriendRequest atiende los mensajes de la lista de amigos. Solo se llama desde Run
+func (h *Hub) handleFriendRequest(req *FriendRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	switch req.Type {
+	case "ADD_FRIEND":
+		h.addFriend(client, req.PlayerID)
+	case "REMOVE_FRIEND":
+		h.removeFriend(client, req.PlayerID)
+	case "LIST_FRIENDS":
+		h.sendFriendList(client)
+	case "SET_PRESENCE":
+		h.setPresence(client, req.Status)
+	}
+}
+
+// addFriend añade un jugador a la lista del cliente. Si el otro ya le había añadido, la
+// amistad pasa a ser mutua y los dos empiezan a verse la presencia; si no, se le avisa
+// con FRIEND_REQUEST
+func (h *Hub) addFriend(client interfaces.Client, friendID string) {
+	playerID := client.GetID()
+	if friendID == playerID {
+		errors.InvalidTarget(client.GetSendChannel(), "No puedes añadirte a ti mismo", playerID)
+		return
+	}
+	// Solo se pueden añadir jugadores conocidos: conectados o con perfil
+	target := h.clientByID(friendID)
+	if target == nil && h.playerProfile(friendID) == nil {
+		errors.PlayerNotFound(client.GetSendChannel(), playerID)
+		return
+	}
+
+	if err := h.friends.Add(playerID, friendID); err != nil {
+		h.sendFriendError(client, err)
+		return
+	}
+	h.saveFriends()
+
+	snap := h.presenceSnapshot()
+	h.sendFriendMessage(client, "FRIEND_ADDED", h.friendInfo(playerID, friendID, snap))
+
+	if target != nil {
+		if h.friends.Mutual(playerID, friendID) {
+			h.sendFriendMessage(target, "FRIEND_ADDED", h.friendInfo(friendID, playerID, snap))
+		} else {
+			h.sendFriendMessage(target, "FRIEND_REQUEST", h.friendInfo(friendID, playerID, snap))
+		}
+	}
+
+	logger.Info("Amigo añadido", logger.Fields{
+		"clientID": playerID,
+		"friendID": friendID,
+		"mutual":   h.friends.Mutual(playerID, friendID),
+	})
+}
+
+// removeFriend quita a un jugador de la lista del cliente. Si la amistad era mutua, el
+// otro deja de ver su presencia y pasa a verle desconectado
+func (h *Hub) removeFriend(client interfaces.Client, friendID string) {
+	playerID := client.GetID()
+	wasMutual := h.friends.Mutual(playerID, friendID)
+
+	if err := h.friends.Remove(playerID, friendID); err != nil {
+		h.sendFriendError(client, err)
+		return
+	}
+	h.saveFriends()
+
+	h.sendFriendMessage(client, "FRIEND_REMOVED", models.FriendInfo{
+		PlayerID: friendID,
+		Profile:  h.playerProfile(friendID),
+	})
+
+	if target := h.clientByID(friendID); target != nil && wasMutual {
+		h.sendPresence(target, playerID, presence{status: PresenceOffline})
+	}
+
+	logger.Info("Amigo eliminado", logger.Fields{
+		"clientID":  playerID,
+		"friendID":  friendID,
+		"wasMutual": wasMutual,
+	})
+}
+
+// sendFriendList envía al cliente su lista de amigos y las solicitudes que tiene pendientes
+func (h *Hub) sendFriendList(client interfaces.Client) {
+	playerID := client.GetID()
+	snap := h.presenceSnapshot()
+
+	listMsg := models.FriendListResponse{
+		Type:     "FRIEND_LIST",
+		Presence: h.presenceChoice(playerID),
+		Friends:  []models.FriendInfo{},
+		Requests: []models.FriendInfo{},
+	}
+	for _, friendID := range h.friends.Friends(playerID) {
+		listMsg.Friends = append(listMsg.Friends, h.friendInfo(playerID, friendID, snap))
+	}
+	for _, requesterID := range h.friends.Requests(playerID) {
+		listMsg.Requests = append(listMsg.Requests, h.friendInfo(playerID, requesterID, snap))
+	}
+
+	msgBytes, _ := json.Marshal(listMsg)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// setPresence guarda cómo quiere aparecer el cliente ante sus amigos y se lo confirma con
+// PRESENCE_UPDATED. "away" dura hasta que se desconecta; "invisible" se mantiene entre
+// conexiones mientras el servidor siga en marcha
+func (h *Hub) setPresence(client interfaces.Client, status string) {
+	playerID := client.GetID()
+	if status == PresenceOnline {
+		delete(h.presenceChoices, playerID)
+	} else {
+		h.presenceChoices[playerID] = status
+	}
+
+	msgBytes, _ := json.Marshal(models.PresenceResponse{
+		Type:   "PRESENCE_UPDATED",
+		Status: status,
+	})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+
+	h.updatePresence(playerID, h.presenceSnapshot())
+}
+
+// presenceChoice devuelve cómo ha elegido aparecer el jugador: online, away o invisible
+func (h *Hub) presenceChoice(playerID string) string {
+	if choice, ok := h.presenceChoices[playerID]; ok {
+		return choice
+	}
+	return PresenceOnline
+}
+
+// presenceSnapshot recoge qué jugadores están conectados y en qué sala juegan. Las salas
+// terminadas conservan los asientos un tiempo, pero no cuentan como partida en curso.
+// Solo se llama desde Run
+func (h *Hub) presenceSnapshot() presenceSnapshot {
+	snap := presenceSnapshot{
+		clients: make(map[string]interfaces.Client, len(h.Clients)),
+		rooms:   make(map[string]*room.Room),
+	}
+	for client := range h.Clients {
+		snap.clients[client.GetID()] = client
+	}
+	for _, r := range h.Rooms {
+		info := r.Info()
+		if !isActive(info) {
+			continue
+		}
+		for _, playerID := range info.Players {
+			snap.rooms[playerID] = r
+		}
+	}
+	return snap
+}
+
+// currentPresence calcula lo que los amigos mutuos del jugador deben ver de él. Quien
+// elige ser invisible aparece desconectado, y la sala solo se revela si es pública
+func (h *Hub) currentPresence(playerID string, snap presenceSnapshot) presence {
+	choice := h.presenceChoice(playerID)
+	if _, online := snap.clients[playerID]; !online || choice == PresenceInvisible {
+		return presence{status: PresenceOffline}
+	}
+	if r, ok := snap.rooms[playerID]; ok {
+		p := presence{status: PresenceInGame}
+		if r.Settings.Visibility == room.VisibilityPublic {
+			p.roomID = r.ID
+		}
+		return p
+	}
+	if choice == PresenceAway {
+		return presence{status: PresenceAway}
+	}
+	return presence{status: PresenceOnline}
+}
+
+// friendInfo describe a otherID tal como lo ve viewerID. La presencia solo se incluye si
+// la amistad es mutua
+func (h *Hub) friendInfo(viewerID, otherID string, snap presenceSnapshot) models.FriendInfo {
+	info := models.FriendInfo{
+		PlayerID: otherID,
+		Profile:  h.playerProfile(otherID),
+		Mutual:   h.friends.Mutual(viewerID, otherID),
+	}
+	if info.Mutual {
+		p := h.currentPresence(otherID, snap)
+		info.Status = p.status
+		info.RoomID = p.roomID
+	}
+	return info
+}
+
+// updatePresence avisa a los amigos mutuos conectados si la presencia del jugador ha
+// cambiado desde el último aviso. Solo se llama desde Run
+func (h *Hub) updatePresence(playerID string, snap presenceSnapshot) {
+	current := h.currentPresence(playerID, snap)
+	last, ok := h.presence[playerID]
+	if !ok {
+		last = presence{status: PresenceOffline}
+	}
+	if current == last {
+		return
+	}
+	if current.status == PresenceOffline {
+		delete(h.presence, playerID)
+	} else {
+		h.presence[playerID] = current
+	}
+
+	for _, friendID := range h.friends.MutualFriends(playerID) {
+		if friend, online := snap.clients[friendID]; online {
+			h.sendPresence(friend, playerID, current)
+		}
+	}
+}
+
+// checkPresence revisa la presencia de los jugadores conectados y de los que acaban de
+// desconectarse. Solo se llama desde Run
+func (h *Hub) checkPresence() {
+	snap := h.presenceSnapshot()
+	for playerID := range snap.clients {
+		h.updatePresence(playerID, snap)
+	}
+	for playerID := range h.presence {
+		if _, online := snap.clients[playerID]; !online {
+			h.updatePresence(playerID, snap)
+		}
+	}
+}
+
+// playerDisconnected anota la última vez que se vio al jugador, olvida su elección "away"
+// y avisa a sus amigos si ya no le queda ninguna conexión. Solo se llama desde Run
+func (h *Hub) playerDisconnected(playerID string) {
+	h.profiles.Seen(playerID, time.Now())
+	if h.clientByID(playerID) != nil {
+		return
+	}
+	if h.presenceChoices[playerID] == PresenceAway {
+		delete(h.presenceChoices, playerID)
+	}
+	h.updatePresence(playerID, h.presenceSnapshot())
+}
+
+// sendPresence envía a un cliente la presencia de uno de sus amigos mutuos
+func (h *Hub) sendPresence(client interfaces.Client, playerID string, p presence) {
+	msgBytes, _ := json.Marshal(models.FriendPresenceResponse{
+		Type:     "FRIEND_PRESENCE",
+		PlayerID: playerID,
+		Status:   p.status,
+		RoomID:   p.roomID,
+	})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// sendFriendMessage envía a un cliente un cambio en su lista de amigos
+func (h *Hub) sendFriendMessage(client interfaces.Client, msgType string, friend models.FriendInfo) {
+	msgBytes, _ := json.Marshal(models.FriendResponse{
+		Type:   msgType,
+		Friend: friend,
+	})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// sendFriendError traduce un error del registro de amigos al error del protocolo
+func (h *Hub) sendFriendError(client interfaces.Client, err error) {
+	switch {
+	case stderrors.Is(err, friends.ErrSelf):
+		errors.InvalidTarget(client.GetSendChannel(), "No puedes añadirte a ti mismo", client.GetID())
+	case stderrors.Is(err, friends.ErrAlreadyFriend):
+		errors.InvalidFriendAction(client.GetSendChannel(), "El jugador ya está en tu lista de amigos", client.GetID())
+	case stderrors.Is(err, friends.ErrNotFriend):
+		errors.InvalidFriendAction(client.GetSendChannel(), "El jugador no está en tu lista de amigos", client.GetID())
+	case stderrors.Is(err, friends.ErrTooManyFriends):
+		errors.InvalidFriendAction(client.GetSendChannel(), "Tu lista de amigos está llena", client.GetID())
+	default:
+		errors.Internal(client.GetSendChannel(), client.GetID())
+	}
+}
+
+// saveFriends guarda las listas de amigos en segundo plano
+func (h *Hub) saveFriends() {
+	go func() {
+		if err := h.friends.Save(); err != nil {
+			logger.Error("No se pudieron guardar las listas de amigos", logger.Fields{
+				"error": err.Error(),
+			})
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package hub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/session"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// presenceMessages devuelve los FRIEND_PRESENCE pendientes del cliente sobre el jugador
+func presenceMessages(t *testing.T, c *fakeClient, playerID string) []map[string]interface{} {
+	t.Helper()
+	var found []map[string]interface{}
+	for {
+		select {
+		case msgBytes := <-c.send:
+			var msg map[string]interface{}
+			if err := json.Unmarshal(msgBytes, &msg); err != nil {
+				t.Fatalf("Mensaje inválido: %v", err)
+			}
+			if msg["type"] == "FRIEND_PRESENCE" && msg["playerId"] == playerID {
+				found = append(found, msg)
+			}
+		default:
+			return found
+		}
+	}
+}
+
+// TestPresenceOnlyForMutualFriends verifica que solo los amigos mutuos reciban la presencia
+// de un jugador, y que una partida terminada no cuente como partida en curso
+func TestPresenceOnlyForMutualFriends(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+
+	ana, bea, carl, dan := newFakeClient("ana"), newFakeClient("bea"), newFakeClient("carl"), newFakeClient("dan")
+	for _, c := range []*fakeClient{ana, bea, carl, dan} {
+		h.Clients[c] = true
+	}
+
+	// bea y ana son amigas mutuas; carl añadió a ana, pero ana no a carl
+	h.handleFriendRequest(&FriendRequest{Client: ana, Type: "ADD_FRIEND", PlayerID: "bea"})
+	h.handleFriendRequest(&FriendRequest{Client: bea, Type: "ADD_FRIEND", PlayerID: "ana"})
+	h.handleFriendRequest(&FriendRequest{Client: carl, Type: "ADD_FRIEND", PlayerID: "ana"})
+	h.checkPresence()
+	presenceMessages(t, bea, "ana")
+	presenceMessages(t, carl, "ana")
+
+	r := startGame(t, h, ana, dan)
+	waitUntil(t, "ana no llegó a sentarse", func() bool { return h.findActiveSeat("ana") != nil })
+	h.checkPresence()
+
+	inGame := presenceMessages(t, bea, "ana")
+	if len(inGame) != 1 || inGame[0]["status"] != PresenceInGame {
+		t.Fatalf("bea debería ver a ana en partida: %v", inGame)
+	}
+	if leaked := presenceMessages(t, carl, "ana"); len(leaked) != 0 {
+		t.Fatalf("carl no es amigo mutuo y no debería recibir la presencia de ana: %v", leaked)
+	}
+
+	// La sala terminada conserva el asiento, pero ana vuelve a estar disponible
+	winGame(t, r, ana, dan)
+	h.checkPresence()
+	if online := presenceMessages(t, bea, "ana"); len(online) != 1 || online[0]["status"] != PresenceOnline {
+		t.Errorf("bea debería ver a ana disponible tras la partida: %v", online)
+	}
+	if leaked := presenceMessages(t, carl, "ana"); len(leaked) != 0 {
+		t.Errorf("carl no debería recibir la presencia de ana: %v", leaked)
+	}
+}
+
+// TestPruneInactivePlayers verifica que se olvide el perfil y las amistades de quien no se
+// conecta desde hace más que la vigencia de las sesiones, pero no de quien sigue conectado
+func TestPruneInactivePlayers(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+	h.SetSessionManager(session.NewManager([]byte("secreto"), time.Hour))
+
+	ana := newFakeClient("ana")
+	h.Clients[ana] = true
+	h.profiles.Set("ana", models.PlayerProfile{DisplayName: "Ana"})
+	h.profiles.Set("bea", models.PlayerProfile{DisplayName: "Bea"})
+	h.friends.Add("ana", "bea")
+	h.friends.Add("bea", "ana")
+	h.friends.Add("carl", "ana")
+
+	h.prunePlayers(time.Now())
+	if len(h.friends.Friends("ana")) != 1 || h.playerProfile("bea") == nil {
+		t.Fatal("No se debería olvidar a nadie antes de que caduquen las sesiones")
+	}
+
+	h.prunePlayers(time.Now().Add(2 * time.Hour))
+	if h.playerProfile("bea") != nil || len(h.friends.Friends("ana")) != 0 || len(h.friends.Requests("ana")) != 0 {
+		t.Error("bea y carl deberían olvidarse")
+	}
+	if h.playerProfile("ana") == nil {
+		t.Error("ana sigue conectada y no debería olvidarse")
+	}
+	if _, err := h.profiles.Set("ana2", models.PlayerProfile{DisplayName: "bea"}); err != nil {
+		t.Errorf("El nombre de bea debería quedar libre: %v", err)
+	}
+}
@@ -3,13 +3,27 @@ package hub
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 
+	"nvivas/backend/tictactoe-go-server/internal/archive"
+	"nvivas/backend/tictactoe-go-server/internal/challenge"
 	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/friends"
 	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/leaderboard"
+	"nvivas/backend/tictactoe-go-server/internal/lobby"
 	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/matchmaking"
+	"nvivas/backend/tictactoe-go-server/internal/profile"
+	"nvivas/backend/tictactoe-go-server/internal/rating"
 	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/roomcode"
+	"nvivas/backend/tictactoe-go-server/internal/roomlist"
+	"nvivas/backend/tictactoe-go-server/internal/session"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/internal/tournament"
 	"nvivas/backend/tictactoe-go-server/pkg/models"
 )
 
@@ -25,6 +39,9 @@ type Hub struct {
 	// Salas activas
 	Rooms map[string]*room.Room
 
+	// Códigos cortos de sala (código -> ID interno de la sala)
+	Codes map[string]string
+
 	// Límite máximo de salas
 	maxRooms int
 
@@ -35,7 +52,7 @@ type Hub struct {
 	Unregister chan interfaces.Client
 
 	// Canal para crear una nueva sala
-	CreateRoomChan chan interfaces.Client
+	CreateRoomChan chan *CreateRequest
 
 	// Canal para unirse a una sala existente
 	JoinRoomChan chan *JoinRequest
@@ -43,32 +60,141 @@ type Hub struct {
 	// Canal para eliminar una sala
 	DeleteRoomChan chan string
 
-	// Canal para mensajes a todos los clientes (opcional)
-	broadcast chan []byte
+	// Canal para entrar o salir de la cola de partida rápida
+	MatchChan chan *MatchRequest
+
+	// Canal para reanudar la sesión de un cliente ya conectado (mensaje RESUME_SESSION)
+	ResumeChan chan *ResumeRequest
+
+	// Firma y verificación de tokens de sesión, nil si no se emiten sesiones
+	sessions *session.Manager
+
+	// Persistencia de salas, nil si está desactivada
+	store            *store.FileStore
+	snapshotInterval time.Duration
+	saveChan         chan chan struct{}
+
+	// Historial de partidas terminadas, nil si no se archivan
+	archive *archive.Archive
+
+	// Eliminación de salas inactivas
+	reapPolicy   room.ReapPolicy
+	reapInterval time.Duration
+	reaperStats  reaperStats
+
+	// Puntuaciones de los jugadores, nil si no se puntúan partidas
+	ratings *rating.Book
+
+	// Clasificación por temporadas, nil si está desactivada
+	leaderboard *leaderboard.Board
+
+	// Cola de partida rápida
+	matchQueue    *matchmaking.Queue
+	matchInterval time.Duration
+
+	// Canal para suscribirse al vestíbulo en vivo o darse de baja
+	LobbyChan chan *LobbyRequest
+
+	// Vestíbulo en vivo: salas públicas y jugadores conectados
+	lobby *lobby.Lobby
+
+	// Canal para las acciones de torneo de los clientes
+	TournamentChan chan *TournamentRequest
+
+	// Canal por el que las salas comunican los resultados de sus partidas
+	ResultChan chan models.GameRecord
+
+	// Torneos, salas de sus partidas (ID de sala -> partida) y clientes que los observan
+	tournaments        map[string]*tournament.Tournament
+	tournamentGames    map[string]*tournamentGame
+	tournamentWatchers map[string]map[interfaces.Client]bool
+	noShowTimeout      time.Duration
+
+	// Canal para retar a un jugador y responder a los retos
+	ChallengeChan chan *ChallengeRequest
+
+	// Retos pendientes
+	challenges *challenge.Registry
+
+	// Perfiles públicos de los jugadores
+	profiles *profile.Book
+
+	// Canal para gestionar la lista de amigos y la presencia
+	FriendChan chan *FriendRequest
+
+	// Listas de amigos, última presencia avisada de cada jugador y presencia elegida por
+	// quien no quiere aparecer como conectado
+	friends         *friends.Book
+	presence        map[string]presence
+	presenceChoices map[string]string
+}
+
+// CreateRequest representa una solicitud para crear una sala
+type CreateRequest struct {
+	Client   interfaces.Client
+	Settings models.RoomSettings
 }
 
 // JoinRequest representa una solicitud para unirse a una sala
 type JoinRequest struct {
-	Client interfaces.Client
-	RoomID string
+	Client   interfaces.Client
+	RoomID   string
+	Spectate bool
+}
+
+// ResumeRequest representa una solicitud para recuperar la identidad de una sesión anterior
+type ResumeRequest struct {
+	Client   interfaces.Client
+	PlayerID string // ID ya verificado a partir del token de sesión
 }
 
 // NewHub crea una nueva instancia de Hub
 func NewHub() *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// Sin almacén, los perfiles y los amigos se guardan solo en memoria hasta que se configure otro registro
+	profiles, _ := profile.NewBook(nil)
+	friendBook, _ := friends.NewBook(nil)
+
 	return &Hub{
 		ctx:            ctx,
 		cancel:         cancel,
 		Clients:        make(map[interfaces.Client]bool),
 		Rooms:          make(map[string]*room.Room),
+		Codes:          make(map[string]string),
 		maxRooms:       0, // Sin límite por defecto
 		Register:       make(chan interfaces.Client),
 		Unregister:     make(chan interfaces.Client),
-		CreateRoomChan: make(chan interfaces.Client),
+		CreateRoomChan: make(chan *CreateRequest),
 		JoinRoomChan:   make(chan *JoinRequest),
 		DeleteRoomChan: make(chan string),
-		broadcast:      make(chan []byte),
+		ResumeChan:     make(chan *ResumeRequest),
+		MatchChan:      make(chan *MatchRequest),
+		saveChan:       make(chan chan struct{}),
+		reapPolicy:     DefaultReapPolicy(),
+		reapInterval:   DefaultReapInterval,
+		reaperStats:    reaperStats{byReason: make(map[string]int)},
+		matchQueue:     matchmaking.NewQueue(matchmaking.DefaultPolicy()),
+		matchInterval:  DefaultMatchInterval,
+		LobbyChan:      make(chan *LobbyRequest),
+		lobby:          lobby.New(),
+
+		TournamentChan:     make(chan *TournamentRequest),
+		ResultChan:         make(chan models.GameRecord),
+		tournaments:        make(map[string]*tournament.Tournament),
+		tournamentGames:    make(map[string]*tournamentGame),
+		tournamentWatchers: make(map[string]map[interfaces.Client]bool),
+		noShowTimeout:      DefaultNoShowTimeout,
+
+		ChallengeChan: make(chan *ChallengeRequest),
+		challenges:    challenge.NewRegistry(challenge.DefaultTimeout),
+
+		profiles: profiles,
+
+		FriendChan:      make(chan *FriendRequest),
+		friends:         friendBook,
+		presence:        make(map[string]presence),
+		presenceChoices: make(map[string]string),
 	}
 }
 
@@ -80,8 +206,32 @@ func (h *Hub) SetLimits(maxRooms int) {
 	})
 }
 
-// Close cancela el contexto y libera recursos
+// SetSessionManager configura la emisión de tokens de sesión. Debe llamarse antes de Run
+func (h *Hub) SetSessionManager(sessions *session.Manager) {
+	h.sessions = sessions
+}
+
+// VerifySession comprueba un token de sesión y devuelve el ID del jugador.
+// Se puede llamar desde cualquier goroutine
+func (h *Hub) VerifySession(token string) (string, error) {
+	if h.sessions == nil {
+		return "", session.ErrInvalidToken
+	}
+	return h.sessions.Verify(token, time.Now())
+}
+
+// ResumeSession pide al Hub que asigne al cliente la identidad de una sesión ya verificada
+func (h *Hub) ResumeSession(client interfaces.Client, playerID string) {
+	h.ResumeChan <- &ResumeRequest{
+		Client:   client,
+		PlayerID: playerID,
+	}
+}
+
+// Close guarda una copia final de las salas (si hay persistencia), cancela el contexto
+// y libera recursos
 func (h *Hub) Close() {
+	h.requestFinalSave()
 	h.cancel()
 	// No cerramos los canales aquí, porque podría haber goroutines escribiendo en ellos
 	// La cancelación del contexto debería ser suficiente para que salgan de sus bucles
@@ -93,12 +243,15 @@ func (h *Hub) UnregisterClient(client interfaces.Client) {
 	h.Unregister <- client
 }
 
-// CreateRoom implements interfaces.Hub
-func (h *Hub) CreateRoom(client interfaces.Client) {
-	h.CreateRoomChan <- client
+// CreateRoom implements interfaces.Hub. La configuración debe venir ya validada
+func (h *Hub) CreateRoom(client interfaces.Client, settings models.RoomSettings) {
+	h.CreateRoomChan <- &CreateRequest{
+		Client:   client,
+		Settings: settings,
+	}
 }
 
-// JoinRoom implements interfaces.Hub
+// JoinRoom implements interfaces.Hub. roomID puede ser el ID interno o el código corto
 func (h *Hub) JoinRoom(roomID string, client interfaces.Client) {
 	h.JoinRoomChan <- &JoinRequest{
 		Client: client,
@@ -106,36 +259,44 @@ func (h *Hub) JoinRoom(roomID string, client interfaces.Client) {
 	}
 }
 
+// SpectateRoom implements interfaces.Hub. roomID puede ser el ID interno o el código corto
+func (h *Hub) SpectateRoom(roomID string, client interfaces.Client) {
+	h.JoinRoomChan <- &JoinRequest{
+		Client:   client,
+		RoomID:   roomID,
+		Spectate: true,
+	}
+}
+
 // DeleteRoom implements interfaces.Hub
 func (h *Hub) DeleteRoom(roomID string) {
 	h.DeleteRoomChan <- roomID
 }
 
-// ListRooms implements interfaces.Hub
-func (h *Hub) ListRooms(client interfaces.Client) {
-	// Create a list of room information
-	roomsList := make([]models.RoomInfo, 0, len(h.Rooms))
+// ListRooms implements interfaces.Hub. La consulta debe venir validada. Las salas salen
+// del vestíbulo, que solo contiene salas públicas y se puede leer desde cualquier goroutine
+func (h *Hub) ListRooms(client interfaces.Client, query models.ListRoomsPayload) {
+	rooms := h.lobby.Rooms()
 
-	for roomID, room := range h.Rooms {
-		// Get player IDs
-		playerIDs := room.GetPlayerIDs()
-
-		// Determine if room is full
-		isFull := len(playerIDs) >= 2
-
-		// Add room info to the list
-		roomInfo := models.RoomInfo{
-			RoomID:  roomID,
-			Players: playerIDs,
-			IsFull:  isFull,
+	// Puntuación media de los jugadores con asiento, para mostrarla y ordenar por ella
+	for i := range rooms {
+		if len(rooms[i].Players) == 0 {
+			continue
+		}
+		var total float64
+		for _, playerID := range rooms[i].Players {
+			total += h.playerRating(playerID, rooms[i].Settings.Variant)
 		}
-		roomsList = append(roomsList, roomInfo)
+		rooms[i].Rating = total / float64(len(rooms[i].Players))
 	}
 
+	page, nextCursor := roomlist.Page(rooms, query)
+
 	// Create the response
 	response := models.RoomListPayload{
-		Type:  "ROOM_LIST",
-		Rooms: roomsList,
+		Type:       "ROOM_LIST",
+		Rooms:      page,
+		NextCursor: nextCursor,
 	}
 
 	// Serialize the response
@@ -154,8 +315,232 @@ func (h *Hub) ListRooms(client interfaces.Client) {
 
 	logger.Info("Lista de salas enviada", logger.Fields{
 		"clientID":  client.GetID(),
-		"roomCount": len(roomsList),
+		"roomCount": len(page),
+		"sort":      query.Sort,
+		"hasMore":   nextCursor != "",
+	})
+}
+
+// findRoom busca una sala por su ID interno o, si no existe, por su código corto
+// (sin distinguir mayúsculas de minúsculas)
+func (h *Hub) findRoom(idOrCode string) (*room.Room, bool) {
+	if r, exists := h.Rooms[idOrCode]; exists {
+		return r, true
+	}
+
+	roomID, exists := h.Codes[roomcode.Normalize(idOrCode)]
+	if !exists {
+		return nil, false
+	}
+
+	r, exists := h.Rooms[roomID]
+	return r, exists
+}
+
+// registerClient da de alta a un cliente, reemplaza una conexión anterior con la misma
+// identidad, le envía su sesión y lo devuelve a su asiento si tenía uno
+func (h *Hub) registerClient(client interfaces.Client) {
+	h.profiles.Seen(client.GetID(), time.Now())
+	seatRoom := h.findPlayerRoom(client.GetID())
+
+	if old := h.clientByID(client.GetID()); old != nil && old != client {
+		h.replaceClient(old, seatRoom)
+	}
+
+	h.Clients[client] = true
+	h.lobby.SetOnline(len(h.Clients))
+	h.sendSession(client, seatRoom)
+
+	// Una partida sin terminar tiene prioridad; si además tiene una partida de torneo
+	// esperándole, se le avisa y se le sienta en ella al terminar
+	t, m, matchRoom, game := h.pendingTournamentSeat(client.GetID())
+	if seatRoom != nil && (t == nil || isActive(seatRoom.Info())) {
+		client.SetRoom(seatRoom)
+		seatRoom.Register <- client
+
+		logger.Info("Cliente devuelto a su sala", logger.Fields{
+			"clientID": client.GetID(),
+			"roomID":   seatRoom.ID,
+		})
+	}
+	if t != nil && matchRoom != seatRoom {
+		h.seatTournamentPlayer(client, t, m, matchRoom, game)
+	}
+}
+
+// resumeClient asigna a un cliente conectado la identidad de su sesión anterior
+func (h *Hub) resumeClient(req *ResumeRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	withID, ok := client.(interface{ SetID(id string) })
+	if !ok {
+		errors.Internal(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	logger.Info("Cliente reanuda sesión", logger.Fields{
+		"clientID": client.GetID(),
+		"playerID": req.PlayerID,
+	})
+
+	// Se registra de nuevo con la identidad recuperada
+	oldID := client.GetID()
+	delete(h.Clients, client)
+	withID.SetID(req.PlayerID)
+	h.registerClient(client)
+	h.playerDisconnected(oldID)
+	h.updatePresence(req.PlayerID, h.presenceSnapshot())
+}
+
+// replaceClient desconecta una conexión anterior del mismo jugador (por ejemplo, tras
+// un cambio de red). Si el jugador tiene asiento, la sala sustituye la conexión al volver
+func (h *Hub) replaceClient(old interfaces.Client, seatRoom *room.Room) {
+	delete(h.Clients, old)
+	h.leaveQueue(old)
+	h.lobby.Unsubscribe(old)
+	h.unwatchTournaments(old)
+	h.cancelChallenges(old, challengeDisconnected)
+
+	if oldRoom, ok := old.GetRoom().(*room.Room); ok && oldRoom != nil && oldRoom != seatRoom {
+		oldRoom.Unregister <- old
+	}
+
+	replacedMsg := models.BaseMessage{Type: "SESSION_REPLACED"}
+	msgBytes, _ := json.Marshal(replacedMsg)
+	select {
+	case old.GetSendChannel() <- msgBytes:
+	default:
+	}
+
+	old.Close()
+
+	logger.Info("Conexión anterior reemplazada", logger.Fields{
+		"clientID": old.GetID(),
+	})
+}
+
+// sendSession emite un token de sesión nuevo para el cliente
+func (h *Hub) sendSession(client interfaces.Client, seatRoom *room.Room) {
+	if h.sessions == nil {
+		return
+	}
+
+	token, expiresAt := h.sessions.Issue(client.GetID(), time.Now())
+	sessionMsg := models.SessionResponse{
+		Type:         "SESSION",
+		PlayerID:     client.GetID(),
+		SessionToken: token,
+		ExpiresAt:    expiresAt.UnixMilli(),
+	}
+	if seatRoom != nil {
+		sessionMsg.RoomID = seatRoom.ID
+	}
+	msgBytes, _ := json.Marshal(sessionMsg)
+
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar SESSION, canal posiblemente cerrado", logger.Fields{
+			"clientID": client.GetID(),
+		})
+	}
+}
+
+// clientByID busca un cliente conectado por su ID
+func (h *Hub) clientByID(clientID string) interfaces.Client {
+	for client := range h.Clients {
+		if client.GetID() == clientID {
+			return client
+		}
+	}
+	return nil
+}
+
+// findPlayerRoom busca la sala en la que el jugador tiene asiento. Una sala terminada
+// conserva los asientos hasta que se elimina, así que si el jugador tiene asiento en
+// varias se prefiere la de una partida sin terminar
+func (h *Hub) findPlayerRoom(playerID string) *room.Room {
+	var finished *room.Room
+	for _, r := range h.Rooms {
+		info := r.Info()
+		if !seatedIn(info, playerID) {
+			continue
+		}
+		if isActive(info) {
+			return r
+		}
+		finished = r
+	}
+	return finished
+}
+
+// findActiveSeat busca la sala en la que el jugador tiene asiento en una partida sin
+// terminar. Un asiento en una sala terminada no impide empezar otra partida
+func (h *Hub) findActiveSeat(playerID string) *room.Room {
+	for _, r := range h.Rooms {
+		if info := r.Info(); isActive(info) && seatedIn(info, playerID) {
+			return r
+		}
+	}
+	return nil
+}
+
+// seatedIn indica si el jugador tiene asiento en la sala
+func seatedIn(info models.RoomInfo, playerID string) bool {
+	for _, seated := range info.Players {
+		if seated == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// isActive indica si la sala tiene una partida sin terminar o espera rival
+func isActive(info models.RoomInfo) bool {
+	return info.State != string(room.StateFinished) && info.State != string(room.StateClosed)
+}
+
+// newRoom crea una sala con un código corto libre, la registra en los mapas del Hub e
+// inicia su bucle. Solo se llama desde Run
+func (h *Hub) newRoom(settings models.RoomSettings) (*room.Room, error) {
+	// Generar un código corto que no colisione con las salas activas
+	code, err := roomcode.Generate(func(c string) bool {
+		_, taken := h.Codes[c]
+		return taken
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	roomID := uuid.NewString()
+	newRoom := room.NewRoomWithSettings(roomID, settings, h, h.ctx)
+	newRoom.Code = code
+
+	h.Rooms[roomID] = newRoom
+	h.Codes[code] = roomID
+
+	go newRoom.Run()
+	return newRoom, nil
+}
+
+// removeRoom cierra una sala y la quita de los mapas del Hub. Solo se llama desde Run
+func (h *Hub) removeRoom(roomID string) {
+	r, exists := h.Rooms[roomID]
+	if !exists {
+		return
+	}
+
+	// Cancelar el contexto de la sala: su bucle avisa a los clientes con ROOM_CLOSED
+	r.Close()
+
+	// Eliminar la sala y su código de los mapas
+	delete(h.Rooms, roomID)
+	delete(h.Codes, r.Code)
+
+	logger.Info("Sala eliminada exitosamente", logger.Fields{"roomID": roomID})
 }
 
 // createErrorMessage crea un mensaje de error serializado en JSON
@@ -191,6 +576,55 @@ func (h *Hub) Run() {
 		}
 	}()
 
+	// Copias periódicas de las salas, solo si hay persistencia
+	var snapshotTick <-chan time.Time
+	if h.store != nil && h.snapshotInterval > 0 {
+		ticker := time.NewTicker(h.snapshotInterval)
+		defer ticker.Stop()
+		snapshotTick = ticker.C
+	}
+
+	// Revisión periódica de salas inactivas; se detiene con el contexto del Hub
+	var reapTick <-chan time.Time
+	if h.reapInterval > 0 {
+		ticker := time.NewTicker(h.reapInterval)
+		defer ticker.Stop()
+		reapTick = ticker.C
+	}
+
+	// La cola se revisa periódicamente porque la tolerancia crece con la espera
+	var matchTick <-chan time.Time
+	if h.matchInterval > 0 {
+		ticker := time.NewTicker(h.matchInterval)
+		defer ticker.Stop()
+		matchTick = ticker.C
+	}
+
+	// Cierre de temporadas de la clasificación
+	var seasonTick <-chan time.Time
+	if h.leaderboard != nil {
+		h.checkSeason()
+		ticker := time.NewTicker(seasonCheckInterval)
+		defer ticker.Stop()
+		seasonTick = ticker.C
+	}
+
+	// Incomparecencias y partidas pendientes de los torneos
+	tournamentTicker := time.NewTicker(tournamentCheckInterval)
+	defer tournamentTicker.Stop()
+
+	// Retos sin respuesta
+	challengeTicker := time.NewTicker(challengeCheckInterval)
+	defer challengeTicker.Stop()
+
+	// Cambios de presencia de los amigos
+	presenceTicker := time.NewTicker(presenceCheckInterval)
+	defer presenceTicker.Stop()
+
+	// Jugadores que ya no pueden volver
+	pruneTicker := time.NewTicker(playerPruneInterval)
+	defer pruneTicker.Stop()
+
 	for {
 		select {
 		case <-h.ctx.Done():
@@ -198,18 +632,75 @@ func (h *Hub) Run() {
 			logger.Info("Contexto cancelado, terminando Hub.Run", nil)
 			return
 
+		case <-snapshotTick:
+			h.saveRooms()
+
+		case <-reapTick:
+			h.reapRooms()
+
+		case <-matchTick:
+			h.runMatchmaking()
+
+		case <-seasonTick:
+			h.checkSeason()
+
+		case now := <-tournamentTicker.C:
+			h.checkTournaments(now)
+
+		case now := <-challengeTicker.C:
+			h.expireChallenges(now)
+
+		case <-presenceTicker.C:
+			h.checkPresence()
+
+		case now := <-pruneTicker.C:
+			h.prunePlayers(now)
+
+		case friendReq := <-h.FriendChan:
+			h.handleFriendRequest(friendReq)
+
+		case challengeReq := <-h.ChallengeChan:
+			h.handleChallengeRequest(challengeReq)
+
+		case tournamentReq := <-h.TournamentChan:
+			h.handleTournamentRequest(tournamentReq)
+
+		case record := <-h.ResultChan:
+			h.handleGameResult(record)
+
+		case matchReq := <-h.MatchChan:
+			h.handleMatchRequest(matchReq)
+
+		case lobbyReq := <-h.LobbyChan:
+			h.handleLobbyRequest(lobbyReq)
+
+		case done := <-h.saveChan:
+			h.saveRooms()
+			close(done)
+
 		case client := <-h.Register:
 			// Registrar un nuevo cliente
-			h.Clients[client] = true
+			h.registerClient(client)
+			h.updatePresence(client.GetID(), h.presenceSnapshot())
 			logger.Info("Cliente registrado", logger.Fields{
 				"clientID": client.GetID(),
 			})
 
+		case resumeReq := <-h.ResumeChan:
+			h.resumeClient(resumeReq)
+
 		case client := <-h.Unregister:
 			// Verificar si el cliente está registrado
 			if _, ok := h.Clients[client]; ok {
-				// Eliminar el cliente
+				// Eliminar el cliente, su búsqueda de partida, su suscripción al vestíbulo y
+				// sus retos antes de cerrar su canal
 				delete(h.Clients, client)
+				h.leaveQueue(client)
+				h.lobby.Unsubscribe(client)
+				h.unwatchTournaments(client)
+				h.cancelChallenges(client, challengeDisconnected)
+				h.lobby.SetOnline(len(h.Clients))
+				h.playerDisconnected(client.GetID())
 				logger.Info("Cliente desregistrado", logger.Fields{
 					"clientID": client.GetID(),
 				})
@@ -229,7 +720,9 @@ func (h *Hub) Run() {
 				}
 			}
 
-		case client := <-h.CreateRoomChan:
+		case createReq := <-h.CreateRoomChan:
+			client := createReq.Client
+
 			// Verificar si hemos alcanzado el límite de salas
 			if h.maxRooms > 0 && len(h.Rooms) >= h.maxRooms {
 				logger.Warn("Límite de salas alcanzado, rechazando creación de sala", logger.Fields{
@@ -243,17 +736,23 @@ func (h *Hub) Run() {
 				continue
 			}
 
-			// Crear un ID único para la sala
-			roomID := uuid.NewString()
-
-			// Crear una instancia de Room
-			newRoom := room.NewRoom(roomID, h, h.ctx)
-
-			// Almacenar la sala en el mapa de salas
-			h.Rooms[roomID] = newRoom
+			// Crear la sala e iniciar su bucle
+			newRoom, err := h.newRoom(createReq.Settings)
+			if err != nil {
+				logger.Error("No se pudo generar código de sala", logger.Fields{
+					"error":    err.Error(),
+					"clientID": client.GetID(),
+				})
+				errors.Internal(client.GetSendChannel(), client.GetID())
+				continue
+			}
+			roomID, code := newRoom.ID, newRoom.Code
 
-			// Iniciar la sala como goroutine
-			go newRoom.Run()
+			// Crear una sala cancela la búsqueda de partida rápida y los retos pendientes
+			if h.leaveQueue(client) {
+				h.sendMatchCancelled(client)
+			}
+			h.cancelChallenges(client, challengeBusy)
 
 			// Si el cliente ya estaba en una sala, limpiamos la referencia
 			oldRoom := client.GetRoom()
@@ -271,8 +770,10 @@ func (h *Hub) Run() {
 			msg := models.RoomCreatedResponse{
 				Type:     "ROOM_CREATED",
 				RoomID:   roomID,
+				RoomCode: code,
 				PlayerID: client.GetID(),
-				Symbol:   "X", // El creador siempre es X
+				Symbol:   newRoom.CreatorSymbol(),
+				Settings: newRoom.Settings,
 			}
 			msgBytes, _ := json.Marshal(msg)
 
@@ -289,16 +790,30 @@ func (h *Hub) Run() {
 
 			logger.Info("Sala creada", logger.Fields{
 				"roomID":    roomID,
+				"roomCode":  code,
 				"clientID":  client.GetID(),
-				"symbol":    "X",
+				"symbol":    newRoom.CreatorSymbol(),
+				"variant":   newRoom.Settings.Variant,
 				"roomCount": len(h.Rooms),
 				"maxRooms":  h.maxRooms,
 			})
 
 		case joinReq := <-h.JoinRoomChan:
 			// Task 29: Mejorar la lógica de unirse a salas
-			// Buscar la sala por su ID
-			if room, exists := h.Rooms[joinReq.RoomID]; exists {
+			// Buscar la sala por su ID o por su código corto
+			if room, exists := h.findRoom(joinReq.RoomID); exists {
+				// Los espectadores no ocupan asiento: la sala valida sus propios límites
+				if joinReq.Spectate {
+					joinReq.Client.SetRoom(room)
+					room.RegisterSpectator <- joinReq.Client
+
+					logger.Info("Cliente solicita observar sala", logger.Fields{
+						"roomID":   room.ID,
+						"clientID": joinReq.Client.GetID(),
+					})
+					continue
+				}
+
 				// Check if this client is rejoining a room they were previously in
 				isRejoining := false
 				for _, playerID := range room.GetPlayerIDs() {
@@ -313,7 +828,7 @@ func (h *Hub) Run() {
 				}
 
 				// Verificar si la sala está llena antes de unirse (solo si no es una reconexión)
-				if len(room.Clients) >= 2 && !isRejoining {
+				if len(room.GetPlayerIDs()) >= 2 && !isRejoining {
 					// Sala llena, enviar mensaje de error
 					select {
 					case joinReq.Client.GetSendChannel() <- createErrorMessage(errors.ErrorRoomFull, "La sala ya está llena", joinReq.Client.GetID()):
@@ -332,6 +847,12 @@ func (h *Hub) Run() {
 					continue
 				}
 
+				// Unirse a una sala cancela la búsqueda de partida rápida y los retos pendientes
+				if h.leaveQueue(joinReq.Client) {
+					h.sendMatchCancelled(joinReq.Client)
+				}
+				h.cancelChallenges(joinReq.Client, challengeBusy)
+
 				// Si el cliente ya estaba en una sala, primero limpiamos la referencia
 				oldRoom := joinReq.Client.GetRoom()
 				if oldRoom != nil {
@@ -373,16 +894,9 @@ func (h *Hub) Run() {
 
 		case roomID := <-h.DeleteRoomChan:
 			// Eliminar una sala cuando ya no es necesaria
-			if room, exists := h.Rooms[roomID]; exists {
+			if _, exists := h.Rooms[roomID]; exists {
 				logger.Info("Eliminando sala", logger.Fields{"roomID": roomID})
-
-				// Cancelar el contexto de la sala (ya que Room ahora usará contexto)
-				room.Close()
-
-				// Eliminar la sala del mapa
-				delete(h.Rooms, roomID)
-
-				logger.Info("Sala eliminada exitosamente", logger.Fields{"roomID": roomID})
+				h.removeRoom(roomID)
 			}
 		}
 	}
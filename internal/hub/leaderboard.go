@@ -0,0 +1,73 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/leaderboard"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// seasonCheckInterval es cada cuánto se comprueba si la temporada ha terminado
+const seasonCheckInterval = time.Minute
+
+// SetLeaderboard activa la clasificación por temporadas. Debe llamarse antes de Run
+func (h *Hub) SetLeaderboard(board *leaderboard.Board) {
+	h.leaderboard = board
+}
+
+// SendLeaderboard envía al cliente una página de la clasificación (mensaje GET_LEADERBOARD).
+// La variante debe venir ya validada
+func (h *Hub) SendLeaderboard(client interfaces.Client, query models.GetLeaderboardPayload) {
+	if h.leaderboard == nil {
+		errors.SeasonNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	page, err := h.leaderboard.Query(query.Variant, query.Season, query.Offset, query.Limit, client.GetID())
+	if stderrors.Is(err, leaderboard.ErrSeasonNotFound) {
+		errors.SeasonNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+	if err != nil {
+		logger.Error("No se pudo consultar la clasificación", logger.Fields{
+			"variant":  query.Variant,
+			"season":   query.Season,
+			"clientID": client.GetID(),
+			"error":    err.Error(),
+		})
+		errors.Internal(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	msgBytes, _ := json.Marshal(page)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar LEADERBOARD, canal posiblemente cerrado", logger.Fields{
+			"clientID": client.GetID(),
+		})
+	}
+}
+
+// checkSeason cierra la temporada si ha terminado y archiva su clasificación final.
+// Solo se llama desde Run
+func (h *Hub) checkSeason() {
+	closed, err := h.leaderboard.RolloverIfDue(time.Now())
+	for _, season := range closed {
+		logger.Info("Temporada cerrada", logger.Fields{
+			"season":    season.Number,
+			"startedAt": time.UnixMilli(season.StartedAt).UTC().Format(time.RFC3339),
+			"endedAt":   time.UnixMilli(season.EndsAt).UTC().Format(time.RFC3339),
+		})
+	}
+	if err != nil {
+		logger.Error("No se pudo archivar el cierre de temporada", logger.Fields{
+			"error": err.Error(),
+		})
+	}
+}
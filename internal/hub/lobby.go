@@ -0,0 +1,61 @@
+package hub
+
+import (
+	"encoding/json"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// LobbyRequest representa una solicitud para suscribirse al vestíbulo o darse de baja
+type LobbyRequest struct {
+	Client    interfaces.Client
+	Subscribe bool
+}
+
+// SubscribeLobby pide al Hub que suscriba al cliente al vestíbulo (mensaje SUBSCRIBE_LOBBY)
+func (h *Hub) SubscribeLobby(client interfaces.Client) {
+	h.LobbyChan <- &LobbyRequest{Client: client, Subscribe: true}
+}
+
+// UnsubscribeLobby pide al Hub que dé de baja al cliente del vestíbulo (mensaje UNSUBSCRIBE_LOBBY)
+func (h *Hub) UnsubscribeLobby(client interfaces.Client) {
+	h.LobbyChan <- &LobbyRequest{Client: client}
+}
+
+// RoomChanged publica en el vestíbulo el resumen de una sala pública. Lo llaman las salas
+// desde su bucle; no bloquea
+func (h *Hub) RoomChanged(info models.RoomInfo) {
+	h.lobby.RoomChanged(info)
+}
+
+// RoomRemoved publica en el vestíbulo que una sala pública se ha cerrado
+func (h *Hub) RoomRemoved(roomID string) {
+	h.lobby.RoomRemoved(roomID)
+}
+
+// handleLobbyRequest atiende SUBSCRIBE_LOBBY y UNSUBSCRIBE_LOBBY. Pasa por el bucle del Hub
+// para que un cliente ya desregistrado (con su canal cerrado) no quede suscrito
+func (h *Hub) handleLobbyRequest(req *LobbyRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	if req.Subscribe {
+		h.lobby.Subscribe(client)
+		logger.Info("Cliente suscrito al vestíbulo", logger.Fields{
+			"clientID":    client.GetID(),
+			"subscribers": h.lobby.Subscribers(),
+		})
+		return
+	}
+
+	h.lobby.Unsubscribe(client)
+	msgBytes, _ := json.Marshal(models.BaseMessage{Type: "LOBBY_UNSUBSCRIBED"})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
@@ -0,0 +1,251 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/matchmaking"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// DefaultMatchInterval es cada cuánto se revisa la cola si no se configura otro valor
+const DefaultMatchInterval = time.Second
+
+// MatchRequest representa una solicitud para entrar o salir de la cola de partida rápida
+type MatchRequest struct {
+	Client   interfaces.Client
+	Settings models.RoomSettings // Configuración ya validada; se ignora al cancelar
+	Cancel   bool
+}
+
+// SetMatchmaking configura la tolerancia de la cola de partida rápida y cada cuánto se
+// revisa. Debe llamarse antes de Run
+func (h *Hub) SetMatchmaking(policy matchmaking.Policy, interval time.Duration) {
+	h.matchQueue = matchmaking.NewQueue(policy)
+	h.matchInterval = interval
+
+	logger.Info("Partida rápida configurada", logger.Fields{
+		"initialTolerance":  policy.InitialTolerance,
+		"wideningPerSecond": policy.WideningPerSecond,
+		"maxTolerance":      policy.MaxTolerance,
+		"interval":          interval.String(),
+	})
+}
+
+// FindMatch pide al Hub que añada al cliente a la cola de partida rápida (mensaje FIND_MATCH).
+// La configuración debe venir ya validada
+func (h *Hub) FindMatch(client interfaces.Client, settings models.RoomSettings) {
+	h.MatchChan <- &MatchRequest{
+		Client:   client,
+		Settings: settings,
+	}
+}
+
+// CancelMatch pide al Hub que saque al cliente de la cola (mensaje CANCEL_MATCH)
+func (h *Hub) CancelMatch(client interfaces.Client) {
+	h.MatchChan <- &MatchRequest{
+		Client: client,
+		Cancel: true,
+	}
+}
+
+// handleMatchRequest atiende FIND_MATCH y CANCEL_MATCH. Solo se llama desde Run
+func (h *Hub) handleMatchRequest(req *MatchRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	if req.Cancel {
+		if !h.leaveQueue(client) {
+			errors.NotInQueue(client.GetSendChannel(), client.GetID())
+			return
+		}
+		h.sendMatchCancelled(client)
+		return
+	}
+
+	if h.findActiveSeat(client.GetID()) != nil {
+		errors.PlayerBusy(client.GetSendChannel(), "No puedes buscar partida mientras juegas en una sala", client.GetID())
+		return
+	}
+
+	ticket := &matchmaking.Ticket{
+		Client:   client,
+		Settings: req.Settings,
+		Rating:   h.playerRating(client.GetID(), req.Settings.Variant),
+		JoinedAt: time.Now(),
+	}
+	if err := h.matchQueue.Add(ticket); err != nil {
+		if stderrors.Is(err, matchmaking.ErrAlreadyQueued) {
+			errors.AlreadyInQueue(client.GetSendChannel(), client.GetID())
+			return
+		}
+		errors.Internal(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	searchingMsg := models.MatchSearchingResponse{
+		Type:        "MATCH_SEARCHING",
+		Variant:     req.Settings.Variant,
+		BoardSize:   req.Settings.BoardSize,
+		TimeControl: req.Settings.TimeControl,
+		Rating:      ticket.Rating,
+		QueueSize:   h.matchQueue.Len(),
+	}
+	msgBytes, _ := json.Marshal(searchingMsg)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+
+	logger.Info("Cliente busca partida rápida", logger.Fields{
+		"clientID":  client.GetID(),
+		"pool":      matchmaking.Pool(req.Settings),
+		"rating":    ticket.Rating,
+		"queueSize": h.matchQueue.Len(),
+	})
+
+	// Puede haber ya un rival esperando
+	h.runMatchmaking()
+}
+
+// leaveQueue saca al cliente de la cola si su ticket es de esta misma conexión.
+// Devuelve true si estaba en la cola. Solo se llama desde Run
+func (h *Hub) leaveQueue(client interfaces.Client) bool {
+	ticket := h.matchQueue.Get(client.GetID())
+	if ticket == nil || ticket.Client != client {
+		return false
+	}
+	h.matchQueue.Remove(client.GetID())
+	return true
+}
+
+// sendMatchCancelled confirma al cliente que ya no está en la cola
+func (h *Hub) sendMatchCancelled(client interfaces.Client) {
+	msgBytes, _ := json.Marshal(models.MatchCancelledResponse{Type: "MATCH_CANCELLED"})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// runMatchmaking empareja a los jugadores compatibles de la cola y crea sus salas.
+// Solo se llama desde Run
+func (h *Hub) runMatchmaking() {
+	if h.matchQueue.Len() < 2 {
+		return
+	}
+
+	now := time.Now()
+	for _, pair := range h.matchQueue.Match(now) {
+		// Sin sitio para más salas, la pareja vuelve a la cola conservando su turno
+		if h.maxRooms > 0 && len(h.Rooms) >= h.maxRooms {
+			h.matchQueue.Add(pair.First)
+			h.matchQueue.Add(pair.Second)
+			logger.Warn("Límite de salas alcanzado, emparejamiento aplazado", logger.Fields{
+				"first":    pair.First.PlayerID(),
+				"second":   pair.Second.PlayerID(),
+				"maxRooms": h.maxRooms,
+			})
+			continue
+		}
+
+		h.startMatch(pair, now)
+	}
+}
+
+// startMatch crea una sala privada para una pareja y sienta a ambos jugadores. Si uno de
+// ellos se ha sentado en otra sala mientras esperaba, pierde su ticket y el otro vuelve a
+// la cola conservando su turno
+func (h *Hub) startMatch(pair matchmaking.Pair, now time.Time) {
+	if h.findActiveSeat(pair.First.PlayerID()) != nil || h.findActiveSeat(pair.Second.PlayerID()) != nil {
+		for _, ticket := range []*matchmaking.Ticket{pair.First, pair.Second} {
+			if h.findActiveSeat(ticket.PlayerID()) == nil {
+				h.matchQueue.Add(ticket)
+				continue
+			}
+			h.sendMatchCancelled(ticket.Client)
+			logger.Info("Ticket de partida rápida descartado, el jugador ya está en una sala", logger.Fields{
+				"clientID": ticket.PlayerID(),
+			})
+		}
+		return
+	}
+
+	// Las salas de partida rápida son puntuadas, no aparecen en LIST_ROOMS y sortean los símbolos
+	settings := pair.First.Settings
+	settings.Visibility = room.VisibilityPrivate
+	settings.PreferredSymbol = room.SymbolRandom
+	settings.Rated = true
+
+	newRoom, err := h.newRoom(settings)
+	if err != nil {
+		logger.Error("No se pudo crear la sala de partida rápida", logger.Fields{
+			"error":  err.Error(),
+			"first":  pair.First.PlayerID(),
+			"second": pair.Second.PlayerID(),
+		})
+		errors.Internal(pair.First.Client.GetSendChannel(), pair.First.PlayerID())
+		errors.Internal(pair.Second.Client.GetSendChannel(), pair.Second.PlayerID())
+		return
+	}
+
+	// El primero en llegar ocupa el asiento del creador
+	symbols := map[*matchmaking.Ticket]string{
+		pair.First:  newRoom.CreatorSymbol(),
+		pair.Second: game.OppositeSymbol(newRoom.CreatorSymbol()),
+	}
+	opponents := map[*matchmaking.Ticket]*matchmaking.Ticket{
+		pair.First:  pair.Second,
+		pair.Second: pair.First,
+	}
+
+	for _, ticket := range []*matchmaking.Ticket{pair.First, pair.Second} {
+		opponent := opponents[ticket]
+		foundMsg := models.MatchFoundResponse{
+			Type:            "MATCH_FOUND",
+			RoomID:          newRoom.ID,
+			RoomCode:        newRoom.Code,
+			PlayerID:        ticket.PlayerID(),
+			OpponentID:      opponent.PlayerID(),
+			OpponentRating:  opponent.Rating,
+			OpponentProfile: h.playerProfile(opponent.PlayerID()),
+			Symbol:          symbols[ticket],
+			Settings:        newRoom.Settings,
+			WaitedMs:        now.Sub(ticket.JoinedAt).Milliseconds(),
+		}
+		msgBytes, _ := json.Marshal(foundMsg)
+
+		select {
+		case ticket.Client.GetSendChannel() <- msgBytes:
+		default:
+			logger.Warn("No se pudo enviar MATCH_FOUND, canal posiblemente cerrado", logger.Fields{
+				"clientID": ticket.PlayerID(),
+				"roomID":   newRoom.ID,
+			})
+		}
+	}
+
+	// La sala envía después sus mensajes habituales y empieza la partida. Los retos
+	// pendientes de ambos se cancelan
+	for _, ticket := range []*matchmaking.Ticket{pair.First, pair.Second} {
+		h.cancelChallenges(ticket.Client, challengeBusy)
+		ticket.Client.SetRoom(newRoom)
+		newRoom.Register <- ticket.Client
+	}
+
+	logger.Info("Partida rápida emparejada", logger.Fields{
+		"roomID":    newRoom.ID,
+		"first":     pair.First.PlayerID(),
+		"second":    pair.Second.PlayerID(),
+		"pool":      matchmaking.Pool(settings),
+		"queueSize": h.matchQueue.Len(),
+	})
+}
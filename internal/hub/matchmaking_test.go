@@ -0,0 +1,79 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/matchmaking"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+)
+
+// TestFindMatchWhileSeated verifica que un jugador con una partida sin terminar no pueda
+// entrar en la cola de partida rápida
+func TestFindMatchWhileSeated(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+
+	ana := newFakeClient("ana")
+	h.Clients[ana] = true
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	seatRoom, err := h.newRoom(settings)
+	if err != nil {
+		t.Fatalf("Error creando sala: %v", err)
+	}
+	ana.SetRoom(seatRoom)
+	seatRoom.Register <- ana
+	deadline := time.Now().Add(2 * time.Second)
+	for h.findActiveSeat("ana") == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("ana no llegó a sentarse")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	h.handleMatchRequest(&MatchRequest{Client: ana, Settings: settings})
+	if busy := waitForMessage(t, ana, "ERROR_PLAYER_BUSY"); busy["message"] == "" {
+		t.Errorf("Error sin mensaje: %v", busy)
+	}
+	if h.matchQueue.Contains("ana") {
+		t.Error("ana no debería estar en la cola")
+	}
+}
+
+// TestStartMatchRequeuesFreePlayer verifica que, si uno de los emparejados se ha sentado
+// en otra sala, no se cree la partida y el otro vuelva a la cola
+func TestStartMatchRequeuesFreePlayer(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+
+	ana, bea, carl := newFakeClient("ana"), newFakeClient("bea"), newFakeClient("carl")
+	for _, c := range []*fakeClient{ana, bea, carl} {
+		h.Clients[c] = true
+	}
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.handleMatchRequest(&MatchRequest{Client: ana, Settings: settings})
+	waitForMessage(t, ana, "MATCH_SEARCHING")
+
+	// bea y carl juegan entre ellos mientras ana espera
+	seatRoom, err := h.newRoom(settings)
+	if err != nil {
+		t.Fatalf("Error creando sala: %v", err)
+	}
+	bea.SetRoom(seatRoom)
+	seatRoom.Register <- bea
+	carl.SetRoom(seatRoom)
+	seatRoom.Register <- carl
+	waitForMessage(t, bea, "GAME_START")
+
+	ticket := h.matchQueue.Get("ana")
+	h.matchQueue.Remove("ana")
+	busyTicket := &matchmaking.Ticket{Client: bea, Settings: settings, JoinedAt: time.Now()}
+	h.startMatch(matchmaking.Pair{First: ticket, Second: busyTicket}, time.Now())
+
+	waitForMessage(t, bea, "MATCH_CANCELLED")
+	if len(h.Rooms) != 1 || !h.matchQueue.Contains("ana") || h.matchQueue.Contains("bea") {
+		t.Errorf("Se esperaba a ana de vuelta en la cola y ninguna sala nueva: %d salas", len(h.Rooms))
+	}
+}
@@ -0,0 +1,122 @@
+package hub
+
+import (
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+const (
+	// roomsDocument es el nombre del documento con las copias de las salas
+	roomsDocument = "rooms"
+
+	// finalSaveTimeout limita la espera de la copia final al cerrar el Hub
+	finalSaveTimeout = 5 * time.Second
+)
+
+// roomsSnapshot es el documento que se guarda con el estado de todas las salas
+type roomsSnapshot struct {
+	SavedAt time.Time       `json:"savedAt"`
+	Rooms   []room.Snapshot `json:"rooms"`
+}
+
+// SetStore activa la persistencia de salas. interval indica cada cuánto se guardan las
+// copias mientras el servidor está en marcha. Debe llamarse antes de Run
+func (h *Hub) SetStore(s *store.FileStore, interval time.Duration) {
+	h.store = s
+	h.snapshotInterval = interval
+}
+
+// RestoreRooms recupera las salas guardadas y las pone en marcha. Debe llamarse antes de Run
+func (h *Hub) RestoreRooms() int {
+	if h.store == nil {
+		return 0
+	}
+
+	var doc roomsSnapshot
+	found, err := h.store.Load(roomsDocument, &doc)
+	if err != nil {
+		logger.Error("No se pudieron cargar las salas guardadas", logger.Fields{"error": err.Error()})
+		return 0
+	}
+	if !found {
+		return 0
+	}
+
+	for _, snap := range doc.Rooms {
+		if _, exists := h.Rooms[snap.ID]; exists {
+			continue
+		}
+		if _, taken := h.Codes[snap.Code]; taken || snap.Code == "" {
+			logger.Warn("Sala guardada con código repetido o vacío, se descarta", logger.Fields{
+				"roomID":   snap.ID,
+				"roomCode": snap.Code,
+			})
+			continue
+		}
+
+		restored := room.RestoreRoom(snap, h, h.ctx)
+		h.Rooms[snap.ID] = restored
+		h.Codes[snap.Code] = snap.ID
+		go restored.Run()
+	}
+
+	logger.Info("Salas restauradas", logger.Fields{
+		"rooms":   len(h.Rooms),
+		"savedAt": doc.SavedAt,
+	})
+
+	return len(h.Rooms)
+}
+
+// saveRooms guarda una copia de todas las salas con jugadores. Solo se llama desde Run
+func (h *Hub) saveRooms() {
+	if h.store == nil {
+		return
+	}
+
+	doc := roomsSnapshot{
+		SavedAt: time.Now(),
+		Rooms:   make([]room.Snapshot, 0, len(h.Rooms)),
+	}
+
+	for _, r := range h.Rooms {
+		snap, ok := r.Snapshot()
+		if !ok || snap.State == room.StateClosed || len(snap.Game.PlayerSymbols) == 0 {
+			continue
+		}
+		doc.Rooms = append(doc.Rooms, snap)
+	}
+
+	if err := h.store.Save(roomsDocument, doc); err != nil {
+		logger.Error("No se pudieron guardar las salas", logger.Fields{"error": err.Error()})
+		return
+	}
+
+	logger.Debug("Salas guardadas", logger.Fields{"rooms": len(doc.Rooms)})
+}
+
+// requestFinalSave pide al bucle del Hub una última copia de las salas y espera a que termine
+func (h *Hub) requestFinalSave() {
+	if h.store == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	select {
+	case h.saveChan <- done:
+	case <-h.ctx.Done():
+		return
+	case <-time.After(finalSaveTimeout):
+		logger.Warn("El Hub no atendió la copia final de salas", nil)
+		return
+	}
+
+	select {
+	case <-done:
+	case <-time.After(finalSaveTimeout):
+		logger.Warn("Tiempo agotado guardando la copia final de salas", nil)
+	}
+}
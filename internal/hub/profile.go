@@ -0,0 +1,116 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/profile"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// playerPruneInterval es cada cuánto se olvida a los jugadores inactivos
+const playerPruneInterval = time.Hour
+
+// SetProfiles configura el registro de perfiles de los jugadores. Debe llamarse antes de Run
+func (h *Hub) SetProfiles(book *profile.Book) {
+	h.profiles = book
+}
+
+// SetProfile guarda el perfil del cliente (mensaje SET_PROFILE) y se lo confirma con
+// PROFILE_UPDATED. El registro es seguro para uso concurrente, así que no pasa por Run.
+// El perfil aparece en los mensajes de sala y del vestíbulo que se envíen a partir de ahora
+func (h *Hub) SetProfile(client interfaces.Client, payload models.SetProfilePayload) {
+	p, err := h.profiles.Set(client.GetID(), models.PlayerProfile{
+		DisplayName: payload.DisplayName,
+		Avatar:      payload.Avatar,
+		Country:     payload.Country,
+	})
+	if stderrors.Is(err, profile.ErrNameTaken) {
+		errors.NameTaken(client.GetSendChannel(), client.GetID())
+		return
+	}
+	if err != nil {
+		errors.InvalidPayload(client.GetSendChannel(), "set profile: "+err.Error(), client.GetID())
+		return
+	}
+
+	h.saveProfiles()
+
+	msgBytes, _ := json.Marshal(models.ProfileResponse{
+		Type:     "PROFILE_UPDATED",
+		PlayerID: client.GetID(),
+		Profile:  p,
+	})
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+
+	logger.Info("Perfil actualizado", logger.Fields{
+		"clientID":    client.GetID(),
+		"displayName": p.DisplayName,
+		"country":     p.Country,
+	})
+}
+
+// PlayerProfiles devuelve los perfiles de los jugadores indicados que tienen uno. Lo
+// llaman las salas desde su bucle
+func (h *Hub) PlayerProfiles(playerIDs []string) map[string]models.PlayerProfile {
+	return h.profiles.Lookup(playerIDs)
+}
+
+// playerProfile devuelve el perfil de un jugador, o nil si no tiene
+func (h *Hub) playerProfile(playerID string) *models.PlayerProfile {
+	if p, ok := h.profiles.Get(playerID); ok {
+		return &p
+	}
+	return nil
+}
+
+// prunePlayers olvida el perfil, los amigos y la presencia elegida de los jugadores que no
+// se conectan desde hace más que la vigencia de las sesiones: ya no pueden recuperar su
+// identidad. Guarda además las fechas de última conexión. Solo se llama desde Run
+func (h *Hub) prunePlayers(now time.Time) {
+	if h.sessions == nil {
+		return
+	}
+
+	connected := make(map[string]bool, len(h.Clients))
+	for client := range h.Clients {
+		connected[client.GetID()] = true
+	}
+
+	// Quien solo aparece en las listas de amigos empieza a contar desde ahora
+	h.profiles.Remember(h.friends.Players(), now)
+	removed := h.profiles.Prune(now.Add(-h.sessions.TTL()), func(playerID string) bool {
+		return connected[playerID]
+	})
+	for _, playerID := range removed {
+		delete(h.presenceChoices, playerID)
+	}
+	if h.friends.Forget(removed) {
+		h.saveFriends()
+	}
+	h.saveProfiles()
+
+	if len(removed) > 0 {
+		logger.Info("Jugadores inactivos olvidados", logger.Fields{
+			"players": len(removed),
+		})
+	}
+}
+
+// saveProfiles guarda los perfiles en segundo plano
+func (h *Hub) saveProfiles() {
+	go func() {
+		if err := h.profiles.Save(); err != nil {
+			logger.Error("No se pudieron guardar los perfiles", logger.Fields{
+				"error": err.Error(),
+			})
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package hub
+
+import (
+	"sort"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/rating"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// SetRatings activa las puntuaciones de los jugadores. Debe llamarse antes de Run
+func (h *Hub) SetRatings(book *rating.Book) {
+	h.ratings = book
+}
+
+// playerRating devuelve la puntuación del jugador en una variante
+func (h *Hub) playerRating(playerID, variant string) float64 {
+	if h.ratings == nil {
+		return rating.DefaultRating
+	}
+
+	return h.ratings.Get(playerID, variant).Rating
+}
+
+// RateGame actualiza las puntuaciones de los dos jugadores de una partida puntuada y
+// devuelve los cambios. Lo llaman las salas al terminar la partida: el cálculo se hace en
+// memoria y la escritura en disco, en segundo plano. Un abandono cuenta como derrota
+func (h *Hub) RateGame(record models.GameRecord) map[string]models.RatingChange {
+	if h.ratings == nil || len(record.Players) != 2 {
+		return nil
+	}
+
+	players := make([]string, 0, 2)
+	for playerID := range record.Players {
+		players = append(players, playerID)
+	}
+	sort.Strings(players)
+	first, second := players[0], players[1]
+
+	var firstScore float64
+	switch {
+	case record.IsDraw:
+		firstScore = 0.5
+	case record.Winner == first:
+		firstScore = 1
+	case record.Winner == second:
+		firstScore = 0
+	default:
+		return nil
+	}
+
+	firstChange, secondChange := h.ratings.RecordGame(record.Variant, first, second, firstScore, time.Now())
+
+	if h.leaderboard != nil {
+		h.leaderboard.RecordResult(record.Variant, first, second, firstScore)
+	}
+
+	go func() {
+		if err := h.ratings.Save(record.Variant); err != nil {
+			logger.Error("No se pudieron guardar las puntuaciones", logger.Fields{
+				"variant": record.Variant,
+				"error":   err.Error(),
+			})
+		}
+		if h.leaderboard == nil {
+			return
+		}
+		if err := h.leaderboard.Save(); err != nil {
+			logger.Error("No se pudo guardar la clasificación", logger.Fields{
+				"error": err.Error(),
+			})
+		}
+	}()
+
+	logger.Info("Puntuaciones actualizadas", logger.Fields{
+		"gameID":      record.ID,
+		"variant":     record.Variant,
+		"reason":      record.Reason,
+		"first":       first,
+		"firstDelta":  firstChange.After.Rating - firstChange.Before.Rating,
+		"second":      second,
+		"secondDelta": secondChange.After.Rating - secondChange.Before.Rating,
+	})
+
+	return map[string]models.RatingChange{
+		first:  toRatingChange(firstChange),
+		second: toRatingChange(secondChange),
+	}
+}
+
+// toRatingChange convierte un cambio de puntuación al formato de los mensajes
+func toRatingChange(c rating.Change) models.RatingChange {
+	return models.RatingChange{
+		Before:     c.Before.Rating,
+		After:      c.After.Rating,
+		Delta:      c.After.Rating - c.Before.Rating,
+		Deviation:  c.After.Deviation,
+		Volatility: c.After.Volatility,
+	}
+}
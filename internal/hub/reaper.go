@@ -0,0 +1,103 @@
+package hub
+
+import (
+	"sync"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+)
+
+// DefaultReapInterval es cada cuánto se revisan las salas inactivas si no se configura otro valor
+const DefaultReapInterval = 10 * time.Second
+
+// DefaultReapPolicy devuelve los límites de vida por defecto de las salas inactivas
+func DefaultReapPolicy() room.ReapPolicy {
+	return room.ReapPolicy{
+		EmptyTTL:    30 * time.Second, // Margen para reconexiones durante navegación de páginas
+		WaitingTTL:  10 * time.Minute,
+		FinishedTTL: 2 * time.Minute,
+	}
+}
+
+// reaperStats acumula las salas eliminadas por el reaper. Solo se usa desde Run
+type reaperStats struct {
+	sweeps   int
+	reaped   int
+	byReason map[string]int
+}
+
+// SetReaper configura los límites de vida de las salas inactivas y cada cuánto se revisan.
+// Debe llamarse antes de Run
+func (h *Hub) SetReaper(policy room.ReapPolicy, interval time.Duration) {
+	h.reapPolicy = policy
+	h.reapInterval = interval
+
+	logger.Info("Reaper de salas configurado", logger.Fields{
+		"emptyTTL":    policy.EmptyTTL.String(),
+		"waitingTTL":  policy.WaitingTTL.String(),
+		"finishedTTL": policy.FinishedTTL.String(),
+		"interval":    interval.String(),
+	})
+}
+
+// reapRooms elimina las salas que han superado su límite de vida. Cada sala decide en su
+// propio bucle si debe eliminarse; se les pregunta a todas a la vez para que una sala
+// ocupada no retrase al resto. Solo se llama desde Run
+func (h *Hub) reapRooms() {
+	started := time.Now()
+	swept := make(map[string]int)
+
+	rooms := make([]*room.Room, 0, len(h.Rooms))
+	for _, r := range h.Rooms {
+		rooms = append(rooms, r)
+	}
+	reasons := make([]string, len(rooms))
+	var wg sync.WaitGroup
+	for i, r := range rooms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reasons[i] = r.Reapable(h.reapPolicy, started)
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range rooms {
+		reason := reasons[i]
+		if reason == "" {
+			continue
+		}
+
+		logger.Info("Eliminando sala inactiva", logger.Fields{
+			"roomID": r.ID,
+			"reason": reason,
+		})
+		h.removeRoom(r.ID)
+		swept[reason]++
+	}
+
+	h.reaperStats.sweeps++
+	reaped := 0
+	for reason, count := range swept {
+		h.reaperStats.byReason[reason] += count
+		reaped += count
+	}
+	h.reaperStats.reaped += reaped
+
+	fields := logger.Fields{
+		"reaped":      reaped,
+		"rooms":       len(h.Rooms),
+		"duration":    time.Since(started).String(),
+		"sweeps":      h.reaperStats.sweeps,
+		"totalReaped": h.reaperStats.reaped,
+		"totalEmpty":  h.reaperStats.byReason[room.ReapEmpty],
+		"totalWait":   h.reaperStats.byReason[room.ReapWaiting],
+		"totalFinish": h.reaperStats.byReason[room.ReapFinished],
+	}
+	if reaped > 0 {
+		logger.Info("Revisión de salas inactivas", fields)
+	} else {
+		logger.Debug("Revisión de salas inactivas", fields)
+	}
+}
@@ -0,0 +1,39 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/room"
+)
+
+// TestReapRooms verifica que el reaper elimine las salas vacías y conserve las ocupadas
+func TestReapRooms(t *testing.T) {
+	h := NewHub()
+	t.Cleanup(h.cancel)
+	h.SetReaper(room.ReapPolicy{EmptyTTL: time.Millisecond}, DefaultReapInterval)
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	for i := 0; i < 5; i++ {
+		if _, err := h.newRoom(settings); err != nil {
+			t.Fatalf("Error creando sala: %v", err)
+		}
+	}
+	ana := newFakeClient("ana")
+	occupied, err := h.newRoom(settings)
+	if err != nil {
+		t.Fatalf("Error creando sala: %v", err)
+	}
+	ana.SetRoom(occupied)
+	occupied.Register <- ana
+	waitUntil(t, "ana no llegó a sentarse", func() bool { return seatedIn(occupied.Info(), "ana") })
+
+	time.Sleep(5 * time.Millisecond)
+	h.reapRooms()
+	if len(h.Rooms) != 1 || h.Rooms[occupied.ID] == nil {
+		t.Errorf("Solo debería quedar la sala ocupada, quedan %d", len(h.Rooms))
+	}
+	if h.reaperStats.byReason[room.ReapEmpty] != 5 {
+		t.Errorf("Se esperaban 5 salas vacías eliminadas, obtenidas %d", h.reaperStats.byReason[room.ReapEmpty])
+	}
+}
@@ -0,0 +1,77 @@
+package hub
+
+import (
+	"encoding/json"
+
+	"nvivas/backend/tictactoe-go-server/internal/archive"
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// SetArchive activa el archivo de partidas terminadas. Debe llamarse antes de Run
+func (h *Hub) SetArchive(a *archive.Archive) {
+	h.archive = a
+}
+
+// ArchiveGame guarda el historial de una partida terminada. Lo llaman las salas al
+// terminar una partida, fuera de su bucle
+func (h *Hub) ArchiveGame(record models.GameRecord) {
+	if h.archive == nil {
+		return
+	}
+
+	if err := h.archive.Save(record); err != nil {
+		logger.Error("No se pudo archivar la partida", logger.Fields{
+			"gameID": record.ID,
+			"roomID": record.RoomID,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Partida archivada", logger.Fields{
+		"gameID": record.ID,
+		"roomID": record.RoomID,
+		"moves":  len(record.Moves),
+	})
+}
+
+// SendReplay envía al cliente el historial de una partida archivada (mensaje GET_REPLAY)
+func (h *Hub) SendReplay(client interfaces.Client, gameID string) {
+	if h.archive == nil {
+		errors.GameNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	record, found, err := h.archive.Get(gameID)
+	if err != nil {
+		logger.Error("No se pudo leer la partida archivada", logger.Fields{
+			"gameID":   gameID,
+			"clientID": client.GetID(),
+			"error":    err.Error(),
+		})
+		errors.Internal(client.GetSendChannel(), client.GetID())
+		return
+	}
+	if !found {
+		errors.GameNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	replayMsg := models.ReplayResponse{
+		Type: "REPLAY",
+		Game: record,
+	}
+	msgBytes, _ := json.Marshal(replayMsg)
+
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar REPLAY, canal posiblemente cerrado", logger.Fields{
+			"clientID": client.GetID(),
+			"gameID":   gameID,
+		})
+	}
+}
@@ -0,0 +1,530 @@
+package hub
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/tournament"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+const (
+	// DefaultNoShowTimeout es cuánto tiene un jugador para sentarse en su partida de torneo
+	DefaultNoShowTimeout = time.Minute
+
+	// tournamentCheckInterval es cada cuánto se revisan incomparecencias y partidas pendientes
+	tournamentCheckInterval = time.Second
+
+	// tournamentRetention es cuánto se conserva un torneo terminado para consultarlo
+	tournamentRetention = time.Hour
+)
+
+// TournamentRequest representa una acción de torneo de un cliente (CREATE_TOURNAMENT,
+// JOIN_TOURNAMENT, LEAVE_TOURNAMENT, START_TOURNAMENT, WATCH_TOURNAMENT,
+// UNWATCH_TOURNAMENT o LIST_TOURNAMENTS)
+type TournamentRequest struct {
+	Client       interfaces.Client
+	Type         string
+	TournamentID string
+	Create       models.CreateTournamentPayload // Solo en CREATE_TOURNAMENT, con la configuración ya validada
+}
+
+// tournamentGame es la sala en la que se juega una partida de torneo
+type tournamentGame struct {
+	tournamentID string
+	matchID      string
+	deadline     time.Time // Los jugadores ausentes pierden pasado este momento
+	seated       bool      // Los dos jugadores llegaron a sentarse
+	busy         []string  // Jugadores que estaban en otra partida y se sientan al terminarla
+}
+
+// SetTournaments configura cuánto tiene un jugador para presentarse a su partida de torneo.
+// Debe llamarse antes de Run
+func (h *Hub) SetTournaments(noShowTimeout time.Duration) {
+	h.noShowTimeout = noShowTimeout
+}
+
+// CreateTournament pide al Hub que abra un torneo organizado por el cliente (mensaje
+// CREATE_TOURNAMENT). La configuración de las salas debe venir ya validada
+func (h *Hub) CreateTournament(client interfaces.Client, payload models.CreateTournamentPayload) {
+	h.TournamentChan <- &TournamentRequest{
+		Client: client,
+		Type:   "CREATE_TOURNAMENT",
+		Create: payload,
+	}
+}
+
+// TournamentAction pide al Hub el resto de acciones de torneo de un cliente
+func (h *Hub) TournamentAction(client interfaces.Client, action string, tournamentID string) {
+	h.TournamentChan <- &TournamentRequest{
+		Client:       client,
+		Type:         action,
+		TournamentID: tournamentID,
+	}
+}
+
+// GameFinished recibe el resultado de una partida. Lo llaman las salas al terminar una
+// partida, fuera de su bucle; solo interesa si la partida es de un torneo
+func (h *Hub) GameFinished(record models.GameRecord) {
+	select {
+	case h.ResultChan <- record:
+	case <-h.ctx.Done():
+	}
+}
+
+// handleTournamentRequest atiende las acciones de torneo. Solo se llama desde Run
+func (h *Hub) handleTournamentRequest(req *TournamentRequest) {
+	client := req.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	if req.Type == "CREATE_TOURNAMENT" {
+		h.createTournament(client, req.Create)
+		return
+	}
+	if req.Type == "LIST_TOURNAMENTS" {
+		h.sendTournamentList(client)
+		return
+	}
+
+	t, ok := h.tournaments[req.TournamentID]
+	if !ok {
+		errors.TournamentNotFound(client.GetSendChannel(), client.GetID())
+		return
+	}
+
+	now := time.Now()
+	switch req.Type {
+	case "JOIN_TOURNAMENT":
+		if err := t.Register(client.GetID(), h.playerRating(client.GetID(), t.Settings.Variant)); err != nil {
+			h.sendTournamentError(client, err)
+			return
+		}
+		logger.Info("Jugador inscrito en torneo", logger.Fields{
+			"tournamentID": t.ID,
+			"clientID":     client.GetID(),
+			"players":      len(t.Players()),
+		})
+		// En una arena en juego, quien entra tarde se empareja en cuanto haya rival
+		h.afterTournamentChange(t, t.Tick(now), now)
+
+	case "LEAVE_TOURNAMENT":
+		if err := t.Unregister(client.GetID()); err != nil {
+			h.sendTournamentError(client, err)
+			return
+		}
+		logger.Info("Jugador retirado del torneo", logger.Fields{
+			"tournamentID": t.ID,
+			"clientID":     client.GetID(),
+		})
+		// Quien se retira ya no recibe las actualizaciones, salvo que lo observe: se le confirma
+		// aparte. En una arena sigue en la clasificación y las recibe igualmente
+		h.publishTournament(t)
+		if !h.tournamentWatchers[t.ID][client] && !t.IsPlayer(client.GetID()) {
+			h.sendTournamentUpdate(client, t)
+		}
+
+	case "START_TOURNAMENT":
+		if client.GetID() != t.OrganizerID {
+			errors.NotOrganizer(client.GetSendChannel(), client.GetID())
+			return
+		}
+		matches, err := t.Start(now)
+		if err != nil {
+			h.sendTournamentError(client, err)
+			return
+		}
+		logger.Info("Torneo empezado", logger.Fields{
+			"tournamentID": t.ID,
+			"format":       t.Format,
+			"players":      len(t.Players()),
+		})
+		h.startTournamentMatches(t, matches, now)
+		h.publishTournament(t)
+
+	case "WATCH_TOURNAMENT":
+		h.tournamentWatchers[t.ID][client] = true
+		h.sendTournamentUpdate(client, t)
+
+	case "UNWATCH_TOURNAMENT":
+		delete(h.tournamentWatchers[t.ID], client)
+		msgBytes, _ := json.Marshal(models.BaseMessage{Type: "TOURNAMENT_UNWATCHED"})
+		select {
+		case client.GetSendChannel() <- msgBytes:
+		default:
+		}
+	}
+}
+
+// createTournament abre un torneo; el organizador lo observa desde el principio
+func (h *Hub) createTournament(client interfaces.Client, payload models.CreateTournamentPayload) {
+	opts := tournament.Options{
+		MaxPlayers: payload.MaxPlayers,
+		Rounds:     payload.Rounds,
+		Duration:   time.Duration(payload.DurationMinutes) * time.Minute,
+	}
+	t, err := tournament.New(uuid.NewString(), payload.Name, payload.Format, client.GetID(), payload.Settings, opts, time.Now())
+	if err != nil {
+		errors.InvalidPayload(client.GetSendChannel(), "create tournament: "+err.Error(), client.GetID())
+		return
+	}
+
+	h.tournaments[t.ID] = t
+	h.tournamentWatchers[t.ID] = map[interfaces.Client]bool{client: true}
+	h.sendTournamentUpdate(client, t)
+
+	logger.Info("Torneo creado", logger.Fields{
+		"tournamentID": t.ID,
+		"organizerID":  client.GetID(),
+		"format":       t.Format,
+		"variant":      t.Settings.Variant,
+		"maxPlayers":   t.MaxPlayers,
+	})
+}
+
+// startTournamentMatches crea una sala para cada partida y sienta a los jugadores conectados.
+// Las salas de torneo son privadas y no cuentan para el límite de salas. Si no se puede
+// crear una sala, la partida queda pendiente y se reintenta en la siguiente revisión
+func (h *Hub) startTournamentMatches(t *tournament.Tournament, matches []*tournament.Match, now time.Time) {
+	for _, m := range matches {
+		settings := t.Settings
+		settings.Visibility = room.VisibilityPrivate
+		settings.PreferredSymbol = room.SymbolRandom
+
+		matchRoom, err := h.newRoom(settings)
+		if err != nil {
+			logger.Error("No se pudo crear la sala de la partida de torneo", logger.Fields{
+				"tournamentID": t.ID,
+				"matchID":      m.ID,
+				"error":        err.Error(),
+			})
+			continue
+		}
+
+		t.SetRoom(m.ID, matchRoom.ID)
+		game := &tournamentGame{
+			tournamentID: t.ID,
+			matchID:      m.ID,
+			deadline:     now.Add(h.noShowTimeout),
+		}
+		h.tournamentGames[matchRoom.ID] = game
+
+		for _, playerID := range []string{m.First, m.Second} {
+			if client := h.clientByID(playerID); client != nil {
+				h.seatTournamentPlayer(client, t, m, matchRoom, game)
+			}
+		}
+
+		logger.Info("Partida de torneo preparada", logger.Fields{
+			"tournamentID": t.ID,
+			"matchID":      m.ID,
+			"roomID":       matchRoom.ID,
+			"first":        m.First,
+			"second":       m.Second,
+			"game":         m.Games + 1,
+		})
+	}
+}
+
+// seatTournamentPlayer avisa al jugador de su partida con TOURNAMENT_MATCH y lo sienta en la
+// sala. Una partida de torneo cancela la búsqueda de partida rápida. Si el jugador está en
+// medio de otra partida no se le sienta: checkTournaments lo hará cuando termine, o perderá
+// por incomparecencia si no termina a tiempo
+func (h *Hub) seatTournamentPlayer(client interfaces.Client, t *tournament.Tournament, m *tournament.Match, matchRoom *room.Room, game *tournamentGame) {
+	opponentID := m.First
+	if opponentID == client.GetID() {
+		opponentID = m.Second
+	}
+
+	matchMsg := models.TournamentMatchResponse{
+		Type:         "TOURNAMENT_MATCH",
+		TournamentID: t.ID,
+		MatchID:      m.ID,
+		Round:        m.Round,
+		Game:         m.Games + 1,
+		RoomID:       matchRoom.ID,
+		RoomCode:     matchRoom.Code,
+		OpponentID:   opponentID,
+		Deadline:     game.deadline.UnixMilli(),
+	}
+	msgBytes, _ := json.Marshal(matchMsg)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar TOURNAMENT_MATCH, canal posiblemente cerrado", logger.Fields{
+			"clientID": client.GetID(),
+			"roomID":   matchRoom.ID,
+		})
+	}
+
+	if busyRoom := h.findActiveSeat(client.GetID()); busyRoom != nil && busyRoom != matchRoom {
+		logger.Info("Jugador de torneo ocupado en otra sala, se le sentará al terminar", logger.Fields{
+			"clientID": client.GetID(),
+			"roomID":   busyRoom.ID,
+			"matchID":  m.ID,
+		})
+		if !slices.Contains(game.busy, client.GetID()) {
+			game.busy = append(game.busy, client.GetID())
+		}
+		return
+	}
+
+	if h.leaveQueue(client) {
+		h.sendMatchCancelled(client)
+	}
+	h.cancelChallenges(client, challengeBusy)
+
+	client.SetRoom(matchRoom)
+	matchRoom.Register <- client
+}
+
+// seatWaitingPlayers sienta a los jugadores de una partida de torneo que estaban jugando
+// en otra sala y ya han terminado. Los desconectados se sientan al volver, en registerClient
+func (h *Hub) seatWaitingPlayers(t *tournament.Tournament, game *tournamentGame, matchRoom *room.Room) {
+	m := t.Match(game.matchID)
+	if m == nil || len(game.busy) == 0 {
+		return
+	}
+	waiting := game.busy
+	game.busy = nil
+	for _, playerID := range waiting {
+		seat := h.findActiveSeat(playerID)
+		client := h.clientByID(playerID)
+		switch {
+		case seat == matchRoom:
+			// Ya se sentó, por ejemplo al volver a conectarse
+		case seat != nil || client == nil:
+			game.busy = append(game.busy, playerID)
+		default:
+			h.seatTournamentPlayer(client, t, m, matchRoom, game)
+		}
+	}
+}
+
+// pendingTournamentSeat busca la partida de torneo a la que el jugador aún no se ha sentado
+func (h *Hub) pendingTournamentSeat(playerID string) (*tournament.Tournament, *tournament.Match, *room.Room, *tournamentGame) {
+	for roomID, game := range h.tournamentGames {
+		if game.seated {
+			continue
+		}
+		t := h.tournaments[game.tournamentID]
+		m := t.Match(game.matchID)
+		if m == nil || (m.First != playerID && m.Second != playerID) {
+			continue
+		}
+		if matchRoom, ok := h.Rooms[roomID]; ok {
+			return t, m, matchRoom, game
+		}
+	}
+	return nil, nil, nil, nil
+}
+
+// handleGameResult avanza el torneo con el resultado de una de sus partidas. La sala se
+// cierra: la siguiente partida se juega en una sala nueva, también si el resultado no se
+// puede anotar. Solo se llama desde Run
+func (h *Hub) handleGameResult(record models.GameRecord) {
+	game, ok := h.tournamentGames[record.RoomID]
+	if !ok {
+		return
+	}
+	delete(h.tournamentGames, record.RoomID)
+	h.removeRoom(record.RoomID)
+
+	t := h.tournaments[game.tournamentID]
+	now := time.Now()
+	next, err := t.Report(game.matchID, record.Winner, record.IsDraw, now)
+	if err != nil {
+		logger.Warn("Resultado de torneo descartado", logger.Fields{
+			"tournamentID": t.ID,
+			"matchID":      game.matchID,
+			"error":        err.Error(),
+		})
+		// La sala ya no existe: si la partida sigue abierta se repite en una sala nueva
+		if err := t.Replay(game.matchID); err == nil {
+			h.afterTournamentChange(t, []*tournament.Match{t.Match(game.matchID)}, now)
+		}
+		return
+	}
+
+	logger.Info("Resultado de torneo anotado", logger.Fields{
+		"tournamentID": t.ID,
+		"matchID":      game.matchID,
+		"winner":       record.Winner,
+		"isDraw":       record.IsDraw,
+		"reason":       record.Reason,
+	})
+	h.afterTournamentChange(t, next, now)
+}
+
+// checkTournaments decide las partidas de torneo a las que algún jugador no se presentó a
+// tiempo, reintenta las partidas sin sala y olvida los torneos terminados hace tiempo.
+// Solo se llama desde Run
+func (h *Hub) checkTournaments(now time.Time) {
+	for roomID, game := range h.tournamentGames {
+		t := h.tournaments[game.tournamentID]
+		matchRoom, exists := h.Rooms[roomID]
+
+		if !exists {
+			// La sala desapareció sin resultado (por ejemplo, la eliminó el reaper)
+			delete(h.tournamentGames, roomID)
+			if game.seated {
+				if err := t.Replay(game.matchID); err == nil {
+					h.afterTournamentChange(t, []*tournament.Match{t.Match(game.matchID)}, now)
+				}
+				continue
+			}
+			next, _ := t.NoShow(game.matchID, nil, now)
+			h.afterTournamentChange(t, next, now)
+			continue
+		}
+
+		if game.seated {
+			continue
+		}
+		seated := matchRoom.GetPlayerIDs()
+		if len(seated) == 2 {
+			game.seated = true
+			continue
+		}
+		if now.Before(game.deadline) {
+			h.seatWaitingPlayers(t, game, matchRoom)
+			continue
+		}
+
+		delete(h.tournamentGames, roomID)
+		h.removeRoom(roomID)
+		next, err := t.NoShow(game.matchID, seated, now)
+		if err != nil {
+			continue
+		}
+		logger.Info("Incomparecencia en partida de torneo", logger.Fields{
+			"tournamentID": t.ID,
+			"matchID":      game.matchID,
+			"present":      seated,
+		})
+		h.afterTournamentChange(t, next, now)
+	}
+
+	for id, t := range h.tournaments {
+		if t.Status == tournament.StatusRunning {
+			if pending := t.Pending(); len(pending) > 0 {
+				h.startTournamentMatches(t, pending, now)
+				h.publishTournament(t)
+			}
+			// Las arenas emparejan a quien espera y se cierran al acabar el tiempo
+			if next := t.Tick(now); len(next) > 0 || t.Status == tournament.StatusFinished {
+				h.afterTournamentChange(t, next, now)
+			}
+		}
+		if t.Status == tournament.StatusFinished && now.Sub(t.FinishedAt) > tournamentRetention {
+			delete(h.tournaments, id)
+			delete(h.tournamentWatchers, id)
+		}
+	}
+}
+
+// afterTournamentChange crea las salas de las partidas nuevas y publica el torneo
+func (h *Hub) afterTournamentChange(t *tournament.Tournament, next []*tournament.Match, now time.Time) {
+	h.startTournamentMatches(t, next, now)
+	h.publishTournament(t)
+
+	if t.Status == tournament.StatusFinished {
+		logger.Info("Torneo terminado", logger.Fields{
+			"tournamentID": t.ID,
+			"winner":       t.Winner,
+			"rounds":       t.Round,
+		})
+	}
+}
+
+// publishTournament envía TOURNAMENT_UPDATE al organizador, a los inscritos conectados y a
+// quienes observan el torneo
+func (h *Hub) publishTournament(t *tournament.Tournament) {
+	recipients := make(map[interfaces.Client]bool)
+	for client := range h.tournamentWatchers[t.ID] {
+		recipients[client] = true
+	}
+	playerIDs := []string{t.OrganizerID}
+	for _, p := range t.Players() {
+		playerIDs = append(playerIDs, p.ID)
+	}
+	for _, playerID := range playerIDs {
+		if client := h.clientByID(playerID); client != nil {
+			recipients[client] = true
+		}
+	}
+
+	msgBytes, _ := json.Marshal(t.Update())
+	for client := range recipients {
+		select {
+		case client.GetSendChannel() <- msgBytes:
+		default:
+		}
+	}
+}
+
+// sendTournamentUpdate envía el estado del torneo a un solo cliente
+func (h *Hub) sendTournamentUpdate(client interfaces.Client, t *tournament.Tournament) {
+	msgBytes, _ := json.Marshal(t.Update())
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// sendTournamentList envía TOURNAMENT_LIST, con los torneos más recientes primero
+func (h *Hub) sendTournamentList(client interfaces.Client) {
+	list := make([]*tournament.Tournament, 0, len(h.tournaments))
+	for _, t := range h.tournaments {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+
+	response := models.TournamentListResponse{
+		Type:        "TOURNAMENT_LIST",
+		Tournaments: make([]models.TournamentSummary, 0, len(list)),
+	}
+	for _, t := range list {
+		response.Tournaments = append(response.Tournaments, t.Summary())
+	}
+
+	msgBytes, _ := json.Marshal(response)
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+	}
+}
+
+// sendTournamentError traduce un error del torneo al error que se envía al cliente
+func (h *Hub) sendTournamentError(client interfaces.Client, err error) {
+	switch {
+	case stderrors.Is(err, tournament.ErrRegistrationClosed),
+		stderrors.Is(err, tournament.ErrAlreadyRegistered),
+		stderrors.Is(err, tournament.ErrNotRegistered),
+		stderrors.Is(err, tournament.ErrFull),
+		stderrors.Is(err, tournament.ErrNotEnoughPlayers):
+		errors.InvalidTournamentAction(client.GetSendChannel(), err.Error(), client.GetID())
+	default:
+		errors.Internal(client.GetSendChannel(), client.GetID())
+	}
+}
+
+// unwatchTournaments deja de enviar actualizaciones de torneo a un cliente que se desconecta.
+// Los inscritos siguen recibiéndolas al volver, porque se buscan por su ID
+func (h *Hub) unwatchTournaments(client interfaces.Client) {
+	for _, watchers := range h.tournamentWatchers {
+		delete(watchers, client)
+	}
+}
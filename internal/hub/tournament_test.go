@@ -0,0 +1,259 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/room"
+	"nvivas/backend/tictactoe-go-server/internal/tournament"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// startTournament crea un Hub con los clientes conectados y un torneo de eliminación directa
+// organizado por "org" con los jugadores indicados inscritos. No arranca Run: las pruebas llaman a los
+// manejadores y a checkTournaments directamente
+func startTournament(t *testing.T, players []string, clients ...*fakeClient) (*Hub, *tournament.Tournament) {
+	t.Helper()
+	h := NewHub()
+	t.Cleanup(h.cancel)
+
+	org := newFakeClient("org")
+	h.Clients[org] = true
+	for _, c := range clients {
+		h.Clients[c] = true
+	}
+
+	settings, _ := room.ValidateSettings(room.DefaultSettings())
+	h.handleTournamentRequest(&TournamentRequest{
+		Client: org,
+		Type:   "CREATE_TOURNAMENT",
+		Create: models.CreateTournamentPayload{Name: "Copa", Format: "single-elimination", Settings: settings},
+	})
+	var tour *tournament.Tournament
+	for _, created := range h.tournaments {
+		tour = created
+	}
+	if tour == nil {
+		t.Fatal("No se creó el torneo")
+	}
+	for _, id := range players {
+		if err := tour.Register(id, 0); err != nil {
+			t.Fatalf("Error inscribiendo a %s: %v", id, err)
+		}
+	}
+	return h, tour
+}
+
+// startAndWait empieza el torneo y espera a que los clientes indicados estén sentados en
+// sus salas. Devuelve la sala de cada jugador
+func startAndWait(t *testing.T, h *Hub, tour *tournament.Tournament, clients ...*fakeClient) map[string]*room.Room {
+	t.Helper()
+	h.handleTournamentRequest(&TournamentRequest{Client: h.clientByID("org"), Type: "START_TOURNAMENT", TournamentID: tour.ID})
+	return waitSeated(t, h, clients...)
+}
+
+// waitSeated espera a que los clientes estén sentados en salas de torneo y las devuelve
+func waitSeated(t *testing.T, h *Hub, clients ...*fakeClient) map[string]*room.Room {
+	t.Helper()
+	rooms := make(map[string]*room.Room)
+	for _, c := range clients {
+		matchMsg := waitForMessage(t, c, "TOURNAMENT_MATCH")
+		roomID, _ := matchMsg["roomId"].(string)
+		matchRoom, exists := h.Rooms[roomID]
+		if !exists {
+			t.Fatalf("TOURNAMENT_MATCH de %s apunta a una sala inexistente: %q", c.id, roomID)
+		}
+		waitUntil(t, c.id+" no llegó a sentarse", func() bool { return seatedIn(matchRoom.Info(), c.id) })
+		rooms[c.id] = matchRoom
+	}
+	return rooms
+}
+
+// playTournamentGame juega la partida de la sala entre a y b, la gana quien mueve primero, y
+// entrega el resultado al Hub como haría Run. Devuelve el ID del ganador
+func playTournamentGame(t *testing.T, h *Hub, r *room.Room, a, b *fakeClient) string {
+	t.Helper()
+	start := waitForMessage(t, a, "GAME_START")
+	players, _ := start["players"].(map[string]interface{})
+	x, o := a, b
+	if players[b.id] == start["currentTurn"] {
+		x, o = b, a
+	}
+	winGame(t, r, x, o)
+
+	select {
+	case record := <-h.ResultChan:
+		h.handleGameResult(record)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("La sala %s no entregó el resultado", r.ID)
+	}
+	return x.id
+}
+
+// tournamentRoom devuelve la sala de la única partida de torneo en juego
+func tournamentRoom(t *testing.T, h *Hub) *room.Room {
+	t.Helper()
+	if len(h.tournamentGames) != 1 {
+		t.Fatalf("Se esperaba una partida de torneo, hay %d", len(h.tournamentGames))
+	}
+	for roomID := range h.tournamentGames {
+		return h.Rooms[roomID]
+	}
+	return nil
+}
+
+// TestTournamentWaitsForBusyPlayer verifica que un jugador en medio de otra partida no se
+// siente en la de torneo hasta terminarla
+func TestTournamentWaitsForBusyPlayer(t *testing.T) {
+	ana, bea, carl := newFakeClient("ana"), newFakeClient("bea"), newFakeClient("carl")
+	h, tour := startTournament(t, []string{"ana", "bea"}, ana, bea, carl)
+
+	casual := startGame(t, h, bea, carl)
+	h.handleTournamentRequest(&TournamentRequest{Client: h.clientByID("org"), Type: "START_TOURNAMENT", TournamentID: tour.ID})
+
+	matchRoom := tournamentRoom(t, h)
+	waitForMessage(t, bea, "TOURNAMENT_MATCH")
+	waitUntil(t, "ana no llegó a sentarse", func() bool { return seatedIn(matchRoom.Info(), "ana") })
+	if bea.GetRoom() != casual || seatedIn(matchRoom.Info(), "bea") {
+		t.Fatal("bea no debería sentarse mientras juega otra partida")
+	}
+
+	// Mientras la otra partida sigue, la revisión no la mueve
+	h.checkTournaments(time.Now())
+	if bea.GetRoom() != casual {
+		t.Fatal("bea no debería sentarse antes de terminar su partida")
+	}
+
+	winGame(t, casual, bea, carl)
+	h.checkTournaments(time.Now())
+	waitUntil(t, "bea no llegó a sentarse al terminar", func() bool { return seatedIn(matchRoom.Info(), "bea") })
+	if bea.GetRoom() != matchRoom {
+		t.Error("bea debería estar en la sala del torneo")
+	}
+}
+
+// TestTournamentReplaysRejectedResult verifica que un resultado que el torneo no acepta no
+// deje la partida colgada: se repite en una sala nueva
+func TestTournamentReplaysRejectedResult(t *testing.T) {
+	ana, bea := newFakeClient("ana"), newFakeClient("bea")
+	h, tour := startTournament(t, []string{"ana", "bea"}, ana, bea)
+	h.handleTournamentRequest(&TournamentRequest{Client: h.clientByID("org"), Type: "START_TOURNAMENT", TournamentID: tour.ID})
+
+	matchRoom := tournamentRoom(t, h)
+	h.handleGameResult(models.GameRecord{RoomID: matchRoom.ID, Winner: "carl"})
+
+	replayRoom := tournamentRoom(t, h)
+	if replayRoom == nil || replayRoom.ID == matchRoom.ID {
+		t.Fatal("La partida debería repetirse en una sala nueva")
+	}
+	if _, exists := h.Rooms[matchRoom.ID]; exists {
+		t.Error("La sala anterior debería haberse cerrado")
+	}
+	for _, c := range []*fakeClient{ana, bea} {
+		waitUntil(t, c.id+" no llegó a la sala nueva", func() bool { return c.GetRoom() == replayRoom })
+	}
+}
+
+// TestTournamentRoundsAdvance verifica que cada partida tenga su propia sala privada y que
+// los resultados de GAME_OVER lleven el torneo a la ronda siguiente hasta el final
+func TestTournamentRoundsAdvance(t *testing.T) {
+	clients := []*fakeClient{newFakeClient("ana"), newFakeClient("bea"), newFakeClient("carl"), newFakeClient("dan")}
+	h, tour := startTournament(t, []string{"ana", "bea", "carl", "dan"}, clients...)
+	byID := make(map[string]*fakeClient)
+	for _, c := range clients {
+		byID[c.id] = c
+	}
+
+	rooms := startAndWait(t, h, tour, clients...)
+	if len(h.Rooms) != 2 || len(h.tournamentGames) != 2 {
+		t.Fatalf("Se esperaba una sala por partida: %d salas, %d partidas", len(h.Rooms), len(h.tournamentGames))
+	}
+
+	var finalists []*fakeClient
+	played := make(map[*room.Room]bool)
+	for _, c := range clients {
+		matchRoom := rooms[c.id]
+		if played[matchRoom] {
+			continue
+		}
+		played[matchRoom] = true
+		if matchRoom.Settings.Visibility != room.VisibilityPrivate {
+			t.Errorf("La sala %s del torneo debería ser privada", matchRoom.ID)
+		}
+		players := matchRoom.GetPlayerIDs()
+		if len(players) != 2 {
+			t.Fatalf("La sala %s debería tener dos jugadores: %v", matchRoom.ID, players)
+		}
+		winner := playTournamentGame(t, h, matchRoom, byID[players[0]], byID[players[1]])
+		finalists = append(finalists, byID[winner])
+		if _, exists := h.Rooms[matchRoom.ID]; exists {
+			t.Errorf("La sala %s debería cerrarse tras el resultado", matchRoom.ID)
+		}
+	}
+
+	if tour.Round != 2 {
+		t.Fatalf("Se esperaba la ronda 2, obtenida %d", tour.Round)
+	}
+	final := waitSeated(t, h, finalists...)
+	champion := playTournamentGame(t, h, final[finalists[0].id], finalists[0], finalists[1])
+
+	if tour.Status != tournament.StatusFinished || tour.Winner != champion {
+		t.Errorf("El torneo debería terminar con %s como ganador: estado %s, ganador %q", champion, tour.Status, tour.Winner)
+	}
+	if len(h.Rooms) != 0 || len(h.tournamentGames) != 0 {
+		t.Errorf("No deberían quedar salas: %d salas, %d partidas", len(h.Rooms), len(h.tournamentGames))
+	}
+}
+
+// TestTournamentNoShow verifica que, pasado el plazo, gane el jugador presente y se cierre
+// la sala
+func TestTournamentNoShow(t *testing.T) {
+	ana := newFakeClient("ana")
+	h, tour := startTournament(t, []string{"ana", "bea"}, ana)
+
+	rooms := startAndWait(t, h, tour, ana)
+	matchRoom := rooms["ana"]
+
+	// Antes del plazo la partida sigue esperando a bea
+	h.checkTournaments(time.Now())
+	if _, exists := h.Rooms[matchRoom.ID]; !exists || tour.Status == tournament.StatusFinished {
+		t.Fatal("La partida no debería decidirse antes del plazo")
+	}
+
+	h.checkTournaments(time.Now().Add(h.noShowTimeout + time.Second))
+	if tour.Status != tournament.StatusFinished || tour.Winner != "ana" {
+		t.Errorf("ana debería ganar por incomparecencia: estado %s, ganador %q", tour.Status, tour.Winner)
+	}
+	if matches := tour.Update().Matches; len(matches) != 1 || matches[0].Result != tournament.ResultNoShow {
+		t.Errorf("Se esperaba una partida decidida por incomparecencia: %+v", matches)
+	}
+	if _, exists := h.Rooms[matchRoom.ID]; exists {
+		t.Error("La sala debería cerrarse tras la incomparecencia")
+	}
+}
+
+// TestTournamentRoomReaped verifica que una partida cuya sala desaparece a mitad de juego
+// se repita en una sala nueva con los mismos jugadores
+func TestTournamentRoomReaped(t *testing.T) {
+	ana, bea := newFakeClient("ana"), newFakeClient("bea")
+	h, tour := startTournament(t, []string{"ana", "bea"}, ana, bea)
+
+	rooms := startAndWait(t, h, tour, ana, bea)
+	matchRoom := rooms["ana"]
+	h.checkTournaments(time.Now())
+	if game := h.tournamentGames[matchRoom.ID]; game == nil || !game.seated {
+		t.Fatal("La partida debería constar como empezada")
+	}
+
+	// El reaper elimina la sala sin que haya resultado
+	h.removeRoom(matchRoom.ID)
+	h.checkTournaments(time.Now())
+
+	replay := waitSeated(t, h, ana, bea)
+	if replay["ana"] != replay["bea"] || replay["ana"].ID == matchRoom.ID {
+		t.Fatal("La partida debería repetirse en una sala nueva para los dos")
+	}
+	if tour.Status != tournament.StatusRunning {
+		t.Errorf("El torneo debería seguir en juego, estado %s", tour.Status)
+	}
+}
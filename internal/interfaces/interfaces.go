@@ -1,6 +1,10 @@
 package interfaces
 
-import "github.com/gorilla/websocket"
+import (
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
 
 // Hub defines the interface for hub operations needed by clients
 type Hub interface {
@@ -8,16 +12,19 @@ type Hub interface {
 	UnregisterClient(client Client)
 
 	// CreateRoom creates a new room with the client as the first player
-	CreateRoom(client Client)
+	CreateRoom(client Client, settings models.RoomSettings)
 
 	// JoinRoom adds a client to an existing room
 	JoinRoom(roomID string, client Client)
 
+	// SpectateRoom adds a client to an existing room as a spectator
+	SpectateRoom(roomID string, client Client)
+
 	// DeleteRoom removes a room from the hub
 	DeleteRoom(roomID string)
 
-	// ListRooms sends the list of available rooms to the client
-	ListRooms(client Client)
+	// ListRooms sends a filtered, sorted page of the public rooms to the client
+	ListRooms(client Client, query models.ListRoomsPayload)
 }
 
 // Client defines the interface for client operations needed by the hub
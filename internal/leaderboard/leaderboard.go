@@ -0,0 +1,410 @@
+// Package leaderboard clasifica a los jugadores por temporadas a partir de sus partidas
+// puntuadas y archiva la clasificación final de cada temporada
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/rating"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Tamaño de las páginas de la clasificación
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Nombres de los documentos en el almacén
+const (
+	currentDocument = "current"
+	seasonPrefix    = "season-"
+)
+
+// ErrSeasonNotFound indica que la temporada pedida no existe
+var ErrSeasonNotFound = errors.New("temporada no encontrada")
+
+// Season es una temporada de la clasificación
+type Season struct {
+	Number    int   `json:"number"`
+	StartedAt int64 `json:"startedAt"` // Unix milliseconds
+	EndsAt    int64 `json:"endsAt"`    // Unix milliseconds
+}
+
+// Stats son los resultados de un jugador en la temporada
+type Stats struct {
+	Games  int `json:"games"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+// Points son los puntos de la temporada: 1 por victoria y 0.5 por tablas
+func (s Stats) Points() float64 {
+	return float64(s.Wins) + float64(s.Draws)/2
+}
+
+// current es la temporada en curso tal y como se guarda en disco
+type current struct {
+	Season Season                      `json:"season"`
+	Stats  map[string]map[string]Stats `json:"stats"` // variante -> jugador -> resultados
+}
+
+// Final es la clasificación final archivada de una temporada
+type Final struct {
+	Season    Season                               `json:"season"`
+	Standings map[string][]models.LeaderboardEntry `json:"standings"` // Por variante, ya ordenada
+}
+
+// ranking es una clasificación ya ordenada de la temporada en curso, válida mientras no
+// cambien los resultados
+type ranking struct {
+	version   int
+	standings []models.LeaderboardEntry // Compartida entre consultas: no se modifica
+}
+
+// Board mantiene la clasificación de la temporada en curso. Las puntuaciones salen del
+// registro de puntuaciones; solo aparecen los jugadores con partidas puntuadas en la
+// temporada. Es seguro para uso concurrente: las clasificaciones se ordenan y los
+// archivos se leen sin el cerrojo, y se reutilizan hasta la siguiente partida
+type Board struct {
+	store    *store.FileStore // nil: solo memoria
+	ratings  *rating.Book
+	schedule Schedule
+
+	mu       sync.Mutex
+	current  current
+	version  int                // Aumenta con cada resultado y con cada cambio de temporada
+	rankings map[string]ranking // variante -> última clasificación calculada
+	archived map[int]Final
+
+	saveMu sync.Mutex // Serializa las escrituras para que la última gane
+}
+
+// New crea la clasificación y recupera la temporada en curso del almacén. Si no hay
+// ninguna, empieza la primera en now
+func New(s *store.FileStore, ratings *rating.Book, schedule Schedule, now time.Time) (*Board, error) {
+	b := &Board{
+		store:    s,
+		ratings:  ratings,
+		schedule: schedule,
+		rankings: make(map[string]ranking),
+		archived: make(map[int]Final),
+	}
+
+	if s != nil {
+		found, err := s.Load(currentDocument, &b.current)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			if b.current.Stats == nil {
+				b.current.Stats = make(map[string]map[string]Stats)
+			}
+			return b, nil
+		}
+	}
+
+	b.current = current{
+		Season: Season{
+			Number:    1,
+			StartedAt: now.UnixMilli(),
+			EndsAt:    schedule.Next(now).UnixMilli(),
+		},
+		Stats: make(map[string]map[string]Stats),
+	}
+	return b, nil
+}
+
+// Season devuelve la temporada en curso
+func (b *Board) Season() Season {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current.Season
+}
+
+// RecordResult anota una partida puntuada. firstScore es el resultado del primero:
+// 1 victoria, 0.5 tablas, 0 derrota
+func (b *Board) RecordResult(variant, first, second string, firstScore float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.version++
+	stats := b.current.Stats[variant]
+	if stats == nil {
+		stats = make(map[string]Stats)
+		b.current.Stats[variant] = stats
+	}
+
+	record := func(playerID string, score float64) {
+		s := stats[playerID]
+		s.Games++
+		switch score {
+		case 1:
+			s.Wins++
+		case 0:
+			s.Losses++
+		default:
+			s.Draws++
+		}
+		stats[playerID] = s
+	}
+	record(first, firstScore)
+	record(second, 1-firstScore)
+}
+
+// Query devuelve una página de la clasificación de una variante. season 0 es la temporada
+// en curso. Si playerID está clasificado, su posición se incluye aunque no esté en la página
+func (b *Board) Query(variant string, season, offset, limit int, playerID string) (models.LeaderboardResponse, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	currentSeason := b.Season()
+	var info Season
+	var standings []models.LeaderboardEntry
+	var err error
+	if season == 0 || season == currentSeason.Number {
+		info, standings = b.standings(variant)
+	} else {
+		var final Final
+		final, err = b.final(season)
+		info, standings = final.Season, final.Standings[variant]
+	}
+	if err != nil {
+		return models.LeaderboardResponse{}, err
+	}
+
+	response := models.LeaderboardResponse{
+		Type:    "LEADERBOARD",
+		Variant: variant,
+		Season: models.SeasonInfo{
+			Number:    info.Number,
+			StartedAt: info.StartedAt,
+			EndsAt:    info.EndsAt,
+			Current:   info.Number == currentSeason.Number,
+		},
+		Entries: []models.LeaderboardEntry{},
+		Total:   len(standings),
+		Offset:  offset,
+		Limit:   limit,
+	}
+	if offset < len(standings) {
+		end := offset + limit
+		if end > len(standings) {
+			end = len(standings)
+		}
+		response.Entries = append(response.Entries, standings[offset:end]...)
+	}
+	for i := range standings {
+		if standings[i].PlayerID == playerID {
+			own := standings[i]
+			response.You = &own
+			break
+		}
+	}
+	return response, nil
+}
+
+// RolloverIfDue cierra la temporada en curso si ha terminado: archiva su clasificación
+// final y empieza la siguiente sin resultados. Las puntuaciones se conservan. Si el
+// servidor estuvo parado varias temporadas, se archivan también las intermedias (vacías).
+// Devuelve las temporadas cerradas
+func (b *Board) RolloverIfDue(now time.Time) ([]Season, error) {
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	b.mu.Lock()
+	var closed []Final
+	for now.UnixMilli() >= b.current.Season.EndsAt {
+		final := Final{
+			Season:    b.current.Season,
+			Standings: make(map[string][]models.LeaderboardEntry),
+		}
+		for variant, stats := range b.current.Stats {
+			final.Standings[variant] = b.rank(variant, stats)
+		}
+		b.archived[final.Season.Number] = final
+		closed = append(closed, final)
+
+		ended := time.UnixMilli(b.current.Season.EndsAt)
+		b.current = current{
+			Season: Season{
+				Number:    b.current.Season.Number + 1,
+				StartedAt: ended.UnixMilli(),
+				EndsAt:    b.schedule.Next(ended).UnixMilli(),
+			},
+			Stats: make(map[string]map[string]Stats),
+		}
+		b.version++
+		b.rankings = make(map[string]ranking)
+	}
+	snapshot := b.snapshot()
+	b.mu.Unlock()
+
+	if len(closed) == 0 {
+		return nil, nil
+	}
+
+	seasons := make([]Season, 0, len(closed))
+	for _, final := range closed {
+		seasons = append(seasons, final.Season)
+		if b.store != nil {
+			if err := b.store.Save(fmt.Sprintf("%s%d", seasonPrefix, final.Season.Number), final); err != nil {
+				return seasons, err
+			}
+		}
+	}
+	if b.store != nil {
+		if err := b.store.Save(currentDocument, snapshot); err != nil {
+			return seasons, err
+		}
+	}
+	return seasons, nil
+}
+
+// Save escribe en disco la temporada en curso
+func (b *Board) Save() error {
+	if b.store == nil {
+		return nil
+	}
+
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	b.mu.Lock()
+	snapshot := b.snapshot()
+	b.mu.Unlock()
+
+	return b.store.Save(currentDocument, snapshot)
+}
+
+// snapshot copia la temporada en curso para escribirla sin el cerrojo. Se llama con b.mu tomado
+func (b *Board) snapshot() current {
+	snapshot := current{
+		Season: b.current.Season,
+		Stats:  make(map[string]map[string]Stats, len(b.current.Stats)),
+	}
+	for variant, stats := range b.current.Stats {
+		copied := make(map[string]Stats, len(stats))
+		for playerID, s := range stats {
+			copied[playerID] = s
+		}
+		snapshot.Stats[variant] = copied
+	}
+	return snapshot
+}
+
+// standings devuelve la temporada en curso y su clasificación en una variante. Reutiliza
+// la última calculada si no ha habido partidas desde entonces; si no, la calcula sin el
+// cerrojo a partir de una copia de los resultados
+func (b *Board) standings(variant string) (Season, []models.LeaderboardEntry) {
+	b.mu.Lock()
+	season, version := b.current.Season, b.version
+	if cached, ok := b.rankings[variant]; ok && cached.version == version {
+		b.mu.Unlock()
+		return season, cached.standings
+	}
+	stats := make(map[string]Stats, len(b.current.Stats[variant]))
+	for playerID, s := range b.current.Stats[variant] {
+		stats[playerID] = s
+	}
+	b.mu.Unlock()
+
+	standings := b.rank(variant, stats)
+
+	// Si entretanto llegó otra partida, la siguiente consulta vuelve a calcularla
+	b.mu.Lock()
+	if b.version == version {
+		b.rankings[variant] = ranking{version: version, standings: standings}
+	}
+	b.mu.Unlock()
+	return season, standings
+}
+
+// rank ordena a los jugadores con resultados en una variante: por los puntos de la
+// temporada, después por puntuación, por partidas jugadas y por último por ID. La
+// puntuación no se reinicia entre temporadas, así que solo sirve para desempatar. No
+// necesita b.mu: las puntuaciones tienen su propio cerrojo
+func (b *Board) rank(variant string, stats map[string]Stats) []models.LeaderboardEntry {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	ratings := b.ratings.Standings(variant)
+
+	standings := make([]models.LeaderboardEntry, 0, len(stats))
+	for playerID, s := range stats {
+		r, ok := ratings[playerID]
+		if !ok {
+			r = rating.New()
+		}
+		standings = append(standings, models.LeaderboardEntry{
+			PlayerID:  playerID,
+			Rating:    r.Rating,
+			Deviation: r.Deviation,
+			Points:    s.Points(),
+			Games:     s.Games,
+			Wins:      s.Wins,
+			Losses:    s.Losses,
+			Draws:     s.Draws,
+		})
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, c := standings[i], standings[j]
+		if a.Points != c.Points {
+			return a.Points > c.Points
+		}
+		if a.Rating != c.Rating {
+			return a.Rating > c.Rating
+		}
+		if a.Games != c.Games {
+			return a.Games > c.Games
+		}
+		return a.PlayerID < c.PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// final devuelve la clasificación archivada de una temporada cerrada. La primera vez la
+// lee del almacén, sin el cerrojo
+func (b *Board) final(season int) (Final, error) {
+	b.mu.Lock()
+	final, ok := b.archived[season]
+	currentNumber := b.current.Season.Number
+	b.mu.Unlock()
+	if ok {
+		return final, nil
+	}
+	if season <= 0 || season > currentNumber || b.store == nil {
+		return Final{}, ErrSeasonNotFound
+	}
+
+	found, err := b.store.Load(fmt.Sprintf("%s%d", seasonPrefix, season), &final)
+	if err != nil {
+		return Final{}, err
+	}
+	if !found {
+		return Final{}, ErrSeasonNotFound
+	}
+
+	b.mu.Lock()
+	b.archived[season] = final
+	b.mu.Unlock()
+	return final, nil
+}
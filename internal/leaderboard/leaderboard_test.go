@@ -0,0 +1,218 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/rating"
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+// TestScheduleNext verifica el final de temporada de cada calendario
+func TestScheduleNext(t *testing.T) {
+	start := time.Date(2026, time.October, 18, 15, 30, 0, 0, time.UTC) // Domingo
+
+	cases := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"monthly", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly", time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		{"72h", start.Add(72 * time.Hour)},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.schedule)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%s): %v", c.schedule, err)
+		}
+		if got := s.Next(start); !got.Equal(c.want) {
+			t.Errorf("%s: Next = %v, se esperaba %v", c.schedule, got, c.want)
+		}
+	}
+
+	// Un lunes la temporada semanal dura la semana entera
+	monday := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	weekly, _ := ParseSchedule("weekly")
+	if got := weekly.Next(monday); !got.Equal(monday.AddDate(0, 0, 7)) {
+		t.Errorf("weekly desde lunes: %v", got)
+	}
+	december, _ := ParseSchedule("monthly")
+	if got := december.Next(time.Date(2026, time.December, 5, 0, 0, 0, 0, time.UTC)); got.Year() != 2027 || got.Month() != time.January {
+		t.Errorf("monthly en diciembre: %v", got)
+	}
+
+	for _, invalid := range []string{"", "yearly", "10s", "-1h"} {
+		if _, err := ParseSchedule(invalid); err == nil {
+			t.Errorf("ParseSchedule(%q) debería fallar", invalid)
+		}
+	}
+}
+
+// playGame puntúa una partida y la anota en la clasificación
+func playGame(t *testing.T, book *rating.Book, board *Board, first, second string, firstScore float64) {
+	t.Helper()
+	book.RecordGame("classic", first, second, firstScore, time.Now())
+	board.RecordResult("classic", first, second, firstScore)
+}
+
+// TestBoardQuery verifica el orden, la paginación y la posición propia
+func TestBoardQuery(t *testing.T) {
+	book, _ := rating.NewBook(nil, nil)
+	schedule, _ := ParseSchedule("monthly")
+	board, err := New(nil, book, schedule, time.Now())
+	if err != nil {
+		t.Fatalf("Error creando clasificación: %v", err)
+	}
+
+	playGame(t, book, board, "ana", "bob", 1)
+	playGame(t, book, board, "ana", "carl", 1)
+	playGame(t, book, board, "bob", "carl", 0.5)
+
+	page, err := board.Query("classic", 0, 0, 2, "carl")
+	if err != nil {
+		t.Fatalf("Error consultando: %v", err)
+	}
+	if page.Total != 3 || len(page.Entries) != 2 || page.Limit != 2 || !page.Season.Current || page.Season.Number != 1 {
+		t.Fatalf("Página incorrecta: %+v", page)
+	}
+	if page.Entries[0].PlayerID != "ana" || page.Entries[0].Rank != 1 || page.Entries[0].Wins != 2 {
+		t.Errorf("Primer puesto incorrecto: %+v", page.Entries[0])
+	}
+	if page.You == nil || page.You.PlayerID != "carl" || page.You.Draws != 1 || page.You.Losses != 1 {
+		t.Errorf("Posición propia incorrecta: %+v", page.You)
+	}
+
+	second, _ := board.Query("classic", 0, 2, 2, "nobody")
+	if len(second.Entries) != 1 || second.Entries[0].Rank != 3 || second.You != nil {
+		t.Errorf("Segunda página incorrecta: %+v", second)
+	}
+	beyond, _ := board.Query("classic", 0, 10, 0, "")
+	if len(beyond.Entries) != 0 || beyond.Limit != DefaultPageSize {
+		t.Errorf("Página fuera de rango incorrecta: %+v", beyond)
+	}
+	if empty, _ := board.Query("gomoku", 0, 0, 10, "ana"); empty.Total != 0 || empty.You != nil {
+		t.Errorf("Las variantes deberían clasificarse por separado: %+v", empty)
+	}
+	if _, err := board.Query("classic", 7, 0, 10, ""); err != ErrSeasonNotFound {
+		t.Errorf("Se esperaba ErrSeasonNotFound, obtenido %v", err)
+	}
+}
+
+// TestBoardRollover verifica que el cambio de temporada archive la clasificación final,
+// empiece la nueva vacía y sobreviva a un reinicio
+func TestBoardRollover(t *testing.T) {
+	s, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creando almacén: %v", err)
+	}
+	book, _ := rating.NewBook(nil, nil)
+	schedule, _ := ParseSchedule("1h")
+	start := time.Now()
+	board, _ := New(s, book, schedule, start)
+
+	playGame(t, book, board, "ana", "bob", 1)
+
+	if closed, err := board.RolloverIfDue(start.Add(30 * time.Minute)); err != nil || len(closed) != 0 {
+		t.Fatalf("La temporada aún no debería cerrarse: %v, %v", closed, err)
+	}
+
+	// El servidor estuvo parado más de una temporada
+	closed, err := board.RolloverIfDue(start.Add(150 * time.Minute))
+	if err != nil || len(closed) != 2 || closed[0].Number != 1 || closed[1].Number != 2 {
+		t.Fatalf("Se esperaban cerradas las temporadas 1 y 2: %+v, %v", closed, err)
+	}
+	if current := board.Season(); current.Number != 3 || current.StartedAt != closed[1].EndsAt {
+		t.Errorf("Temporada en curso incorrecta: %+v", current)
+	}
+
+	if now, _ := board.Query("classic", 0, 0, 10, "ana"); now.Total != 0 || now.You != nil {
+		t.Errorf("La nueva temporada debería empezar vacía: %+v", now)
+	}
+
+	// Tras reiniciar, la clasificación final sigue disponible
+	reopened, err := New(s, book, schedule, time.Now())
+	if err != nil {
+		t.Fatalf("Error reabriendo: %v", err)
+	}
+	final, err := reopened.Query("classic", 1, 0, 10, "bob")
+	if err != nil {
+		t.Fatalf("Error consultando la temporada archivada: %v", err)
+	}
+	if final.Season.Current || final.Total != 2 || final.Entries[0].PlayerID != "ana" || final.You == nil || final.You.Rank != 2 {
+		t.Errorf("Clasificación final incorrecta: %+v", final)
+	}
+	if reopened.Season().Number != 3 {
+		t.Errorf("La temporada en curso no se recuperó: %+v", reopened.Season())
+	}
+}
+
+// TestSeasonRanksByPoints verifica que la clasificación de una temporada nueva dependa de
+// sus resultados y no de la puntuación acumulada en temporadas anteriores
+func TestSeasonRanksByPoints(t *testing.T) {
+	book, _ := rating.NewBook(nil, nil)
+	schedule, _ := ParseSchedule("1h")
+	start := time.Now()
+	board, _ := New(nil, book, schedule, start)
+
+	// ana acumula puntuación en la primera temporada
+	for i := 0; i < 15; i++ {
+		playGame(t, book, board, "ana", "bob", 1)
+	}
+	if _, err := board.RolloverIfDue(start.Add(90 * time.Minute)); err != nil {
+		t.Fatalf("Error cerrando la temporada: %v", err)
+	}
+
+	// En la nueva, carl gana sus dos partidas y ana solo hace tablas
+	playGame(t, book, board, "carl", "dan", 1)
+	playGame(t, book, board, "carl", "dan", 1)
+	playGame(t, book, board, "ana", "bob", 0.5)
+
+	page, err := board.Query("classic", 0, 0, 10, "ana")
+	if err != nil {
+		t.Fatalf("Error consultando: %v", err)
+	}
+	first := page.Entries[0]
+	if first.PlayerID != "carl" || first.Points != 2 {
+		t.Fatalf("carl debería ir primero con 2 puntos: %+v", page.Entries)
+	}
+	if page.You == nil || page.You.Rank != 2 || page.You.Points != 0.5 || page.You.Rating <= first.Rating {
+		t.Errorf("ana debería ir segunda pese a tener más puntuación: %+v", page.You)
+	}
+}
+
+// TestQueryCacheFollowsResults verifica que la clasificación reutilizada se rehaga tras
+// cada partida y que las consultas concurrentes con partidas no interfieran
+func TestQueryCacheFollowsResults(t *testing.T) {
+	book, _ := rating.NewBook(nil, nil)
+	schedule, _ := ParseSchedule("monthly")
+	board, _ := New(nil, book, schedule, time.Now())
+
+	playGame(t, book, board, "ana", "bea", 1)
+	first, _ := board.Query("classic", 0, 0, 10, "")
+	again, _ := board.Query("classic", 0, 0, 10, "")
+	if first.Total != 2 || again.Entries[0].PlayerID != "ana" {
+		t.Fatalf("Clasificación incorrecta: %+v", again.Entries)
+	}
+
+	playGame(t, book, board, "bea", "ana", 1)
+	playGame(t, book, board, "bea", "carl", 1)
+	page, _ := board.Query("classic", 0, 0, 10, "")
+	if page.Total != 3 || page.Entries[0].PlayerID != "bea" || page.Entries[0].Points != 2 {
+		t.Errorf("La clasificación debería reflejar las partidas nuevas: %+v", page.Entries)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			board.Query("classic", 0, 0, 10, "ana")
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		board.RecordResult("classic", "dan", "erik", 0.5)
+	}
+	<-done
+	if page, _ := board.Query("classic", 0, 0, 10, ""); page.Total != 5 {
+		t.Errorf("Se esperaban 5 jugadores, obtenidos %d", page.Total)
+	}
+}
@@ -0,0 +1,59 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendarios de temporada predefinidos
+const (
+	ScheduleMonthly = "monthly" // Del día 1 de cada mes (UTC)
+	ScheduleWeekly  = "weekly"  // De lunes a lunes (UTC)
+)
+
+// Schedule decide cuándo termina una temporada
+type Schedule struct {
+	kind  string
+	every time.Duration // Solo para temporadas de duración fija
+}
+
+// ParseSchedule interpreta un calendario: "monthly", "weekly" o una duración fija como "72h"
+func ParseSchedule(s string) (Schedule, error) {
+	switch s {
+	case ScheduleMonthly, ScheduleWeekly:
+		return Schedule{kind: s}, nil
+	}
+
+	every, err := time.ParseDuration(s)
+	if err != nil || every < time.Minute {
+		return Schedule{}, fmt.Errorf("calendario de temporada '%s' inválido (monthly, weekly o una duración de al menos 1m)", s)
+	}
+	return Schedule{every: every}, nil
+}
+
+// Next devuelve el final de una temporada que empieza en start
+func (s Schedule) Next(start time.Time) time.Time {
+	start = start.UTC()
+
+	switch s.kind {
+	case ScheduleMonthly:
+		return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	case ScheduleWeekly:
+		midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		days := (8 - int(midnight.Weekday())) % 7
+		if days == 0 {
+			days = 7
+		}
+		return midnight.AddDate(0, 0, days)
+	default:
+		return start.Add(s.every)
+	}
+}
+
+// String devuelve el calendario en el mismo formato que acepta ParseSchedule
+func (s Schedule) String() string {
+	if s.kind != "" {
+		return s.kind
+	}
+	return s.every.String()
+}
@@ -0,0 +1,154 @@
+// Package lobby mantiene el vestíbulo en vivo: las salas públicas y el número de jugadores
+// conectados, y avisa de cada cambio a los clientes suscritos
+package lobby
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Lobby guarda el último resumen de cada sala pública y los clientes suscritos. Las salas
+// publican sus cambios desde su propio bucle; los avisos se envían sin bloquear, así que
+// un cliente lento pierde avisos en lugar de frenar a las salas. Es seguro para uso concurrente
+type Lobby struct {
+	mu          sync.Mutex
+	subscribers map[interfaces.Client]bool
+	rooms       map[string]models.RoomInfo
+	online      int
+}
+
+// New crea un vestíbulo vacío
+func New() *Lobby {
+	return &Lobby{
+		subscribers: make(map[interfaces.Client]bool),
+		rooms:       make(map[string]models.RoomInfo),
+	}
+}
+
+// Subscribe suscribe al cliente y le envía el estado actual (mensaje LOBBY). Los avisos
+// posteriores parten de ese estado
+func (l *Lobby) Subscribe(client interfaces.Client) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.subscribers[client] = true
+
+	snapshot := models.LobbySnapshotResponse{
+		Type:   "LOBBY",
+		Rooms:  l.roomList(),
+		Online: l.online,
+	}
+	msgBytes, _ := json.Marshal(snapshot)
+	send(client, msgBytes)
+}
+
+// Unsubscribe da de baja al cliente. Al volver, ya no recibirá más avisos.
+// Devuelve true si estaba suscrito
+func (l *Lobby) Unsubscribe(client interfaces.Client) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.subscribers[client] {
+		return false
+	}
+	delete(l.subscribers, client)
+	return true
+}
+
+// Rooms devuelve el último resumen de cada sala pública, ordenadas por ID
+func (l *Lobby) Rooms() []models.RoomInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.roomList()
+}
+
+// Subscribers devuelve el número de clientes suscritos
+func (l *Lobby) Subscribers() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.subscribers)
+}
+
+// RoomChanged publica el resumen de una sala pública: ROOM_ADDED la primera vez y
+// ROOM_UPDATED después. Los resúmenes sin cambios no se publican
+func (l *Lobby) RoomChanged(info models.RoomInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous, known := l.rooms[info.RoomID]
+	if known && reflect.DeepEqual(previous, info) {
+		return
+	}
+	l.rooms[info.RoomID] = info
+
+	msgType := "ROOM_UPDATED"
+	if !known {
+		msgType = "ROOM_ADDED"
+	}
+	msgBytes, _ := json.Marshal(models.LobbyRoomResponse{Type: msgType, Room: info})
+	l.publish(msgBytes)
+}
+
+// RoomRemoved publica que una sala ya no está disponible (ROOM_REMOVED)
+func (l *Lobby) RoomRemoved(roomID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, known := l.rooms[roomID]; !known {
+		return
+	}
+	delete(l.rooms, roomID)
+
+	msgBytes, _ := json.Marshal(models.LobbyRoomRemovedResponse{Type: "ROOM_REMOVED", RoomID: roomID})
+	l.publish(msgBytes)
+}
+
+// SetOnline publica el número de jugadores conectados si ha cambiado (ONLINE_COUNT)
+func (l *Lobby) SetOnline(online int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if online == l.online {
+		return
+	}
+	l.online = online
+
+	msgBytes, _ := json.Marshal(models.OnlineCountResponse{Type: "ONLINE_COUNT", Online: online})
+	l.publish(msgBytes)
+}
+
+// roomList devuelve las salas ordenadas por ID. Se llama con l.mu tomado
+func (l *Lobby) roomList() []models.RoomInfo {
+	rooms := make([]models.RoomInfo, 0, len(l.rooms))
+	for _, info := range l.rooms {
+		rooms = append(rooms, info)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].RoomID < rooms[j].RoomID
+	})
+	return rooms
+}
+
+// publish envía un aviso a todos los suscritos. Se llama con l.mu tomado
+func (l *Lobby) publish(msgBytes []byte) {
+	for client := range l.subscribers {
+		send(client, msgBytes)
+	}
+}
+
+// send entrega un mensaje sin bloquear
+func send(client interfaces.Client, msgBytes []byte) {
+	select {
+	case client.GetSendChannel() <- msgBytes:
+	default:
+		logger.Warn("No se pudo enviar aviso del vestíbulo, canal lleno", logger.Fields{
+			"clientID": client.GetID(),
+		})
+	}
+}
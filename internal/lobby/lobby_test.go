@@ -0,0 +1,122 @@
+package lobby
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+type fakeClient struct {
+	id   string
+	send chan []byte
+}
+
+func newFakeClient(id string, buffer int) *fakeClient {
+	return &fakeClient{id: id, send: make(chan []byte, buffer)}
+}
+
+func (f *fakeClient) GetID() string                  { return f.id }
+func (f *fakeClient) GetSendChannel() chan []byte    { return f.send }
+func (f *fakeClient) GetConnection() *websocket.Conn { return nil }
+func (f *fakeClient) SetRoom(interface{})            {}
+func (f *fakeClient) GetRoom() interface{}           { return nil }
+func (f *fakeClient) Close()                         {}
+
+// next devuelve el siguiente mensaje pendiente del cliente, o nil si no hay ninguno
+func (f *fakeClient) next(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case msgBytes := <-f.send:
+		var msg map[string]interface{}
+		if err := json.Unmarshal(msgBytes, &msg); err != nil {
+			t.Fatalf("Mensaje inválido: %v", err)
+		}
+		return msg
+	default:
+		return nil
+	}
+}
+
+func TestMain(m *testing.M) {
+	logger.Initialize()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// TestLobbyEvents verifica la instantánea inicial y los avisos de altas, cambios y bajas
+func TestLobbyEvents(t *testing.T) {
+	l := New()
+	l.SetOnline(3)
+	l.RoomChanged(models.RoomInfo{RoomID: "b", State: "waiting"})
+
+	sub := newFakeClient("s1", 16)
+	l.Subscribe(sub)
+
+	snapshot := sub.next(t)
+	if snapshot["type"] != "LOBBY" || snapshot["online"] != float64(3) || len(snapshot["rooms"].([]interface{})) != 1 {
+		t.Fatalf("Instantánea incorrecta: %v", snapshot)
+	}
+
+	l.RoomChanged(models.RoomInfo{RoomID: "a", State: "waiting"})
+	if msg := sub.next(t); msg["type"] != "ROOM_ADDED" || msg["room"].(map[string]interface{})["roomId"] != "a" {
+		t.Errorf("Se esperaba ROOM_ADDED, obtenido %v", msg)
+	}
+
+	// Un resumen idéntico no se vuelve a publicar
+	l.RoomChanged(models.RoomInfo{RoomID: "a", State: "waiting"})
+	if msg := sub.next(t); msg != nil {
+		t.Errorf("No se esperaba aviso, obtenido %v", msg)
+	}
+
+	l.RoomChanged(models.RoomInfo{RoomID: "a", State: "playing", IsFull: true})
+	if msg := sub.next(t); msg["type"] != "ROOM_UPDATED" {
+		t.Errorf("Se esperaba ROOM_UPDATED, obtenido %v", msg)
+	}
+
+	l.RoomRemoved("a")
+	if msg := sub.next(t); msg["type"] != "ROOM_REMOVED" || msg["roomId"] != "a" {
+		t.Errorf("Se esperaba ROOM_REMOVED, obtenido %v", msg)
+	}
+	l.RoomRemoved("a")
+	if msg := sub.next(t); msg != nil {
+		t.Errorf("Una sala desconocida no debería avisarse, obtenido %v", msg)
+	}
+
+	l.SetOnline(3)
+	l.SetOnline(4)
+	if msg := sub.next(t); msg["type"] != "ONLINE_COUNT" || msg["online"] != float64(4) {
+		t.Errorf("Se esperaba ONLINE_COUNT 4, obtenido %v", msg)
+	}
+	if msg := sub.next(t); msg != nil {
+		t.Errorf("Solo debería avisarse el cambio de conectados, obtenido %v", msg)
+	}
+}
+
+// TestLobbyUnsubscribe verifica que un cliente dado de baja no reciba más avisos
+// y que un cliente lento no bloquee la publicación
+func TestLobbyUnsubscribe(t *testing.T) {
+	l := New()
+	gone, slow := newFakeClient("gone", 16), newFakeClient("slow", 1)
+	l.Subscribe(gone)
+	l.Subscribe(slow)
+	gone.next(t)
+
+	if !l.Unsubscribe(gone) || l.Unsubscribe(gone) {
+		t.Errorf("Unsubscribe debería dar de baja una sola vez")
+	}
+
+	// El canal de slow ya está lleno con la instantánea: el aviso se descarta
+	l.RoomChanged(models.RoomInfo{RoomID: "a"})
+	if msg := gone.next(t); msg != nil {
+		t.Errorf("Un cliente dado de baja no debería recibir avisos, obtenido %v", msg)
+	}
+	if l.Subscribers() != 1 {
+		t.Errorf("Se esperaba 1 suscrito, hay %d", l.Subscribers())
+	}
+}
@@ -0,0 +1,183 @@
+// Package matchmaking empareja a los jugadores que buscan partida rápida con la misma
+// variante y control de tiempo y un nivel de juego parecido
+package matchmaking
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// ErrAlreadyQueued indica que el jugador ya está buscando partida
+var ErrAlreadyQueued = errors.New("el jugador ya está en la cola")
+
+// Policy indica cuánta diferencia de puntuación se acepta y cómo crece con la espera
+type Policy struct {
+	InitialTolerance  float64 // Diferencia aceptada al entrar en la cola
+	WideningPerSecond float64 // Diferencia añadida por cada segundo de espera
+	MaxTolerance      float64 // Diferencia máxima (0 = sin límite)
+}
+
+// DefaultPolicy devuelve la política de emparejamiento por defecto
+func DefaultPolicy() Policy {
+	return Policy{
+		InitialTolerance:  50,
+		WideningPerSecond: 10,
+		MaxTolerance:      400,
+	}
+}
+
+// Tolerance devuelve la diferencia de puntuación aceptada tras esperar waited
+func (p Policy) Tolerance(waited time.Duration) float64 {
+	if waited < 0 {
+		waited = 0
+	}
+	tolerance := p.InitialTolerance + p.WideningPerSecond*waited.Seconds()
+	if p.MaxTolerance > 0 && tolerance > p.MaxTolerance {
+		return p.MaxTolerance
+	}
+	return tolerance
+}
+
+// Ticket es la entrada de un jugador en la cola
+type Ticket struct {
+	Client   interfaces.Client
+	Settings models.RoomSettings // Configuración ya validada de la sala que se creará
+	Rating   float64
+	JoinedAt time.Time
+}
+
+// PlayerID devuelve el ID del jugador del ticket
+func (t *Ticket) PlayerID() string {
+	return t.Client.GetID()
+}
+
+// Pair son dos jugadores emparejados. First es el que más tiempo llevaba esperando
+type Pair struct {
+	First  *Ticket
+	Second *Ticket
+}
+
+// Pool identifica el grupo de jugadores compatibles: misma variante, tablero y control de tiempo
+func Pool(settings models.RoomSettings) string {
+	return fmt.Sprintf("%s/%d/%d+%d", settings.Variant, settings.BoardSize,
+		settings.TimeControl.InitialSeconds, settings.TimeControl.IncrementSeconds)
+}
+
+// Queue es la cola de partida rápida. No es segura para uso concurrente: el Hub la usa
+// solo desde su bucle
+type Queue struct {
+	policy  Policy
+	tickets []*Ticket // En orden de llegada
+}
+
+// NewQueue crea una cola vacía con la política indicada
+func NewQueue(policy Policy) *Queue {
+	return &Queue{policy: policy}
+}
+
+// Policy devuelve la política de la cola
+func (q *Queue) Policy() Policy {
+	return q.policy
+}
+
+// Add añade un jugador a la cola. La cola se mantiene ordenada por antigüedad, de modo
+// que un ticket devuelto a la cola conserva su turno
+func (q *Queue) Add(t *Ticket) error {
+	if q.Contains(t.PlayerID()) {
+		return ErrAlreadyQueued
+	}
+
+	i := len(q.tickets)
+	for i > 0 && q.tickets[i-1].JoinedAt.After(t.JoinedAt) {
+		i--
+	}
+	q.tickets = append(q.tickets, nil)
+	copy(q.tickets[i+1:], q.tickets[i:])
+	q.tickets[i] = t
+	return nil
+}
+
+// Remove saca a un jugador de la cola. Devuelve su ticket, o nil si no estaba
+func (q *Queue) Remove(playerID string) *Ticket {
+	for i, t := range q.tickets {
+		if t.PlayerID() == playerID {
+			q.tickets = append(q.tickets[:i], q.tickets[i+1:]...)
+			return t
+		}
+	}
+	return nil
+}
+
+// Get devuelve el ticket de un jugador, o nil si no está en la cola
+func (q *Queue) Get(playerID string) *Ticket {
+	for _, t := range q.tickets {
+		if t.PlayerID() == playerID {
+			return t
+		}
+	}
+	return nil
+}
+
+// Contains indica si el jugador está en la cola
+func (q *Queue) Contains(playerID string) bool {
+	return q.Get(playerID) != nil
+}
+
+// Len devuelve el número de jugadores en la cola
+func (q *Queue) Len() int {
+	return len(q.tickets)
+}
+
+// Match forma todas las parejas posibles y las saca de la cola. Dos jugadores son
+// compatibles si están en el mismo grupo y la diferencia de puntuación entra en la
+// tolerancia de ambos. Los que más esperan eligen primero, y cada uno se queda con
+// el rival de puntuación más cercana
+func (q *Queue) Match(now time.Time) []Pair {
+	var pairs []Pair
+	matched := make(map[*Ticket]bool)
+
+	for i, first := range q.tickets {
+		if matched[first] {
+			continue
+		}
+		pool := Pool(first.Settings)
+		firstTolerance := q.policy.Tolerance(now.Sub(first.JoinedAt))
+
+		var best *Ticket
+		bestDiff := math.Inf(1)
+		for _, candidate := range q.tickets[i+1:] {
+			if matched[candidate] || Pool(candidate.Settings) != pool {
+				continue
+			}
+			diff := math.Abs(first.Rating - candidate.Rating)
+			if diff > firstTolerance || diff > q.policy.Tolerance(now.Sub(candidate.JoinedAt)) {
+				continue
+			}
+			if diff < bestDiff {
+				best, bestDiff = candidate, diff
+			}
+		}
+
+		if best != nil {
+			matched[first], matched[best] = true, true
+			pairs = append(pairs, Pair{First: first, Second: best})
+		}
+	}
+
+	if len(pairs) > 0 {
+		remaining := q.tickets[:0]
+		for _, t := range q.tickets {
+			if !matched[t] {
+				remaining = append(remaining, t)
+			}
+		}
+		q.tickets = remaining
+	}
+
+	return pairs
+}
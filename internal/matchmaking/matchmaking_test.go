@@ -0,0 +1,166 @@
+package matchmaking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// stubClient es un cliente mínimo que solo aporta su ID
+type stubClient struct {
+	id string
+}
+
+func (c *stubClient) GetID() string                  { return c.id }
+func (c *stubClient) GetSendChannel() chan []byte    { return nil }
+func (c *stubClient) GetConnection() *websocket.Conn { return nil }
+func (c *stubClient) SetRoom(interface{})            {}
+func (c *stubClient) GetRoom() interface{}           { return nil }
+func (c *stubClient) Close()                         {}
+
+func classic(initial int) models.RoomSettings {
+	return models.RoomSettings{
+		Variant:     "classic",
+		BoardSize:   3,
+		TimeControl: models.TimeControl{InitialSeconds: initial},
+	}
+}
+
+func ticket(id string, settings models.RoomSettings, rating float64, joined time.Time) *Ticket {
+	return &Ticket{Client: &stubClient{id: id}, Settings: settings, Rating: rating, JoinedAt: joined}
+}
+
+// TestPolicyTolerance verifica que la tolerancia crezca con la espera hasta el máximo
+func TestPolicyTolerance(t *testing.T) {
+	p := Policy{InitialTolerance: 50, WideningPerSecond: 10, MaxTolerance: 200}
+
+	cases := []struct {
+		waited time.Duration
+		want   float64
+	}{
+		{0, 50},
+		{-time.Second, 50},
+		{5 * time.Second, 100},
+		{time.Minute, 200},
+	}
+	for _, c := range cases {
+		if got := p.Tolerance(c.waited); got != c.want {
+			t.Errorf("Tolerance(%v) = %v, se esperaba %v", c.waited, got, c.want)
+		}
+	}
+
+	p.MaxTolerance = 0
+	if got := p.Tolerance(time.Minute); got != 650 {
+		t.Errorf("Sin máximo, Tolerance(1m) = %v, se esperaba 650", got)
+	}
+}
+
+// TestQueueAddRemove verifica que un jugador no pueda estar dos veces en la cola
+func TestQueueAddRemove(t *testing.T) {
+	q := NewQueue(DefaultPolicy())
+	now := time.Now()
+
+	if err := q.Add(ticket("p1", classic(0), 1500, now)); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if err := q.Add(ticket("p1", classic(60), 1500, now)); err != ErrAlreadyQueued {
+		t.Errorf("Se esperaba ErrAlreadyQueued, obtenido %v", err)
+	}
+	if q.Remove("p1") == nil || q.Remove("p1") != nil || q.Len() != 0 {
+		t.Errorf("Remove debería sacar al jugador una sola vez")
+	}
+}
+
+// TestQueueMatchFiltersPools verifica que solo se emparejen jugadores con la misma configuración
+func TestQueueMatchFiltersPools(t *testing.T) {
+	q := NewQueue(DefaultPolicy())
+	now := time.Now()
+
+	gomoku := models.RoomSettings{Variant: "gomoku", BoardSize: 15}
+	q.Add(ticket("blitz1", classic(60), 1500, now))
+	q.Add(ticket("untimed", classic(0), 1500, now))
+	q.Add(ticket("gomoku", gomoku, 1500, now))
+	q.Add(ticket("blitz2", classic(60), 1500, now))
+
+	pairs := q.Match(now)
+	if len(pairs) != 1 {
+		t.Fatalf("Se esperaba una pareja, obtenidas %d", len(pairs))
+	}
+	if pairs[0].First.PlayerID() != "blitz1" || pairs[0].Second.PlayerID() != "blitz2" {
+		t.Errorf("Pareja incorrecta: %s - %s", pairs[0].First.PlayerID(), pairs[0].Second.PlayerID())
+	}
+	if q.Len() != 2 || !q.Contains("untimed") || !q.Contains("gomoku") {
+		t.Errorf("Los jugadores sin pareja deberían seguir en la cola")
+	}
+}
+
+// TestQueueMatchWidensTolerance verifica que la diferencia aceptada crezca con la espera
+func TestQueueMatchWidensTolerance(t *testing.T) {
+	q := NewQueue(Policy{InitialTolerance: 50, WideningPerSecond: 10, MaxTolerance: 400})
+	start := time.Now()
+
+	q.Add(ticket("low", classic(0), 1400, start))
+	q.Add(ticket("high", classic(0), 1600, start))
+
+	if pairs := q.Match(start); len(pairs) != 0 {
+		t.Fatalf("200 puntos de diferencia no deberían emparejarse al entrar")
+	}
+	if pairs := q.Match(start.Add(10 * time.Second)); len(pairs) != 0 {
+		t.Fatalf("A los 10s la tolerancia (150) aún no alcanza")
+	}
+	if pairs := q.Match(start.Add(15 * time.Second)); len(pairs) != 1 {
+		t.Fatalf("A los 15s la tolerancia (200) debería alcanzar")
+	}
+}
+
+// TestQueueMatchRequiresBothTolerances verifica que el recién llegado también deba aceptar al rival
+func TestQueueMatchRequiresBothTolerances(t *testing.T) {
+	q := NewQueue(Policy{InitialTolerance: 50, WideningPerSecond: 10})
+	start := time.Now()
+
+	q.Add(ticket("veteran", classic(0), 1400, start))
+	q.Add(ticket("newcomer", classic(0), 1600, start.Add(time.Minute)))
+
+	if pairs := q.Match(start.Add(time.Minute)); len(pairs) != 0 {
+		t.Fatalf("El recién llegado no acepta todavía 200 puntos de diferencia")
+	}
+	if pairs := q.Match(start.Add(time.Minute + 15*time.Second)); len(pairs) != 1 {
+		t.Fatalf("Tras 15s de espera ambos deberían aceptar")
+	}
+}
+
+// TestQueueMatchPrefersClosestRating verifica que se elija al rival de puntuación más cercana
+func TestQueueMatchPrefersClosestRating(t *testing.T) {
+	q := NewQueue(Policy{InitialTolerance: 300})
+	now := time.Now()
+
+	q.Add(ticket("a", classic(0), 1500, now))
+	q.Add(ticket("far", classic(0), 1750, now))
+	q.Add(ticket("near", classic(0), 1520, now))
+
+	pairs := q.Match(now)
+	if len(pairs) != 1 || pairs[0].Second.PlayerID() != "near" {
+		t.Fatalf("Se esperaba emparejar con 'near', obtenido %+v", pairs)
+	}
+	if !q.Contains("far") || q.Len() != 1 {
+		t.Errorf("'far' debería seguir esperando")
+	}
+}
+
+// TestQueueAddKeepsSeniority verifica que un ticket devuelto a la cola conserve su turno
+func TestQueueAddKeepsSeniority(t *testing.T) {
+	q := NewQueue(Policy{InitialTolerance: 1000})
+	start := time.Now()
+
+	q.Add(ticket("new1", classic(0), 1500, start.Add(time.Minute)))
+	q.Add(ticket("old", classic(0), 1500, start))
+	q.Add(ticket("new2", classic(0), 1500, start.Add(time.Minute)))
+
+	pairs := q.Match(start.Add(time.Minute))
+	if len(pairs) != 1 || pairs[0].First.PlayerID() != "old" || pairs[0].Second.PlayerID() != "new1" {
+		t.Fatalf("El ticket más antiguo debería elegir primero, obtenido %+v", pairs)
+	}
+}
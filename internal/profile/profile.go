@@ -0,0 +1,247 @@
+// Package profile guarda los perfiles públicos de los jugadores: nombre visible, avatar y
+// bandera del país. También recuerda cuándo se vio por última vez a cada jugador, para
+// olvidar a quien ya no puede volver con su identidad
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Longitud del nombre visible, en caracteres
+const (
+	MinNameLength = 3
+	MaxNameLength = 20
+)
+
+// documentName es el nombre del documento de perfiles en el almacén
+const documentName = "profiles"
+
+// ErrNameTaken indica que otro jugador ya usa ese nombre visible
+var ErrNameTaken = errors.New("el nombre ya está en uso")
+
+var (
+	avatarPattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// Validate normaliza un perfil y comprueba que sea válido. El nombre pierde los espacios
+// sobrantes; el país pasa a mayúsculas. El avatar y el país son opcionales
+func Validate(p models.PlayerProfile) (models.PlayerProfile, error) {
+	p.DisplayName = strings.Join(strings.Fields(p.DisplayName), " ")
+	length := utf8.RuneCountInString(p.DisplayName)
+	if length < MinNameLength || length > MaxNameLength {
+		return p, fmt.Errorf("el nombre debe tener entre %d y %d caracteres", MinNameLength, MaxNameLength)
+	}
+
+	hasLetter := false
+	for _, r := range p.DisplayName {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r), r == ' ', r == '_', r == '-', r == '.':
+		default:
+			return p, fmt.Errorf("el nombre solo admite letras, números, espacios, '_', '-' y '.'")
+		}
+	}
+	if !hasLetter {
+		return p, errors.New("el nombre debe contener al menos una letra")
+	}
+
+	if p.Avatar != "" && !avatarPattern.MatchString(p.Avatar) {
+		return p, errors.New("el avatar debe ser un identificador de hasta 32 caracteres (a-z, 0-9, '_' o '-')")
+	}
+
+	p.Country = strings.ToUpper(p.Country)
+	if p.Country != "" && !countryPattern.MatchString(p.Country) {
+		return p, errors.New("el país debe ser un código ISO 3166-1 de dos letras")
+	}
+	return p, nil
+}
+
+// nameKey es la forma del nombre con la que se comprueba que no se repita: sin distinguir
+// mayúsculas ni separadores
+func nameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// record es lo que se guarda de cada jugador. Un jugador sin perfil tiene el nombre vacío
+type record struct {
+	models.PlayerProfile
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Book guarda los perfiles de los jugadores y cuándo se les vio por última vez. Se cargan
+// del almacén al crearlo, así que las consultas nunca tocan el disco. Es seguro para uso
+// concurrente
+type Book struct {
+	store *store.FileStore // nil: solo memoria
+
+	mu       sync.RWMutex
+	profiles map[string]record // jugador -> perfil y última vez visto
+	names    map[string]string // clave del nombre -> jugador
+
+	saveMu sync.Mutex // Serializa las escrituras para que la última gane
+}
+
+// NewBook crea el registro de perfiles y recupera los guardados. s puede ser nil para
+// guardar solo en memoria. Los perfiles guardados sin fecha cuentan como vistos ahora
+func NewBook(s *store.FileStore) (*Book, error) {
+	b := &Book{
+		store:    s,
+		profiles: make(map[string]record),
+		names:    make(map[string]string),
+	}
+	if s != nil {
+		if _, err := s.Load(documentName, &b.profiles); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	for playerID, rec := range b.profiles {
+		if rec.LastSeen.IsZero() {
+			rec.LastSeen = now
+			b.profiles[playerID] = rec
+		}
+		if rec.DisplayName != "" {
+			b.names[nameKey(rec.DisplayName)] = playerID
+		}
+	}
+	return b, nil
+}
+
+// Set valida y guarda en memoria el perfil de un jugador. El nombre no puede coincidir con
+// el de otro jugador salvo en mayúsculas o separadores. Devuelve el perfil normalizado
+func (b *Book) Set(playerID string, p models.PlayerProfile) (models.PlayerProfile, error) {
+	p, err := Validate(p)
+	if err != nil {
+		return p, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := nameKey(p.DisplayName)
+	if owner, taken := b.names[key]; taken && owner != playerID {
+		return p, ErrNameTaken
+	}
+	rec := b.profiles[playerID]
+	if rec.DisplayName != "" {
+		delete(b.names, nameKey(rec.DisplayName))
+	}
+	rec.PlayerProfile = p
+	rec.LastSeen = time.Now()
+	b.profiles[playerID] = rec
+	b.names[key] = playerID
+	return p, nil
+}
+
+// Get devuelve el perfil de un jugador, si tiene
+func (b *Book) Get(playerID string) (models.PlayerProfile, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	rec, ok := b.profiles[playerID]
+	return rec.PlayerProfile, ok && rec.DisplayName != ""
+}
+
+// Lookup devuelve los perfiles de los jugadores indicados que tienen uno. Devuelve nil si
+// ninguno lo tiene
+func (b *Book) Lookup(playerIDs []string) map[string]models.PlayerProfile {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var found map[string]models.PlayerProfile
+	for _, playerID := range playerIDs {
+		rec, ok := b.profiles[playerID]
+		if !ok || rec.DisplayName == "" {
+			continue
+		}
+		if found == nil {
+			found = make(map[string]models.PlayerProfile, len(playerIDs))
+		}
+		found[playerID] = rec.PlayerProfile
+	}
+	return found
+}
+
+// Seen anota que el jugador estaba conectado en now, tenga perfil o no
+func (b *Book) Seen(playerID string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec := b.profiles[playerID]
+	if now.After(rec.LastSeen) {
+		rec.LastSeen = now
+	}
+	b.profiles[playerID] = rec
+}
+
+// Remember anota como vistos en now a los jugadores que aún no constan. Sirve para que
+// los jugadores conocidos por otros registros empiecen a contar desde ahora
+func (b *Book) Remember(playerIDs []string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, playerID := range playerIDs {
+		if _, ok := b.profiles[playerID]; !ok {
+			b.profiles[playerID] = record{LastSeen: now}
+		}
+	}
+}
+
+// Prune olvida a los jugadores vistos por última vez antes de before, salvo a los que
+// keep indique, y libera sus nombres. Devuelve los jugadores olvidados, ordenados
+func (b *Book) Prune(before time.Time, keep func(playerID string) bool) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var removed []string
+	for playerID, rec := range b.profiles {
+		if !rec.LastSeen.Before(before) || keep(playerID) {
+			continue
+		}
+		if rec.DisplayName != "" {
+			delete(b.names, nameKey(rec.DisplayName))
+		}
+		delete(b.profiles, playerID)
+		removed = append(removed, playerID)
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// Save escribe en disco los perfiles actuales
+func (b *Book) Save() error {
+	if b.store == nil {
+		return nil
+	}
+
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	b.mu.RLock()
+	profiles := make(map[string]record, len(b.profiles))
+	for playerID, rec := range b.profiles {
+		profiles[playerID] = rec
+	}
+	b.mu.RUnlock()
+
+	return b.store.Save(documentName, profiles)
+}
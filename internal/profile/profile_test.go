@@ -0,0 +1,123 @@
+package profile
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// TestValidate verifica la normalización y los límites de los perfiles
+func TestValidate(t *testing.T) {
+	p, err := Validate(models.PlayerProfile{DisplayName: "  Ana   María ", Avatar: "cat-2", Country: "es"})
+	if err != nil {
+		t.Fatalf("Perfil válido rechazado: %v", err)
+	}
+	if p.DisplayName != "Ana María" || p.Country != "ES" {
+		t.Errorf("Perfil mal normalizado: %+v", p)
+	}
+
+	for _, invalid := range []models.PlayerProfile{
+		{DisplayName: "ab"},
+		{DisplayName: "nombre-demasiado-largo-para-mostrar"},
+		{DisplayName: "1234"},
+		{DisplayName: "<script>"},
+		{DisplayName: "Ana", Avatar: "Cat"},
+		{DisplayName: "Ana", Country: "ESP"},
+		{DisplayName: "Ana", Country: "E1"},
+	} {
+		if _, err := Validate(invalid); err == nil {
+			t.Errorf("Validate(%+v) debería fallar", invalid)
+		}
+	}
+}
+
+// TestBookNames verifica que un nombre no se repita entre jugadores aunque cambien las
+// mayúsculas o los separadores, y que al cambiar de nombre quede libre el anterior
+func TestBookNames(t *testing.T) {
+	b, _ := NewBook(nil)
+
+	if _, err := b.Set("p1", models.PlayerProfile{DisplayName: "Ana_Maria"}); err != nil {
+		t.Fatalf("Error guardando perfil: %v", err)
+	}
+	if _, err := b.Set("p2", models.PlayerProfile{DisplayName: "ana maria"}); err != ErrNameTaken {
+		t.Errorf("Se esperaba ErrNameTaken, obtenido %v", err)
+	}
+	if _, err := b.Set("p1", models.PlayerProfile{DisplayName: "ANA MARIA", Country: "AR"}); err != nil {
+		t.Errorf("Un jugador puede cambiar su propio nombre de forma: %v", err)
+	}
+	if _, err := b.Set("p1", models.PlayerProfile{DisplayName: "Anita"}); err != nil {
+		t.Fatalf("Error cambiando de nombre: %v", err)
+	}
+	if _, err := b.Set("p2", models.PlayerProfile{DisplayName: "Ana Maria"}); err != nil {
+		t.Errorf("El nombre anterior debería quedar libre: %v", err)
+	}
+
+	found := b.Lookup([]string{"p1", "p2", "p3"})
+	if len(found) != 2 || found["p1"].DisplayName != "Anita" {
+		t.Errorf("Lookup incorrecto: %+v", found)
+	}
+	if b.Lookup([]string{"p3"}) != nil {
+		t.Error("Lookup sin perfiles debería devolver nil")
+	}
+}
+
+// TestBookPrune verifica que se olvide a quien lleva tiempo sin aparecer, con o sin
+// perfil, y que su nombre quede libre
+func TestBookPrune(t *testing.T) {
+	b, _ := NewBook(nil)
+	start := time.Now()
+
+	b.Set("p1", models.PlayerProfile{DisplayName: "Ana"})
+	b.Seen("p1", start)
+	b.Seen("p2", start)
+	b.Seen("p3", start.Add(2*time.Hour))
+	b.Remember([]string{"p3", "p4"}, start.Add(time.Hour))
+	b.Set("p5", models.PlayerProfile{DisplayName: "Bea"})
+	b.Seen("p5", start)
+
+	if _, ok := b.Get("p2"); ok {
+		t.Error("Un jugador sin perfil no debería tener perfil")
+	}
+
+	connected := func(playerID string) bool { return playerID == "p5" }
+	removed := b.Prune(start.Add(90*time.Minute), connected)
+	if fmt.Sprint(removed) != "[p1 p2 p4]" {
+		t.Errorf("Jugadores olvidados incorrectos: %v", removed)
+	}
+	if _, ok := b.Get("p1"); ok {
+		t.Error("El perfil de p1 debería haberse olvidado")
+	}
+	if _, err := b.Set("p6", models.PlayerProfile{DisplayName: "ana"}); err != nil {
+		t.Errorf("El nombre de p1 debería quedar libre: %v", err)
+	}
+	if _, ok := b.Get("p5"); !ok {
+		t.Error("Un jugador conectado no se olvida")
+	}
+}
+
+// TestBookPersistence verifica que los perfiles y sus nombres se recuperen del almacén
+func TestBookPersistence(t *testing.T) {
+	s, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error abriendo almacén: %v", err)
+	}
+	b, _ := NewBook(s)
+	b.Set("p1", models.PlayerProfile{DisplayName: "Ana", Avatar: "owl", Country: "UY"})
+	if err := b.Save(); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	restored, err := NewBook(s)
+	if err != nil {
+		t.Fatalf("Error cargando: %v", err)
+	}
+	if p, ok := restored.Get("p1"); !ok || p.Avatar != "owl" || p.Country != "UY" {
+		t.Errorf("Perfil no recuperado: %+v", p)
+	}
+	if _, err := restored.Set("p2", models.PlayerProfile{DisplayName: "ana"}); err != ErrNameTaken {
+		t.Errorf("Los nombres recuperados deberían seguir ocupados: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package rating
+
+import (
+	"sync"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+// documentPrefix antecede al nombre de la variante en el nombre de su documento
+const documentPrefix = "ratings-"
+
+// Change es la puntuación de un jugador antes y después de una partida
+type Change struct {
+	Before Rating
+	After  Rating
+}
+
+// Book guarda las puntuaciones de los jugadores, separadas por variante. Las variantes se
+// cargan del almacén al crearlo, así que las consultas nunca tocan el disco. Es seguro
+// para uso concurrente
+type Book struct {
+	store *store.FileStore // nil: solo memoria
+
+	mu       sync.Mutex
+	variants map[string]map[string]Rating // variante -> jugador -> puntuación
+
+	saveMu sync.Mutex // Serializa las escrituras para que la última gane
+}
+
+// NewBook crea un registro de puntuaciones y recupera las guardadas de las variantes
+// indicadas. s puede ser nil para guardar solo en memoria. Si falla la lectura de alguna
+// variante devuelve el error, para no sobrescribir después su documento con uno vacío
+func NewBook(s *store.FileStore, variants []string) (*Book, error) {
+	b := &Book{
+		store:    s,
+		variants: make(map[string]map[string]Rating),
+	}
+	if s == nil {
+		return b, nil
+	}
+
+	for _, variant := range variants {
+		ratings := make(map[string]Rating)
+		if _, err := s.Load(documentPrefix+variant, &ratings); err != nil {
+			return nil, err
+		}
+		b.variants[variant] = ratings
+	}
+	return b, nil
+}
+
+// Get devuelve la puntuación de un jugador en una variante, o la inicial si no tiene
+func (b *Book) Get(playerID, variant string) Rating {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r, ok := b.variants[variant][playerID]; ok {
+		return r
+	}
+	return New()
+}
+
+// RecordGame actualiza las puntuaciones de los dos jugadores de una partida terminada.
+// firstScore es el resultado del primero: 1 victoria, 0.5 tablas, 0 derrota. Ambos se
+// calculan con las puntuaciones previas a la partida
+func (b *Book) RecordGame(variant, first, second string, firstScore float64, now time.Time) (Change, Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ratings := b.variants[variant]
+	if ratings == nil {
+		ratings = make(map[string]Rating)
+		b.variants[variant] = ratings
+	}
+
+	before := func(playerID string) Rating {
+		if r, ok := ratings[playerID]; ok {
+			return r
+		}
+		return New()
+	}
+	firstBefore, secondBefore := before(first), before(second)
+
+	firstAfter := Update(firstBefore, []Result{{Opponent: secondBefore, Score: firstScore}})
+	secondAfter := Update(secondBefore, []Result{{Opponent: firstBefore, Score: 1 - firstScore}})
+	firstAfter.UpdatedAt = now.UnixMilli()
+	secondAfter.UpdatedAt = now.UnixMilli()
+
+	ratings[first] = firstAfter
+	ratings[second] = secondAfter
+
+	return Change{Before: firstBefore, After: firstAfter}, Change{Before: secondBefore, After: secondAfter}
+}
+
+// Standings devuelve una copia de todas las puntuaciones de una variante
+func (b *Book) Standings(variant string) map[string]Rating {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ratings := b.variants[variant]
+	standings := make(map[string]Rating, len(ratings))
+	for playerID, r := range ratings {
+		standings[playerID] = r
+	}
+	return standings
+}
+
+// Save escribe en disco las puntuaciones actuales de una variante
+func (b *Book) Save(variant string) error {
+	if b.store == nil {
+		return nil
+	}
+
+	b.saveMu.Lock()
+	defer b.saveMu.Unlock()
+
+	return b.store.Save(documentPrefix+variant, b.Standings(variant))
+}
@@ -0,0 +1,133 @@
+// Package rating calcula y guarda las puntuaciones de los jugadores con el sistema Glicko-2
+package rating
+
+import (
+	"math"
+)
+
+// Valores iniciales de un jugador sin partidas puntuadas
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+)
+
+const (
+	// tau limita cuánto puede cambiar la volatilidad en cada partida
+	tau = 0.5
+
+	// glickoScale convierte entre la escala Glicko y la escala interna de Glicko-2
+	glickoScale = 173.7178
+
+	// convergence es la precisión con la que se calcula la nueva volatilidad
+	convergence = 0.000001
+)
+
+// Rating es la puntuación de un jugador en una variante
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`  // Incertidumbre de la puntuación (RD)
+	Volatility float64 `json:"volatility"` // Consistencia de los resultados
+	Games      int     `json:"games"`
+	UpdatedAt  int64   `json:"updatedAt,omitempty"` // Unix milliseconds
+}
+
+// New devuelve la puntuación de un jugador nuevo
+func New() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Result es el resultado de una partida contra un rival: 1 victoria, 0.5 tablas, 0 derrota
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Update calcula la nueva puntuación de un jugador tras un periodo con los resultados
+// indicados. Sin resultados solo crece la incertidumbre
+func Update(player Rating, results []Result) Rating {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.Deviation / glickoScale
+	sigma := player.Volatility
+
+	if len(results) == 0 {
+		player.Deviation = math.Min(math.Sqrt(phi*phi+sigma*sigma)*glickoScale, DefaultDeviation)
+		return player
+	}
+
+	// Varianza estimada (v) y mejora estimada (delta) a partir de los resultados
+	var invV, sum float64
+	for _, res := range results {
+		muJ := (res.Opponent.Rating - DefaultRating) / glickoScale
+		g := gFactor(res.Opponent.Deviation / glickoScale)
+		e := expectedScore(mu, muJ, g)
+		invV += g * g * e * (1 - e)
+		sum += g * (res.Score - e)
+	}
+	v := 1 / invV
+	delta := v * sum
+
+	newSigma := newVolatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*sum
+
+	return Rating{
+		Rating:     newMu*glickoScale + DefaultRating,
+		Deviation:  math.Min(newPhi*glickoScale, DefaultDeviation),
+		Volatility: newSigma,
+		Games:      player.Games + len(results),
+		UpdatedAt:  player.UpdatedAt,
+	}
+}
+
+// gFactor reduce el peso de un resultado según la incertidumbre del rival
+func gFactor(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// expectedScore es la puntuación esperada contra un rival
+func expectedScore(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+// newVolatility resuelve la nueva volatilidad con el método de Illinois
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,116 @@
+package rating
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/store"
+)
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+// TestUpdateGlickmanExample reproduce el ejemplo del artículo original de Glicko-2
+func TestUpdateGlickmanExample(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+		{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+		{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+	}
+
+	got := Update(player, results)
+	if !near(got.Rating, 1464.06, 0.05) || !near(got.Deviation, 151.52, 0.05) || !near(got.Volatility, 0.05999, 0.00001) {
+		t.Errorf("Resultado distinto del ejemplo: %+v", got)
+	}
+	if got.Games != 3 {
+		t.Errorf("Se esperaban 3 partidas, obtenidas %d", got.Games)
+	}
+}
+
+// TestUpdateWithoutGamesGrowsDeviation verifica que la incertidumbre crezca sin partidas, hasta el máximo
+func TestUpdateWithoutGamesGrowsDeviation(t *testing.T) {
+	player := Rating{Rating: 1600, Deviation: 50, Volatility: 0.06}
+	got := Update(player, nil)
+	if got.Rating != 1600 || got.Deviation <= 50 {
+		t.Errorf("Sin partidas solo debería crecer la desviación: %+v", got)
+	}
+
+	if got := Update(New(), nil); got.Deviation != DefaultDeviation {
+		t.Errorf("La desviación no debería superar %v, obtenida %v", DefaultDeviation, got.Deviation)
+	}
+}
+
+// TestBookRecordGame verifica que una partida mueva las puntuaciones de forma simétrica
+// y que se separen por variante
+func TestBookRecordGame(t *testing.T) {
+	b, _ := NewBook(nil, nil)
+	now := time.Now()
+
+	winner, loser := b.RecordGame("classic", "p1", "p2", 1, now)
+	if winner.Before.Rating != DefaultRating || winner.After.Rating <= DefaultRating || loser.After.Rating >= DefaultRating {
+		t.Errorf("Cambio incorrecto: ganador %+v, perdedor %+v", winner, loser)
+	}
+	if !near(winner.After.Rating-DefaultRating, DefaultRating-loser.After.Rating, 0.001) {
+		t.Errorf("Entre jugadores iguales el cambio debería ser simétrico")
+	}
+	if winner.After.Games != 1 || winner.After.UpdatedAt != now.UnixMilli() {
+		t.Errorf("Datos de la partida no anotados: %+v", winner.After)
+	}
+
+	if r := b.Get("p1", "classic"); r != winner.After {
+		t.Errorf("Get devolvió %+v, se esperaba %+v", r, winner.After)
+	}
+	if r := b.Get("p1", "gomoku"); r != New() {
+		t.Errorf("Las variantes deberían tener puntuaciones separadas, obtenido %+v", r)
+	}
+
+	draw1, draw2 := b.RecordGame("classic", "p1", "p2", 0.5, now)
+	if draw1.After.Rating >= draw1.Before.Rating || draw2.After.Rating <= draw2.Before.Rating {
+		t.Errorf("Unas tablas deberían acercar las puntuaciones: %+v, %+v", draw1, draw2)
+	}
+}
+
+// TestBookPersistence verifica que las puntuaciones sobrevivan a un reinicio
+func TestBookPersistence(t *testing.T) {
+	s, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creando almacén: %v", err)
+	}
+
+	variants := []string{"classic", "gomoku"}
+	b, _ := NewBook(s, variants)
+	change, _ := b.RecordGame("classic", "p1", "p2", 1, time.Now())
+	if err := b.Save("classic"); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	reopened, err := NewBook(s, variants)
+	if err != nil {
+		t.Fatalf("Error cargando: %v", err)
+	}
+	if r := reopened.Get("p1", "classic"); r != change.After {
+		t.Errorf("Puntuación recuperada %+v, se esperaba %+v", r, change.After)
+	}
+}
+
+// TestBookLoadError verifica que un documento ilegible impida crear el registro, en vez de
+// empezar con puntuaciones vacías que lo sobrescribirían
+func TestBookLoadError(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Error creando almacén: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, documentPrefix+"classic.json"), []byte("{roto"), 0o644); err != nil {
+		t.Fatalf("Error escribiendo documento: %v", err)
+	}
+
+	if _, err := NewBook(s, []string{"classic"}); err == nil {
+		t.Error("Se esperaba un error al cargar un documento ilegible")
+	}
+}
@@ -0,0 +1,71 @@
+package room
+
+import (
+	"encoding/json"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Modos de envío de GAME_UPDATE que puede elegir un cliente
+const (
+	UpdateModeFull  = "full"  // Tablero completo en cada actualización (por defecto)
+	UpdateModeDelta = "delta" // Solo las casillas cambiadas, con checkpoints periódicos
+)
+
+// DeltaCheckpointEvery es cada cuántas jugadas una actualización delta incluye el tablero completo
+const DeltaCheckpointEvery = 10
+
+// wantsDelta indica si el cliente pidió actualizaciones delta
+func wantsDelta(client interfaces.Client) bool {
+	withMode, ok := client.(interface{ UpdateMode() string })
+	return ok && withMode.UpdateMode() == UpdateModeDelta
+}
+
+// broadcastGameUpdate envía la actualización de una jugada a todos los miembros de la sala:
+// tablero completo a quienes no eligieron delta y solo los cambios a quienes sí.
+// Ambas versiones del mensaje comparten la versión de la sala
+func (r *Room) broadcastGameUpdate(move models.MovePayload, changes []models.CellChange) {
+	fullMsg := models.GameUpdateResponse{
+		Type:        "GAME_UPDATE",
+		Board:       getBoardJSON(r.GameState.Board),
+		CurrentTurn: r.GameState.CurrentTurnSymbol,
+		LastMove:    move,
+		Clocks:      r.clockMillis(),
+	}
+
+	deltaMsg := models.GameDeltaResponse{
+		Type:        "GAME_UPDATE",
+		Delta:       true,
+		MoveNumber:  r.moveNumber,
+		Changes:     changes,
+		CurrentTurn: fullMsg.CurrentTurn,
+		LastMove:    move,
+		Clocks:      fullMsg.Clocks,
+	}
+	if r.moveNumber%DeltaCheckpointEvery == 0 {
+		deltaMsg.Checkpoint = true
+		deltaMsg.Board = fullMsg.Board
+	}
+
+	fullBytes, _ := json.Marshal(fullMsg)
+	deltaBytes, _ := json.Marshal(deltaMsg)
+
+	r.version++
+	fullBytes = r.stamp(fullBytes)
+	deltaBytes = r.stamp(deltaBytes)
+
+	send := func(client interfaces.Client) {
+		if wantsDelta(client) {
+			r.deliver(client, deltaBytes, "GAME_UPDATE")
+		} else {
+			r.deliver(client, fullBytes, "GAME_UPDATE")
+		}
+	}
+	for client := range r.Clients {
+		send(client)
+	}
+	for spectator := range r.Spectators {
+		send(spectator)
+	}
+}
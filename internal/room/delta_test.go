@@ -0,0 +1,79 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// deltaClient es un cliente de prueba que pidió actualizaciones delta
+type deltaClient struct {
+	*fakeClient
+}
+
+func (d deltaClient) UpdateMode() string { return UpdateModeDelta }
+
+// TestRoomDeltaUpdates verifica que los clientes delta reciban solo los cambios, con
+// checkpoints periódicos, mientras el resto sigue recibiendo el tablero completo
+func TestRoomDeltaUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings := DefaultSettings()
+	settings.Variant = game.VariantGomoku
+	settings.BoardSize = 15
+	settings.PreferredSymbol = "X"
+	settings, err := ValidateSettings(settings)
+	if err != nil {
+		t.Fatalf("Configuración inválida: %v", err)
+	}
+	r := NewRoomWithSettings("delta-room", settings, nil, ctx)
+	go r.Run()
+
+	p1 := deltaClient{newFakeClient("p1")}
+	p2 := newFakeClient("p2")
+	spectator := newFakeClient("s1")
+	r.Register <- p1
+	r.Register <- p2
+	r.RegisterSpectator <- spectator
+	waitForMessage(t, spectator, "SPECTATING")
+
+	for i := 0; i < DeltaCheckpointEvery; i++ {
+		var mover interface{} = p1
+		row := 0
+		if i%2 == 1 {
+			mover, row = p2, 14
+		}
+		col := (i / 2) * 2
+		r.ReceiveMove <- &models.PlayerMove{Client: mover, MoveData: models.MovePayload{Row: row, Col: col}}
+
+		delta := waitForMessage(t, p1.fakeClient, "GAME_UPDATE")
+		full := waitForMessage(t, spectator, "GAME_UPDATE")
+
+		if delta["delta"] != true || delta["version"] != full["version"] {
+			t.Fatalf("Jugada %d: delta %v, versión %v frente a %v", i+1, delta["delta"], delta["version"], full["version"])
+		}
+		if full["board"] == nil || full["delta"] != nil {
+			t.Errorf("Jugada %d: el espectador debería recibir el tablero completo", i+1)
+		}
+
+		changes, _ := delta["changes"].([]interface{})
+		if len(changes) != 1 {
+			t.Fatalf("Jugada %d: se esperaba un cambio, obtenidos %v", i+1, delta["changes"])
+		}
+		change := changes[0].(map[string]interface{})
+		if change["row"] != float64(row) || change["col"] != float64(col) {
+			t.Errorf("Jugada %d: cambio incorrecto %v", i+1, change)
+		}
+
+		checkpoint := i+1 == DeltaCheckpointEvery
+		if (delta["board"] != nil) != checkpoint || (delta["checkpoint"] == true) != checkpoint {
+			t.Errorf("Jugada %d: checkpoint=%v board=%v", i+1, delta["checkpoint"], delta["board"] != nil)
+		}
+		if delta["moveNumber"] != float64(i+1) {
+			t.Errorf("Jugada %d: moveNumber %v", i+1, delta["moveNumber"])
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package room
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// timerGracePrefix identifica los temporizadores de gracia; cada jugador desconectado tiene el suyo
+const timerGracePrefix = "grace:"
+
+// graceTimerKind devuelve el tipo de temporizador de gracia de un jugador
+func graceTimerKind(playerID string) string {
+	return timerGracePrefix + playerID
+}
+
+// graceTimerPlayer extrae el ID del jugador de un temporizador de gracia
+func graceTimerPlayer(kind string) (string, bool) {
+	return strings.CutPrefix(kind, timerGracePrefix)
+}
+
+// startGracePeriod conserva el asiento de un jugador que se desconectó durante la partida,
+// pausa el juego y da al jugador un plazo para volver antes de perder por abandono
+func (r *Room) startGracePeriod(client interfaces.Client) {
+	playerID := client.GetID()
+	grace := time.Duration(r.Settings.DisconnectGrace) * time.Second
+	deadline := time.Now().Add(grace)
+
+	delete(r.Clients, client)
+	client.SetRoom(nil)
+
+	r.disconnected[playerID] = deadline
+	r.record(roomlog.Event{Type: roomlog.EventDisconnected, PlayerID: playerID})
+	r.startTimer(graceTimerKind(playerID), grace)
+	r.pauseGame("player_disconnected")
+
+	disconnectedMsg := models.PlayerDisconnectedResponse{
+		Type:         "PLAYER_DISCONNECTED",
+		PlayerID:     playerID,
+		GraceSeconds: r.Settings.DisconnectGrace,
+		Deadline:     deadline.UnixMilli(),
+	}
+	msgBytes, _ := json.Marshal(disconnectedMsg)
+	r.broadcastToAll(msgBytes, "PLAYER_DISCONNECTED")
+
+	logger.Info("Jugador desconectado, esperando reconexión", logger.Fields{
+		"roomID":   r.ID,
+		"clientID": playerID,
+		"grace":    r.Settings.DisconnectGrace,
+	})
+}
+
+// endGracePeriod cancela el plazo de un jugador que volvió. Devuelve false si no estaba desconectado
+func (r *Room) endGracePeriod(playerID string) bool {
+	if _, ok := r.disconnected[playerID]; !ok {
+		return false
+	}
+
+	delete(r.disconnected, playerID)
+	r.record(roomlog.Event{Type: roomlog.EventReconnected, PlayerID: playerID})
+	r.stopTimer(graceTimerKind(playerID))
+	return true
+}
+
+// handleGraceExpired libera el asiento de un jugador que no volvió a tiempo. Si la partida
+// seguía en curso, la pierde por abandono
+func (r *Room) handleGraceExpired(playerID string) {
+	if _, ok := r.disconnected[playerID]; !ok {
+		return
+	}
+	delete(r.disconnected, playerID)
+
+	logger.Info("Tiempo de gracia agotado", logger.Fields{
+		"roomID":   r.ID,
+		"clientID": playerID,
+	})
+
+	// El rival gana aunque también esté desconectado
+	winnerID := ""
+	for seatedID := range r.GameState.PlayerSymbols {
+		if seatedID != playerID {
+			winnerID = seatedID
+		}
+	}
+
+	r.unseatPlayer(playerID, "grace_expired")
+	r.migrateHost(playerID)
+
+	playerLeftMsg := models.PlayerLeftResponse{
+		Type:     "PLAYER_LEFT",
+		PlayerID: playerID,
+	}
+	msgBytes, _ := json.Marshal(playerLeftMsg)
+	r.broadcastToAll(msgBytes, "PLAYER_LEFT")
+
+	if r.gameInProgress() && winnerID != "" {
+		r.GameState.IsGameOver = true
+		r.GameState.Winner = r.GameState.PlayerSymbols[winnerID]
+		r.stopClock()
+		r.announceGameOver(winnerID, false, "abandonment")
+	}
+}
+
+// pauseGame detiene la partida en curso y su reloj
+func (r *Room) pauseGame(reason string) {
+	if r.state != StatePlaying {
+		return
+	}
+
+	r.stopClock()
+	r.transition(StatePaused, reason)
+}
+
+// resumeGame reanuda la partida si ya no queda ningún motivo para mantenerla en pausa
+// (jugadores desconectados o una pausa de mutuo acuerdo)
+func (r *Room) resumeGame(reason string) {
+	if r.state != StatePaused || len(r.disconnected) > 0 || r.pause != nil {
+		return
+	}
+
+	r.transition(StatePlaying, reason)
+
+	// El reloj continúa con el tiempo que le quedaba al jugador en turno
+	if r.Clock != nil {
+		r.Clock.Start(r.GameState.CurrentTurnSymbol, time.Now())
+		r.scheduleClockTimer()
+	}
+}
@@ -0,0 +1,77 @@
+package room
+
+import (
+	"nvivas/backend/tictactoe-go-server/internal/archive"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// record anota un evento en el registro de la sala. Solo se llama desde el bucle de la sala
+func (r *Room) record(e roomlog.Event) {
+	r.log.Append(e)
+}
+
+// seatPlayer asigna un símbolo a un jugador y lo registra
+func (r *Room) seatPlayer(playerID, symbol string) {
+	r.GameState.PlayerSymbols[playerID] = symbol
+	r.record(roomlog.Event{Type: roomlog.EventPlayerJoined, PlayerID: playerID, Symbol: symbol})
+}
+
+// unseatPlayer libera el asiento de un jugador y lo registra
+func (r *Room) unseatPlayer(playerID, reason string) {
+	if _, seated := r.GameState.PlayerSymbols[playerID]; !seated {
+		return
+	}
+	delete(r.GameState.PlayerSymbols, playerID)
+	r.record(roomlog.Event{Type: roomlog.EventPlayerLeft, PlayerID: playerID, Reason: reason})
+}
+
+// assignHost cambia el anfitrión sin notificarlo y lo registra
+func (r *Room) assignHost(hostID, reason string) {
+	r.HostID = hostID
+	r.record(roomlog.Event{Type: roomlog.EventHostChanged, PlayerID: hostID, Reason: reason})
+}
+
+// finishedGame construye el historial de la partida recién terminada a partir del registro
+func (r *Room) finishedGame() (models.GameRecord, bool) {
+	record, err := archive.FromEvents(r.log.Events())
+	if err != nil {
+		logger.Warn("No se pudo construir el historial de la partida", logger.Fields{
+			"roomID": r.ID,
+			"error":  err.Error(),
+		})
+		return models.GameRecord{}, false
+	}
+	record.RoomID = r.ID
+	record.RoomCode = r.Code
+	record.Variant = r.Settings.Variant
+	return record, true
+}
+
+// archiveGame envía al Hub el historial de la partida recién terminada para poder repetirla
+// después de eliminar la sala
+func (r *Room) archiveGame(record models.GameRecord) {
+	archiver, ok := r.Hub.(interface {
+		ArchiveGame(record models.GameRecord)
+	})
+	if !ok {
+		return
+	}
+
+	// El Hub escribe en disco: no se bloquea el bucle de la sala
+	go archiver.ArchiveGame(record)
+}
+
+// reportResult comunica al Hub el resultado de la partida recién terminada, por ejemplo
+// para que avance un torneo. Como el archivo, se envía fuera del bucle de la sala
+func (r *Room) reportResult(record models.GameRecord) {
+	reporter, ok := r.Hub.(interface {
+		GameFinished(record models.GameRecord)
+	})
+	if !ok {
+		return
+	}
+
+	go reporter.GameFinished(record)
+}
@@ -0,0 +1,129 @@
+package room
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// TestRoomEventLogReplay verifica que el registro de la sala reconstruya exactamente su partida
+func TestRoomEventLogReplay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.PreferredSymbol = "X"
+	r := NewRoomWithSettings("log-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2, spectator := newFakeClient("p1"), newFakeClient("p2"), newFakeClient("s1")
+	r.Register <- p1
+	r.RegisterSpectator <- spectator
+	waitForMessage(t, spectator, "SPECTATING")
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	moves := []struct {
+		client *fakeClient
+		row    int
+		col    int
+	}{
+		{p1, 0, 0}, {p2, 1, 0}, {p1, 0, 1}, {p2, 1, 1},
+	}
+	for _, m := range moves {
+		r.ReceiveMove <- &models.PlayerMove{Client: m.client, MoveData: models.MovePayload{Row: m.row, Col: m.col}}
+		waitForMessage(t, spectator, "GAME_UPDATE")
+	}
+
+	// A mitad de partida, el registro reproduce el estado actual
+	snap, ok := r.Snapshot()
+	if !ok {
+		t.Fatal("No se obtuvo la copia de la sala")
+	}
+	replayed, err := roomlog.Replay(snap.Events)
+	if err != nil {
+		t.Fatalf("Error reproduciendo el registro: %v", err)
+	}
+	if !reflect.DeepEqual(replayed, snap.Game) {
+		t.Errorf("Estado reproducido distinto:\n%+v\n%+v", replayed, snap.Game)
+	}
+
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 0, Col: 2}}
+	waitForMessage(t, spectator, "GAME_OVER")
+
+	snap, _ = r.Snapshot()
+	state, err := roomlog.ReplayRoom(snap.Events)
+	if err != nil {
+		t.Fatalf("Error reproduciendo el registro: %v", err)
+	}
+	if !reflect.DeepEqual(state.Game, snap.Game) {
+		t.Errorf("Estado final reproducido distinto:\n%+v\n%+v", state.Game, snap.Game)
+	}
+	if state.State != string(StateFinished) || state.HostID != "p1" || !state.Spectators["s1"] {
+		t.Errorf("Estado de sala reproducido incorrecto: %+v", state)
+	}
+
+	last := snap.Events[len(snap.Events)-2]
+	if last.Type != roomlog.EventGameOver || last.PlayerID != "p1" || last.Reason != "win" {
+		t.Errorf("Se esperaba game_over antes del cambio de estado, obtenido %+v", last)
+	}
+}
+
+// archivingHub es un Hub mínimo que recoge las partidas archivadas por la sala
+type archivingHub struct {
+	archived chan models.GameRecord
+}
+
+func (h *archivingHub) UnregisterClient(interfaces.Client)                   {}
+func (h *archivingHub) CreateRoom(interfaces.Client, models.RoomSettings)    {}
+func (h *archivingHub) JoinRoom(string, interfaces.Client)                   {}
+func (h *archivingHub) SpectateRoom(string, interfaces.Client)               {}
+func (h *archivingHub) DeleteRoom(string)                                    {}
+func (h *archivingHub) ListRooms(interfaces.Client, models.ListRoomsPayload) {}
+func (h *archivingHub) ArchiveGame(record models.GameRecord)                 { h.archived <- record }
+
+// TestRoomArchivesFinishedGame verifica que la sala archive la partida al terminar
+func TestRoomArchivesFinishedGame(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := &archivingHub{archived: make(chan models.GameRecord, 1)}
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.PreferredSymbol = "X"
+	r := NewRoomWithSettings("archive-room", settings, hub, ctx)
+	r.Code = "ARC123"
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	start := waitForMessage(t, p1, "GAME_START")
+
+	for i, m := range []struct {
+		client   *fakeClient
+		row, col int
+	}{{p1, 0, 0}, {p2, 1, 0}, {p1, 0, 1}, {p2, 1, 1}, {p1, 0, 2}} {
+		r.ReceiveMove <- &models.PlayerMove{Client: m.client, MoveData: models.MovePayload{Row: m.row, Col: m.col}}
+		if i < 4 {
+			waitForMessage(t, p1, "GAME_UPDATE")
+		}
+	}
+	over := waitForMessage(t, p1, "GAME_OVER")
+
+	select {
+	case record := <-hub.archived:
+		if record.ID == "" || record.ID != start["gameId"] || record.ID != over["gameId"] {
+			t.Errorf("ID de partida inconsistente: %s, %v, %v", record.ID, start["gameId"], over["gameId"])
+		}
+		if record.RoomCode != "ARC123" || record.Winner != "p1" || record.Reason != "win" || len(record.Moves) != 5 {
+			t.Errorf("Historial incorrecto: %+v", record)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("La partida no se archivó")
+	}
+}
@@ -0,0 +1,266 @@
+package room
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/coinflip"
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+const (
+	// Políticas para decidir quién mueve primero
+	FirstMoveCreator  = "creator"
+	FirstMoveJoiner   = "joiner"
+	FirstMoveRandom   = "random"
+	FirstMoveCoinFlip = "coinflip"
+
+	// coinFlipTimeout es el plazo de cada fase del sorteo (compromiso y revelación)
+	coinFlipTimeout = 30 * time.Second
+)
+
+// Fases del sorteo commit-reveal
+const (
+	coinFlipCommit = "commit"
+	coinFlipReveal = "reveal"
+)
+
+// coinFlipState guarda el progreso del sorteo commit-reveal
+type coinFlipState struct {
+	phase       string
+	deadline    time.Time
+	commitments map[string]string // ID de jugador -> SHA-256 del nonce
+	nonces      map[string][]byte // ID de jugador -> nonce revelado
+	invalid     map[string]bool   // Jugadores cuya revelación no coincidió
+}
+
+// beginGame decide quién mueve primero según la política de la sala y arranca la partida.
+// Con la política coinflip, la partida empieza cuando termina el sorteo
+func (r *Room) beginGame() {
+	switch r.Settings.FirstMove {
+	case FirstMoveJoiner:
+		r.GameState.CurrentTurnSymbol = game.OppositeSymbol(r.creatorSymbol)
+	case FirstMoveRandom:
+		r.GameState.CurrentTurnSymbol = []string{"X", "O"}[rand.Intn(2)]
+	case FirstMoveCoinFlip:
+		r.startCoinFlip()
+		return
+	default:
+		r.GameState.CurrentTurnSymbol = r.creatorSymbol
+	}
+
+	r.startGame()
+}
+
+// startCoinFlip abre la fase de compromiso del sorteo
+func (r *Room) startCoinFlip() {
+	r.coinFlip = &coinFlipState{
+		phase:       coinFlipCommit,
+		deadline:    time.Now().Add(coinFlipTimeout),
+		commitments: make(map[string]string),
+		nonces:      make(map[string][]byte),
+		invalid:     make(map[string]bool),
+	}
+	r.startTimer(timerCoinFlip, coinFlipTimeout)
+	r.transition(StateReadyCheck, "coin_flip")
+
+	for client := range r.Clients {
+		r.sendCoinFlipState(client)
+	}
+	for spectator := range r.Spectators {
+		r.sendCoinFlipState(spectator)
+	}
+
+	logger.Info("Sorteo de primer movimiento iniciado", logger.Fields{"roomID": r.ID})
+}
+
+// sendCoinFlipState envía el estado del sorteo a un cliente (por ejemplo al reconectarse)
+func (r *Room) sendCoinFlipState(client interfaces.Client) {
+	stateMsg := models.CoinFlipResponse{
+		Type:        "COIN_FLIP",
+		Phase:       r.coinFlip.phase,
+		CreatorID:   r.playerIDForSymbol(r.creatorSymbol),
+		Players:     r.GameState.PlayerSymbols,
+		Commitments: r.coinFlip.commitments,
+		Deadline:    r.coinFlip.deadline.UnixMilli(),
+	}
+	msgBytes, _ := json.Marshal(stateMsg)
+	r.sendToClient(client, msgBytes, "COIN_FLIP")
+}
+
+// handleCoinFlipCommit registra el compromiso (hash del nonce) de un jugador
+func (r *Room) handleCoinFlipCommit(cmd *Command) {
+	if !r.requireCoinFlipPhase(cmd.Client, coinFlipCommit) {
+		return
+	}
+
+	var payload models.CoinFlipCommitPayload
+	if err := json.Unmarshal(cmd.Payload, &payload); err != nil {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), "coin flip commit", cmd.Client.GetID())
+		return
+	}
+
+	commitment, err := coinflip.NormalizeCommitment(payload.Commitment)
+	if err != nil {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), err.Error(), cmd.Client.GetID())
+		return
+	}
+
+	// El compromiso no se puede cambiar una vez enviado
+	if _, exists := r.coinFlip.commitments[cmd.Client.GetID()]; exists {
+		errors.InvalidCoinFlip(cmd.Client.GetSendChannel(), "Ya enviaste tu compromiso", cmd.Client.GetID())
+		return
+	}
+	r.coinFlip.commitments[cmd.Client.GetID()] = commitment
+
+	// Cuando todos se comprometieron, se pasa a la fase de revelación
+	if len(r.coinFlip.commitments) < len(r.GameState.PlayerSymbols) {
+		r.broadcastCoinFlipState()
+		return
+	}
+
+	r.coinFlip.phase = coinFlipReveal
+	r.coinFlip.deadline = time.Now().Add(coinFlipTimeout)
+	r.startTimer(timerCoinFlip, coinFlipTimeout)
+	r.broadcastCoinFlipState()
+}
+
+// handleCoinFlipReveal registra el nonce revelado y, si coincide con el compromiso,
+// lo usa para el sorteo
+func (r *Room) handleCoinFlipReveal(cmd *Command) {
+	if !r.requireCoinFlipPhase(cmd.Client, coinFlipReveal) {
+		return
+	}
+
+	if _, revealed := r.coinFlip.nonces[cmd.Client.GetID()]; revealed || r.coinFlip.invalid[cmd.Client.GetID()] {
+		errors.InvalidCoinFlip(cmd.Client.GetSendChannel(), "Ya revelaste tu nonce", cmd.Client.GetID())
+		return
+	}
+
+	var payload models.CoinFlipRevealPayload
+	if err := json.Unmarshal(cmd.Payload, &payload); err != nil {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), "coin flip reveal", cmd.Client.GetID())
+		return
+	}
+
+	nonce, err := coinflip.ParseNonce(payload.Nonce)
+	if err != nil {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), err.Error(), cmd.Client.GetID())
+		return
+	}
+
+	if err := coinflip.Verify(r.coinFlip.commitments[cmd.Client.GetID()], nonce); err != nil {
+		// Una revelación que no coincide pierde el sorteo
+		r.coinFlip.invalid[cmd.Client.GetID()] = true
+		errors.InvalidCoinFlip(cmd.Client.GetSendChannel(), err.Error(), cmd.Client.GetID())
+	} else {
+		r.coinFlip.nonces[cmd.Client.GetID()] = nonce
+	}
+
+	if len(r.coinFlip.nonces)+len(r.coinFlip.invalid) == len(r.GameState.PlayerSymbols) {
+		r.resolveCoinFlip()
+	}
+}
+
+// handleCoinFlipTimeout resuelve el sorteo cuando un jugador no completa su fase a tiempo
+func (r *Room) handleCoinFlipTimeout() {
+	if r.coinFlip == nil {
+		return
+	}
+	r.resolveCoinFlip()
+}
+
+// resolveCoinFlip decide quién empieza. Si ambos revelaron correctamente decide el XOR
+// de los nonces; si solo uno cumplió, empieza ese jugador; si ninguno, empieza el creador
+func (r *Room) resolveCoinFlip() {
+	flip := r.coinFlip
+	r.stopTimer(timerCoinFlip)
+	r.coinFlip = nil
+
+	creatorID := r.playerIDForSymbol(r.creatorSymbol)
+	joinerID := r.playerIDForSymbol(game.OppositeSymbol(r.creatorSymbol))
+
+	creatorNonce, creatorOK := flip.nonces[creatorID]
+	joinerNonce, joinerOK := flip.nonces[joinerID]
+
+	firstID := creatorID
+	reason := "xor"
+	switch {
+	case creatorOK && joinerOK:
+		if coinflip.Decide(creatorNonce, joinerNonce) == 1 {
+			firstID = joinerID
+		}
+	case creatorOK:
+		reason = "forfeit"
+	case joinerOK:
+		firstID = joinerID
+		reason = "forfeit"
+	default:
+		reason = "default"
+	}
+
+	r.GameState.CurrentTurnSymbol = r.GameState.PlayerSymbols[firstID]
+
+	revealed := make(map[string]string, len(flip.nonces))
+	for playerID, nonce := range flip.nonces {
+		revealed[playerID] = hex.EncodeToString(nonce)
+	}
+
+	resultMsg := models.CoinFlipResultResponse{
+		Type:          "COIN_FLIP_RESULT",
+		Commitments:   flip.commitments,
+		Nonces:        revealed,
+		FirstPlayerID: firstID,
+		FirstSymbol:   r.GameState.CurrentTurnSymbol,
+		Reason:        reason,
+	}
+	msgBytes, _ := json.Marshal(resultMsg)
+	r.broadcastToAll(msgBytes, "COIN_FLIP_RESULT")
+
+	logger.Info("Sorteo de primer movimiento resuelto", logger.Fields{
+		"roomID":        r.ID,
+		"firstPlayerID": firstID,
+		"reason":        reason,
+	})
+
+	r.startGame()
+}
+
+// cancelCoinFlip descarta el sorteo cuando un jugador abandona o es expulsado
+func (r *Room) cancelCoinFlip() {
+	if r.coinFlip == nil {
+		return
+	}
+	r.coinFlip = nil
+	r.stopTimer(timerCoinFlip)
+	r.transition(StateWaiting, "player_left")
+}
+
+// broadcastCoinFlipState envía el estado del sorteo a toda la sala
+func (r *Room) broadcastCoinFlipState() {
+	for client := range r.Clients {
+		r.sendCoinFlipState(client)
+	}
+	for spectator := range r.Spectators {
+		r.sendCoinFlipState(spectator)
+	}
+}
+
+// requireCoinFlipPhase comprueba que haya un sorteo en la fase indicada y que el cliente juegue
+func (r *Room) requireCoinFlipPhase(client interfaces.Client, phase string) bool {
+	if r.coinFlip == nil || r.coinFlip.phase != phase {
+		errors.InvalidCoinFlip(client.GetSendChannel(), "No hay un sorteo en la fase "+phase, client.GetID())
+		return false
+	}
+	if _, isPlayer := r.GameState.PlayerSymbols[client.GetID()]; !isPlayer {
+		errors.NotInGame(client.GetSendChannel(), client.GetID())
+		return false
+	}
+	return true
+}
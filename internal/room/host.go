@@ -0,0 +1,201 @@
+package room
+
+import (
+	"encoding/json"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// handleKickPlayer expulsa a un jugador o espectador de la sala. Solo el anfitrión
+// puede hacerlo y no se puede expulsar a un jugador con una partida en curso
+func (r *Room) handleKickPlayer(cmd *Command) {
+	if !r.requireHost(cmd.Client) {
+		return
+	}
+
+	var payload models.TargetPlayerPayload
+	if err := json.Unmarshal(cmd.Payload, &payload); err != nil || payload.PlayerID == "" {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), "kick player", cmd.Client.GetID())
+		return
+	}
+
+	if payload.PlayerID == cmd.Client.GetID() {
+		errors.InvalidTarget(cmd.Client.GetSendChannel(), "No puedes expulsarte a ti mismo", cmd.Client.GetID())
+		return
+	}
+
+	target, isPlayer := r.findMember(payload.PlayerID)
+	if target == nil {
+		errors.InvalidTarget(cmd.Client.GetSendChannel(), "El jugador no está en la sala", cmd.Client.GetID())
+		return
+	}
+
+	if isPlayer && r.gameInProgress() {
+		errors.InvalidTarget(cmd.Client.GetSendChannel(), "No se puede expulsar a un jugador durante una partida", cmd.Client.GetID())
+		return
+	}
+
+	// Retirar al expulsado e impedir que vuelva a entrar
+	r.banned[payload.PlayerID] = true
+	if isPlayer {
+		delete(r.Clients, target)
+		r.unseatPlayer(payload.PlayerID, "kicked")
+		r.cancelReadyCheck()
+		r.cancelCoinFlip()
+	} else {
+		delete(r.Spectators, target)
+		r.record(roomlog.Event{Type: roomlog.EventSpectatorLeft, PlayerID: payload.PlayerID, Reason: "kicked"})
+	}
+	target.SetRoom(nil)
+
+	kickedMsg := models.KickedResponse{
+		Type:   "KICKED_FROM_ROOM",
+		RoomID: r.ID,
+		By:     cmd.Client.GetID(),
+	}
+	kickedBytes, _ := json.Marshal(kickedMsg)
+	r.sendToClient(target, kickedBytes, "KICKED_FROM_ROOM")
+
+	notice := models.PlayerKickedResponse{
+		Type:     "PLAYER_KICKED",
+		PlayerID: payload.PlayerID,
+		By:       cmd.Client.GetID(),
+	}
+	noticeBytes, _ := json.Marshal(notice)
+	r.broadcastToAll(noticeBytes, "PLAYER_KICKED")
+
+	logger.Info("Jugador expulsado de la sala", logger.Fields{
+		"roomID":   r.ID,
+		"hostID":   cmd.Client.GetID(),
+		"targetID": payload.PlayerID,
+		"isPlayer": isPlayer,
+	})
+}
+
+// handleSetLocked bloquea o desbloquea la entrada de nuevos jugadores y espectadores
+func (r *Room) handleSetLocked(cmd *Command, locked bool) {
+	if !r.requireHost(cmd.Client) {
+		return
+	}
+
+	if r.locked == locked {
+		return
+	}
+	r.locked = locked
+	r.record(roomlog.Event{Type: roomlog.EventLockChanged, Locked: locked})
+
+	msgType := "ROOM_UNLOCKED"
+	if locked {
+		msgType = "ROOM_LOCKED"
+	}
+
+	lockMsg := models.RoomLockResponse{
+		Type:   msgType,
+		RoomID: r.ID,
+		Locked: locked,
+		By:     cmd.Client.GetID(),
+	}
+	msgBytes, _ := json.Marshal(lockMsg)
+	r.broadcastToAll(msgBytes, msgType)
+
+	logger.Info("Estado de bloqueo de sala cambiado", logger.Fields{
+		"roomID": r.ID,
+		"hostID": cmd.Client.GetID(),
+		"locked": locked,
+	})
+}
+
+// handleTransferHost cede el rol de anfitrión a otro miembro de la sala
+func (r *Room) handleTransferHost(cmd *Command) {
+	if !r.requireHost(cmd.Client) {
+		return
+	}
+
+	var payload models.TargetPlayerPayload
+	if err := json.Unmarshal(cmd.Payload, &payload); err != nil || payload.PlayerID == "" {
+		errors.InvalidPayload(cmd.Client.GetSendChannel(), "transfer host", cmd.Client.GetID())
+		return
+	}
+
+	if target, _ := r.findMember(payload.PlayerID); target == nil {
+		errors.InvalidTarget(cmd.Client.GetSendChannel(), "El jugador no está en la sala", cmd.Client.GetID())
+		return
+	}
+
+	r.setHost(payload.PlayerID, "transferred")
+}
+
+// migrateHost elige un nuevo anfitrión cuando el actual abandona la sala.
+// Se prefiere a un jugador sobre un espectador
+func (r *Room) migrateHost(leavingID string) {
+	if r.HostID != leavingID {
+		return
+	}
+
+	for client := range r.Clients {
+		r.setHost(client.GetID(), "host_left")
+		return
+	}
+	for spectator := range r.Spectators {
+		r.setHost(spectator.GetID(), "host_left")
+		return
+	}
+
+	// No queda nadie en la sala
+	r.assignHost("", "host_left")
+}
+
+// setHost cambia el anfitrión y lo notifica a toda la sala
+func (r *Room) setHost(hostID, reason string) {
+	previous := r.HostID
+	r.assignHost(hostID, reason)
+
+	hostMsg := models.HostChangedResponse{
+		Type:           "HOST_CHANGED",
+		HostID:         hostID,
+		PreviousHostID: previous,
+		Reason:         reason,
+	}
+	msgBytes, _ := json.Marshal(hostMsg)
+	r.broadcastToAll(msgBytes, "HOST_CHANGED")
+
+	logger.Info("Anfitrión de sala cambiado", logger.Fields{
+		"roomID":     r.ID,
+		"hostID":     hostID,
+		"previousID": previous,
+		"reason":     reason,
+	})
+}
+
+// requireHost comprueba que el cliente sea el anfitrión y, si no lo es, le envía un error
+func (r *Room) requireHost(client interfaces.Client) bool {
+	if client.GetID() != r.HostID {
+		errors.NotHost(client.GetSendChannel(), client.GetID())
+		return false
+	}
+	return true
+}
+
+// findMember busca a un miembro de la sala por su ID e indica si es jugador
+func (r *Room) findMember(playerID string) (interfaces.Client, bool) {
+	for client := range r.Clients {
+		if client.GetID() == playerID {
+			return client, true
+		}
+	}
+	for spectator := range r.Spectators {
+		if spectator.GetID() == playerID {
+			return spectator, false
+		}
+	}
+	return nil, false
+}
+
+// gameInProgress indica si hay una partida empezada y sin terminar
+func (r *Room) gameInProgress() bool {
+	return r.state == StatePlaying || r.state == StatePaused
+}
@@ -0,0 +1,33 @@
+package room
+
+import (
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// lobbyPublisher recibe los cambios de las salas públicas para el vestíbulo en vivo.
+// Sus métodos no bloquean, así que se llaman directamente desde el bucle de la sala
+type lobbyPublisher interface {
+	RoomChanged(info models.RoomInfo)
+	RoomRemoved(roomID string)
+}
+
+// publishInfo publica en el vestíbulo el resumen de la sala si es pública y sigue abierta.
+// Solo se llama desde el bucle de la sala
+func (r *Room) publishInfo(info models.RoomInfo) {
+	if r.Settings.Visibility != VisibilityPublic || r.state == StateClosed {
+		return
+	}
+	if publisher, ok := r.Hub.(lobbyPublisher); ok {
+		publisher.RoomChanged(info)
+	}
+}
+
+// publishRemoved avisa al vestíbulo de que la sala se ha cerrado
+func (r *Room) publishRemoved() {
+	if r.Settings.Visibility != VisibilityPublic {
+		return
+	}
+	if publisher, ok := r.Hub.(lobbyPublisher); ok {
+		publisher.RoomRemoved(r.ID)
+	}
+}
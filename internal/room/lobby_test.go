@@ -0,0 +1,61 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/internal/lobby"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// lobbyHub es un Hub mínimo que publica los cambios de las salas en un vestíbulo real
+type lobbyHub struct {
+	archivingHub
+	*lobby.Lobby
+}
+
+// TestRoomPublishesToLobby verifica que una sala pública avise al vestíbulo al abrirse,
+// al llenarse y al cerrarse, y que una privada no aparezca
+func TestRoomPublishesToLobby(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := &lobbyHub{archivingHub: archivingHub{archived: make(chan models.GameRecord, 1)}, Lobby: lobby.New()}
+	subscriber := newFakeClient("watcher")
+	hub.Subscribe(subscriber)
+	waitForMessage(t, subscriber, "LOBBY")
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	private := settings
+	private.Visibility = VisibilityPrivate
+	hidden := NewRoomWithSettings("hidden-room", private, hub, ctx)
+	go hidden.Run()
+
+	r := NewRoomWithSettings("lobby-room", settings, hub, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	added := waitForMessage(t, subscriber, "ROOM_ADDED")
+	if added["room"].(map[string]interface{})["roomId"] != "lobby-room" {
+		t.Fatalf("ROOM_ADDED de otra sala: %v", added)
+	}
+
+	r.Register <- p2
+	for {
+		updated := waitForMessage(t, subscriber, "ROOM_UPDATED")
+		info := updated["room"].(map[string]interface{})
+		if info["roomId"] != "lobby-room" {
+			t.Fatalf("ROOM_UPDATED de otra sala: %v", updated)
+		}
+		if info["isFull"] == true && info["state"] == string(StatePlaying) {
+			break
+		}
+	}
+
+	r.Close()
+	removed := waitForMessage(t, subscriber, "ROOM_REMOVED")
+	if removed["roomId"] != "lobby-room" {
+		t.Errorf("ROOM_REMOVED incorrecto: %v", removed)
+	}
+}
@@ -0,0 +1,164 @@
+package room
+
+import (
+	"encoding/json"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// mutualPause describe una pausa acordada por ambos jugadores
+type mutualPause struct {
+	requestedBy string
+	acceptedBy  string
+	deadline    time.Time // Momento en que la partida se reanuda sola
+}
+
+// handleRequestPause registra la solicitud de pausa de un jugador. El rival debe aceptarla
+func (r *Room) handleRequestPause(cmd *Command) {
+	if !r.requireSeatedPlayer(cmd.Client) {
+		return
+	}
+
+	switch {
+	case r.Settings.MaxPauses == 0:
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "Esta sala no permite pausas", cmd.Client.GetID())
+		return
+	case r.state != StatePlaying:
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "Solo se puede pausar una partida en curso", cmd.Client.GetID())
+		return
+	case r.pausesUsed >= r.Settings.MaxPauses:
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "Ya se usaron todas las pausas de la partida", cmd.Client.GetID())
+		return
+	case r.pauseRequestedBy != "":
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "Ya hay una solicitud de pausa pendiente", cmd.Client.GetID())
+		return
+	}
+
+	r.pauseRequestedBy = cmd.Client.GetID()
+
+	requestMsg := models.PauseRequestedResponse{
+		Type:       "PAUSE_REQUESTED",
+		PlayerID:   cmd.Client.GetID(),
+		PausesUsed: r.pausesUsed,
+		MaxPauses:  r.Settings.MaxPauses,
+	}
+	msgBytes, _ := json.Marshal(requestMsg)
+	r.broadcastToAll(msgBytes, "PAUSE_REQUESTED")
+
+	logger.Info("Pausa solicitada", logger.Fields{
+		"roomID":   r.ID,
+		"clientID": cmd.Client.GetID(),
+	})
+}
+
+// handleAcceptPause pausa la partida cuando el rival acepta la solicitud pendiente
+func (r *Room) handleAcceptPause(cmd *Command) {
+	if !r.requireSeatedPlayer(cmd.Client) {
+		return
+	}
+
+	if r.pauseRequestedBy == "" || r.state != StatePlaying {
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "No hay ninguna solicitud de pausa pendiente", cmd.Client.GetID())
+		return
+	}
+
+	if r.pauseRequestedBy == cmd.Client.GetID() {
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "No puedes aceptar tu propia solicitud de pausa", cmd.Client.GetID())
+		return
+	}
+
+	maxDuration := time.Duration(r.Settings.MaxPauseSeconds) * time.Second
+	r.pause = &mutualPause{
+		requestedBy: r.pauseRequestedBy,
+		acceptedBy:  cmd.Client.GetID(),
+		deadline:    time.Now().Add(maxDuration),
+	}
+	r.pauseRequestedBy = ""
+	r.pausesUsed++
+
+	r.pauseGame("mutual_pause")
+	r.startTimer(timerPause, maxDuration)
+
+	pausedMsg := models.GamePausedResponse{
+		Type:           "GAME_PAUSED",
+		RequestedBy:    r.pause.requestedBy,
+		AcceptedBy:     r.pause.acceptedBy,
+		ResumeDeadline: r.pause.deadline.UnixMilli(),
+		PausesUsed:     r.pausesUsed,
+		MaxPauses:      r.Settings.MaxPauses,
+		Clocks:         r.clockMillis(),
+	}
+	msgBytes, _ := json.Marshal(pausedMsg)
+	r.broadcastToAll(msgBytes, "GAME_PAUSED")
+
+	logger.Info("Partida pausada de mutuo acuerdo", logger.Fields{
+		"roomID":      r.ID,
+		"requestedBy": r.pause.requestedBy,
+		"acceptedBy":  r.pause.acceptedBy,
+		"pausesUsed":  r.pausesUsed,
+	})
+}
+
+// handleResumeGame termina la pausa a petición de cualquiera de los dos jugadores
+func (r *Room) handleResumeGame(cmd *Command) {
+	if !r.requireSeatedPlayer(cmd.Client) {
+		return
+	}
+
+	if r.pause == nil {
+		errors.InvalidPause(cmd.Client.GetSendChannel(), "La partida no está en pausa", cmd.Client.GetID())
+		return
+	}
+
+	r.endPause(cmd.Client.GetID(), "resumed")
+}
+
+// handlePauseExpired reanuda la partida cuando la pausa alcanza su duración máxima
+func (r *Room) handlePauseExpired() {
+	if r.pause == nil {
+		return
+	}
+	r.endPause("", "pause_expired")
+}
+
+// endPause termina la pausa acordada. La partida sigue detenida si algún jugador está desconectado
+func (r *Room) endPause(resumedBy, reason string) {
+	r.pause = nil
+	r.stopTimer(timerPause)
+	r.resumeGame(reason)
+
+	resumedMsg := models.GameResumedResponse{
+		Type:      "GAME_RESUMED",
+		ResumedBy: resumedBy,
+		Reason:    reason,
+		Clocks:    r.clockMillis(),
+	}
+	msgBytes, _ := json.Marshal(resumedMsg)
+	r.broadcastToAll(msgBytes, "GAME_RESUMED")
+
+	logger.Info("Pausa terminada", logger.Fields{
+		"roomID":    r.ID,
+		"resumedBy": resumedBy,
+		"reason":    reason,
+	})
+}
+
+// clearPause descarta la pausa y la solicitud pendiente, por ejemplo al terminar la partida
+func (r *Room) clearPause() {
+	r.pause = nil
+	r.pauseRequestedBy = ""
+	r.stopTimer(timerPause)
+}
+
+// requireSeatedPlayer comprueba que el cliente tenga asiento en la partida
+func (r *Room) requireSeatedPlayer(client interfaces.Client) bool {
+	if _, isPlayer := r.GameState.PlayerSymbols[client.GetID()]; !isPlayer {
+		errors.NotInGame(client.GetSendChannel(), client.GetID())
+		return false
+	}
+	return true
+}
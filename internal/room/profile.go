@@ -0,0 +1,33 @@
+package room
+
+import (
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// profileSource da los perfiles públicos de los jugadores. Es seguro para uso concurrente y
+// no toca el disco, así que se llama directamente desde el bucle de la sala
+type profileSource interface {
+	PlayerProfiles(playerIDs []string) map[string]models.PlayerProfile
+}
+
+// playerProfiles devuelve los perfiles de los jugadores indicados que tienen uno, o nil
+func (r *Room) playerProfiles(playerIDs []string) map[string]models.PlayerProfile {
+	source, ok := r.Hub.(profileSource)
+	if !ok || len(playerIDs) == 0 {
+		return nil
+	}
+	return source.PlayerProfiles(playerIDs)
+}
+
+// seatedProfiles devuelve los perfiles de los jugadores con asiento en la sala
+func (r *Room) seatedProfiles() map[string]models.PlayerProfile {
+	return r.playerProfiles(r.sortedPlayerIDs())
+}
+
+// playerProfile devuelve el perfil de un jugador, o nil si no tiene
+func (r *Room) playerProfile(playerID string) *models.PlayerProfile {
+	if p, ok := r.playerProfiles([]string{playerID})[playerID]; ok {
+		return &p
+	}
+	return nil
+}
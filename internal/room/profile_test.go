@@ -0,0 +1,67 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// profileHub es un Hub mínimo con perfiles fijos
+type profileHub struct {
+	archivingHub
+	profiles map[string]models.PlayerProfile
+}
+
+func (h *profileHub) PlayerProfiles(playerIDs []string) map[string]models.PlayerProfile {
+	found := make(map[string]models.PlayerProfile)
+	for _, playerID := range playerIDs {
+		if p, ok := h.profiles[playerID]; ok {
+			found[playerID] = p
+		}
+	}
+	return found
+}
+
+// TestRoomProfiles verifica que los perfiles acompañen a los IDs en PLAYER_JOINED,
+// GAME_START y el resumen de la sala, y que se omitan los jugadores sin perfil
+func TestRoomProfiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := &profileHub{
+		archivingHub: archivingHub{archived: make(chan models.GameRecord, 1)},
+		profiles: map[string]models.PlayerProfile{
+			"p2": {DisplayName: "Bea", Avatar: "fox", Country: "ES"},
+		},
+	}
+	settings, _ := ValidateSettings(DefaultSettings())
+	r := NewRoomWithSettings("profile-room", settings, hub, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+
+	joined := waitForMessage(t, p1, "PLAYER_JOINED")
+	profile, ok := joined["profile"].(map[string]interface{})
+	if !ok || profile["displayName"] != "Bea" || profile["country"] != "ES" {
+		t.Fatalf("PLAYER_JOINED sin el perfil del rival: %v", joined)
+	}
+
+	start := waitForMessage(t, p1, "GAME_START")
+	profiles, ok := start["profiles"].(map[string]interface{})
+	if !ok || len(profiles) != 1 || profiles["p2"] == nil {
+		t.Errorf("GAME_START debería incluir solo el perfil de p2: %v", start["profiles"])
+	}
+
+	// El resumen se actualiza al terminar de atender el registro
+	deadline := time.Now().Add(time.Second)
+	for info := r.Info(); info.Profiles["p2"].Avatar != "fox" || len(info.Profiles) != 1; info = r.Info() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Resumen de sala sin perfiles: %+v", info.Profiles)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,22 @@
+package room
+
+import (
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// rateGame pide al Hub que actualice las puntuaciones de una partida puntuada y devuelve
+// los cambios para incluirlos en GAME_OVER. Las partidas sin puntuar devuelven nil.
+// El Hub calcula en memoria y escribe en disco fuera del bucle de la sala
+func (r *Room) rateGame(record models.GameRecord) map[string]models.RatingChange {
+	if !r.Settings.Rated {
+		return nil
+	}
+
+	rater, ok := r.Hub.(interface {
+		RateGame(record models.GameRecord) map[string]models.RatingChange
+	})
+	if !ok {
+		return nil
+	}
+	return rater.RateGame(record)
+}
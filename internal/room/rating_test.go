@@ -0,0 +1,90 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// ratingHub es un Hub mínimo que anota las partidas puntuadas y devuelve cambios fijos
+type ratingHub struct {
+	archivingHub
+	rated chan models.GameRecord
+}
+
+func (h *ratingHub) RateGame(record models.GameRecord) map[string]models.RatingChange {
+	h.rated <- record
+	return map[string]models.RatingChange{
+		record.Winner: {Before: 1500, After: 1662, Delta: 162},
+	}
+}
+
+// TestRoomRatedAbandonment verifica que un abandono en partida puntuada cuente como derrota
+// y que el cambio de puntuación llegue en GAME_OVER
+func TestRoomRatedAbandonment(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := &ratingHub{
+		archivingHub: archivingHub{archived: make(chan models.GameRecord, 1)},
+		rated:        make(chan models.GameRecord, 1),
+	}
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.Rated = true
+	settings.DisconnectGrace = 0
+	r := NewRoomWithSettings("rated-room", settings, hub, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	r.Unregister <- p1
+	gameOver := waitForMessage(t, p2, "GAME_OVER")
+
+	record := <-hub.rated
+	if len(record.Players) != 2 || record.Players["p1"] == "" || record.Winner != "p2" || record.Reason != "abandonment" {
+		t.Errorf("Partida puntuada incorrecta: %+v", record)
+	}
+
+	changes, ok := gameOver["ratingChanges"].(map[string]interface{})
+	if !ok || changes["p2"] == nil {
+		t.Fatalf("GAME_OVER sin cambios de puntuación: %v", gameOver)
+	}
+	if archived := <-hub.archived; archived.RatingChanges["p2"].Delta != 162 {
+		t.Errorf("El historial archivado debería incluir los cambios: %+v", archived.RatingChanges)
+	}
+}
+
+// TestRoomCasualGameNotRated verifica que las partidas sin puntuar no cambien puntuaciones
+func TestRoomCasualGameNotRated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := &ratingHub{
+		archivingHub: archivingHub{archived: make(chan models.GameRecord, 1)},
+		rated:        make(chan models.GameRecord, 1),
+	}
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.DisconnectGrace = 0
+	r := NewRoomWithSettings("casual-room", settings, hub, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	r.Unregister <- p1
+	gameOver := waitForMessage(t, p2, "GAME_OVER")
+	if _, rated := gameOver["ratingChanges"]; rated {
+		t.Errorf("Una partida sin puntuar no debería incluir cambios: %v", gameOver)
+	}
+	select {
+	case record := <-hub.rated:
+		t.Errorf("No se debería puntuar la partida: %+v", record)
+	default:
+	}
+}
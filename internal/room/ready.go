@@ -0,0 +1,175 @@
+package room
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/errors"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// startReadyCheck abre la fase de confirmación: ambos jugadores reciben READY_CHECK
+// y la partida empieza cuando todos envían PLAYER_READY
+func (r *Room) startReadyCheck() {
+	r.readyCheckActive = true
+	r.readyPlayers = make(map[string]bool)
+	r.transition(StateReadyCheck, "ready_check")
+
+	timeout := time.Duration(r.Settings.ReadyTimeout) * time.Second
+	r.readyDeadline = time.Now().Add(timeout)
+	r.startTimer(timerReady, timeout)
+
+	for client := range r.Clients {
+		r.sendReadyCheck(client)
+	}
+	for spectator := range r.Spectators {
+		r.sendReadyCheck(spectator)
+	}
+
+	logger.Info("Ready-check iniciado", logger.Fields{
+		"roomID":  r.ID,
+		"timeout": r.Settings.ReadyTimeout,
+	})
+}
+
+// sendReadyCheck envía el estado actual del ready-check a un cliente
+func (r *Room) sendReadyCheck(client interfaces.Client) {
+	readyMsg := models.ReadyCheckResponse{
+		Type:           "READY_CHECK",
+		Players:        r.sortedPlayerIDs(),
+		Ready:          r.readyList(),
+		TimeoutSeconds: r.Settings.ReadyTimeout,
+		Deadline:       r.readyDeadline.UnixMilli(),
+		Profiles:       r.seatedProfiles(),
+	}
+	msgBytes, _ := json.Marshal(readyMsg)
+	r.sendToClient(client, msgBytes, "READY_CHECK")
+}
+
+// handlePlayerReady registra la confirmación de un jugador
+func (r *Room) handlePlayerReady(cmd *Command) {
+	if !r.readyCheckActive {
+		errors.NoReadyCheck(cmd.Client.GetSendChannel(), cmd.Client.GetID())
+		return
+	}
+
+	if _, isPlayer := r.GameState.PlayerSymbols[cmd.Client.GetID()]; !isPlayer {
+		errors.NotInGame(cmd.Client.GetSendChannel(), cmd.Client.GetID())
+		return
+	}
+
+	r.readyPlayers[cmd.Client.GetID()] = true
+
+	updateMsg := models.ReadyUpdateResponse{
+		Type:     "PLAYER_READY",
+		PlayerID: cmd.Client.GetID(),
+		Ready:    r.readyList(),
+	}
+	msgBytes, _ := json.Marshal(updateMsg)
+	r.broadcastToAll(msgBytes, "PLAYER_READY")
+
+	// Empezar cuando todos los jugadores sentados hayan confirmado
+	for playerID := range r.GameState.PlayerSymbols {
+		if !r.readyPlayers[playerID] {
+			return
+		}
+	}
+
+	r.endReadyCheck()
+	r.beginGame()
+}
+
+// handleReadyTimeout retira de la sala a los jugadores que no confirmaron a tiempo
+func (r *Room) handleReadyTimeout() {
+	if !r.readyCheckActive {
+		return
+	}
+
+	var removed []string
+	for client := range r.Clients {
+		if r.readyPlayers[client.GetID()] {
+			continue
+		}
+
+		removed = append(removed, client.GetID())
+		delete(r.Clients, client)
+		r.unseatPlayer(client.GetID(), "not_ready")
+		client.SetRoom(nil)
+
+		removedMsg := models.RemovedFromRoomResponse{
+			Type:   "REMOVED_FROM_ROOM",
+			RoomID: r.ID,
+			Reason: "not_ready",
+		}
+		msgBytes, _ := json.Marshal(removedMsg)
+		r.sendToClient(client, msgBytes, "REMOVED_FROM_ROOM")
+	}
+	sort.Strings(removed)
+
+	r.endReadyCheck()
+	r.transition(StateWaiting, "ready_timeout")
+
+	cancelMsg := models.ReadyCheckCancelledResponse{
+		Type:    "READY_CHECK_CANCELLED",
+		Reason:  "timeout",
+		Removed: removed,
+	}
+	msgBytes, _ := json.Marshal(cancelMsg)
+	r.broadcastToAll(msgBytes, "READY_CHECK_CANCELLED")
+
+	logger.Info("Ready-check vencido, jugadores retirados", logger.Fields{
+		"roomID":  r.ID,
+		"removed": removed,
+	})
+
+	for _, playerID := range removed {
+		r.migrateHost(playerID)
+	}
+}
+
+// cancelReadyCheck cancela el ready-check cuando un jugador abandona o es expulsado
+func (r *Room) cancelReadyCheck() {
+	if !r.readyCheckActive {
+		return
+	}
+
+	r.endReadyCheck()
+	r.transition(StateWaiting, "player_left")
+
+	cancelMsg := models.ReadyCheckCancelledResponse{
+		Type:   "READY_CHECK_CANCELLED",
+		Reason: "player_left",
+	}
+	msgBytes, _ := json.Marshal(cancelMsg)
+	r.broadcastToAll(msgBytes, "READY_CHECK_CANCELLED")
+}
+
+// endReadyCheck cierra la fase de confirmación y cancela su temporizador
+func (r *Room) endReadyCheck() {
+	r.readyCheckActive = false
+	r.readyPlayers = nil
+	r.stopTimer(timerReady)
+}
+
+// readyList devuelve los IDs que ya confirmaron, ordenados
+func (r *Room) readyList() []string {
+	ready := make([]string, 0, len(r.readyPlayers))
+	for playerID := range r.readyPlayers {
+		ready = append(ready, playerID)
+	}
+	sort.Strings(ready)
+	return ready
+}
+
+// sortedPlayerIDs devuelve los IDs de los jugadores sentados, ordenados
+func (r *Room) sortedPlayerIDs() []string {
+	players := make([]string, 0, len(r.GameState.PlayerSymbols))
+	for playerID := range r.GameState.PlayerSymbols {
+		players = append(players, playerID)
+	}
+	sort.Strings(players)
+	return players
+}
@@ -0,0 +1,79 @@
+package room
+
+import (
+	"time"
+)
+
+// Motivos por los que una sala se puede eliminar
+const (
+	ReapEmpty    = "empty"    // Sin jugadores, espectadores ni desconectados pendientes
+	ReapWaiting  = "waiting"  // Esperando rival demasiado tiempo
+	ReapFinished = "finished" // Partida terminada sin revancha
+)
+
+// ReapPolicy indica cuánto tiempo puede seguir viva una sala en cada situación.
+// Un valor 0 desactiva ese límite
+type ReapPolicy struct {
+	EmptyTTL    time.Duration
+	WaitingTTL  time.Duration
+	FinishedTTL time.Duration
+}
+
+// reapQuery es una consulta del Hub al bucle de la sala para saber si debe eliminarse
+type reapQuery struct {
+	policy ReapPolicy
+	now    time.Time
+	reply  chan string
+}
+
+// Reapable pregunta al bucle de la sala si ha superado alguno de los límites de la política.
+// Devuelve el motivo, o "" si la sala debe seguir. Una sala ya cerrada siempre se puede eliminar
+func (r *Room) Reapable(policy ReapPolicy, now time.Time) string {
+	query := reapQuery{policy: policy, now: now, reply: make(chan string, 1)}
+
+	select {
+	case r.reapReq <- query:
+	case <-r.ctx.Done():
+		return ReapEmpty
+	}
+
+	select {
+	case reason := <-query.reply:
+		return reason
+	case <-r.ctx.Done():
+		return ReapEmpty
+	}
+}
+
+// reapReason decide si la sala ha superado algún límite. Solo se llama desde el bucle de la sala
+func (r *Room) reapReason(policy ReapPolicy, now time.Time) string {
+	if !r.emptySince.IsZero() && policy.EmptyTTL > 0 && now.Sub(r.emptySince) >= policy.EmptyTTL {
+		return ReapEmpty
+	}
+
+	expired := func(ttl time.Duration) bool {
+		return ttl > 0 && now.Sub(r.stateSince) >= ttl
+	}
+
+	switch {
+	case r.state == StateWaiting && expired(policy.WaitingTTL):
+		return ReapWaiting
+	case r.state == StateFinished && expired(policy.FinishedTTL):
+		return ReapFinished
+	}
+
+	return ""
+}
+
+// trackEmpty anota desde cuándo está vacía la sala. Los jugadores desconectados durante
+// su tiempo de gracia cuentan como presentes
+func (r *Room) trackEmpty() {
+	empty := len(r.Clients) == 0 && len(r.Spectators) == 0 && len(r.disconnected) == 0
+
+	switch {
+	case !empty:
+		r.emptySince = time.Time{}
+	case r.emptySince.IsZero():
+		r.emptySince = time.Now()
+	}
+}
@@ -0,0 +1,49 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRoomReapable verifica que la sala decida en su bucle cuándo ha superado sus límites
+func TestRoomReapable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRoom("reap-room", nil, ctx)
+	go r.Run()
+
+	policy := ReapPolicy{EmptyTTL: time.Minute, WaitingTTL: 10 * time.Minute, FinishedTTL: 2 * time.Minute}
+	now := time.Now()
+
+	// Una sala recién creada y vacía solo se elimina pasado su límite
+	if reason := r.Reapable(policy, now); reason != "" {
+		t.Errorf("La sala no debería eliminarse todavía, motivo '%s'", reason)
+	}
+	if reason := r.Reapable(policy, now.Add(2*time.Minute)); reason != ReapEmpty {
+		t.Errorf("Se esperaba '%s', obtenido '%s'", ReapEmpty, reason)
+	}
+
+	// Con un jugador esperando rival ya no está vacía
+	p1 := newFakeClient("p1")
+	r.Register <- p1
+	waitForMessage(t, p1, "WAITING_FOR_OPPONENT")
+	if reason := r.Reapable(policy, now.Add(2*time.Minute)); reason != "" {
+		t.Errorf("Una sala con jugador no debería estar vacía, motivo '%s'", reason)
+	}
+	if reason := r.Reapable(policy, now.Add(11*time.Minute)); reason != ReapWaiting {
+		t.Errorf("Se esperaba '%s', obtenido '%s'", ReapWaiting, reason)
+	}
+
+	// Sin límite de espera, la sala sigue viva
+	if reason := r.Reapable(ReapPolicy{EmptyTTL: time.Minute}, now.Add(time.Hour)); reason != "" {
+		t.Errorf("Un límite 0 no debería eliminar la sala, motivo '%s'", reason)
+	}
+
+	// Una sala cerrada siempre se puede eliminar
+	cancel()
+	if reason := r.Reapable(policy, now); reason == "" {
+		t.Error("Una sala cerrada debería poder eliminarse")
+	}
+}
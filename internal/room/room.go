@@ -3,50 +3,178 @@ package room
 import (
 	"context"
 	"encoding/json"
+	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"nvivas/backend/tictactoe-go-server/internal/errors"
 	"nvivas/backend/tictactoe-go-server/internal/game"
 	"nvivas/backend/tictactoe-go-server/internal/interfaces"
 	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
 	"nvivas/backend/tictactoe-go-server/pkg/models"
 )
 
 // Room representa una sala de juego
 type Room struct {
-	ID          string                     // Identificador único de la sala
-	Hub         interfaces.Hub             // Referencia al Hub principal
-	Clients     map[interfaces.Client]bool // Clientes en la sala (máximo 2)
-	GameState   *game.GameState            // Estado actual del juego
-	Register    chan interfaces.Client     // Canal para registrar clientes
-	Unregister  chan interfaces.Client     // Canal para desregistrar clientes
-	Broadcast   chan []byte                // Canal para mensajes a todos los clientes
-	ReceiveMove chan *models.PlayerMove    // Canal para recibir movimientos
+	ID                string                     // Identificador único de la sala
+	Code              string                     // Código corto legible para compartir la sala
+	Settings          models.RoomSettings        // Configuración efectiva de la sala (inmutable)
+	Hub               interfaces.Hub             // Referencia al Hub principal
+	Clients           map[interfaces.Client]bool // Clientes en la sala (máximo 2)
+	Spectators        map[interfaces.Client]bool // Espectadores de la sala
+	GameState         *game.GameState            // Estado actual del juego
+	Clock             *game.Clock                // Reloj de la partida, nil si no hay control de tiempo
+	Register          chan interfaces.Client     // Canal para registrar clientes
+	RegisterSpectator chan interfaces.Client     // Canal para registrar espectadores
+	Unregister        chan interfaces.Client     // Canal para desregistrar clientes
+	Broadcast         chan []byte                // Canal para mensajes a todos los clientes
+	ReceiveMove       chan *models.PlayerMove    // Canal para recibir movimientos
+	Commands          chan *Command              // Canal para acciones de sala enviadas por los clientes
+	HostID            string                     // ID del anfitrión de la sala
+
+	// Símbolo asignado al primer jugador según la configuración
+	creatorSymbol string
+
+	// Control de acceso gestionado por el anfitrión
+	locked bool            // Si está bloqueada no se admiten nuevos jugadores ni espectadores
+	banned map[string]bool // IDs expulsados que no pueden volver a entrar
+
+	// Ciclo de vida de la sala
+	state      State                    // Estado actual, solo se cambia con transition
+	gameID     string                   // ID de la partida en curso o de la última jugada
+	moveNumber int                      // Jugadas de la partida en curso
+	lastResult *models.GameOverResponse // Resultado de la última partida terminada
+
+	// Resumen publicado para el Hub, protegido por infoMu
+	infoMu sync.RWMutex
+	info   models.RoomInfo
+
+	// Fase de inicio de partida
+	readyCheckActive bool            // Se está esperando la confirmación de los jugadores
+	readyPlayers     map[string]bool // Jugadores que ya confirmaron
+	readyDeadline    time.Time       // Momento en que vence el ready-check
+	coinFlip         *coinFlipState  // Sorteo commit-reveal en curso, nil si no hay
+
+	// Jugadores desconectados durante la partida y el plazo que tienen para volver
+	disconnected map[string]time.Time
+
+	// Pausas de mutuo acuerdo
+	pause            *mutualPause // Pausa en curso, nil si no hay
+	pauseRequestedBy string       // Jugador con una solicitud de pausa pendiente
+	pausesUsed       int          // Pausas usadas en la partida actual
+
+	// Registro de todos los cambios de la sala; el estado se puede reconstruir a partir de él
+	log *roomlog.Log
+
+	// Copias de estado solicitadas desde fuera del bucle (persistencia)
+	snapshotReq chan chan Snapshot
+
+	// Versión de la sala: avanza con cada mensaje enviado a todos sus miembros
+	version uint64
+
+	// Consultas del Hub para eliminar salas inactivas
+	reapReq    chan reapQuery
+	createdAt  time.Time // Momento en que se creó la sala
+	stateSince time.Time // Momento en que la sala entró en su estado actual
+	emptySince time.Time // Momento en que la sala quedó vacía, cero si hay alguien
+
+	// Temporizadores de la sala, procesados dentro de Run
+	timers     map[string]*time.Timer
+	timerGens  map[string]uint64
+	timerGen   uint64
+	timerFired chan roomTimer
 
 	// Context para control de cancelación
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-// NewRoom crea una nueva sala de juego
+// Command es una acción dirigida a la sala por un cliente (expulsar, bloquear, etc.)
+type Command struct {
+	Client  interfaces.Client
+	Type    string
+	Payload json.RawMessage
+}
+
+// NewRoom crea una nueva sala de juego con la configuración por defecto
 func NewRoom(id string, hub interfaces.Hub, parentCtx context.Context) *Room {
+	settings, _ := ValidateSettings(DefaultSettings())
+	return NewRoomWithSettings(id, settings, hub, parentCtx)
+}
+
+// NewRoomWithSettings crea una nueva sala de juego con una configuración ya validada
+func NewRoomWithSettings(id string, settings models.RoomSettings, hub interfaces.Hub, parentCtx context.Context) *Room {
 	// Crear un contexto derivado que se pueda cancelar independientemente
 	ctx, cancel := context.WithCancel(parentCtx)
 
-	return &Room{
-		ID:          id,
-		Hub:         hub,
-		Clients:     make(map[interfaces.Client]bool),
-		GameState:   game.NewGameState(),
-		Register:    make(chan interfaces.Client),
-		Unregister:  make(chan interfaces.Client),
-		Broadcast:   make(chan []byte),
-		ReceiveMove: make(chan *models.PlayerMove),
-		ctx:         ctx,
-		cancel:      cancel,
+	// Preparar el tablero según la variante
+	gameState := game.NewGameState()
+	if variant, ok := game.LookupVariant(settings.Variant); ok {
+		gameState = game.NewGameStateWithSize(settings.BoardSize, variant.WinLength)
+	}
+
+	// Resolver el símbolo del creador
+	creatorSymbol := settings.PreferredSymbol
+	if creatorSymbol != "X" && creatorSymbol != "O" {
+		creatorSymbol = []string{"X", "O"}[rand.Intn(2)]
+	}
+
+	r := &Room{
+		ID:                id,
+		Settings:          settings,
+		Hub:               hub,
+		Clients:           make(map[interfaces.Client]bool),
+		Spectators:        make(map[interfaces.Client]bool),
+		GameState:         gameState,
+		Register:          make(chan interfaces.Client),
+		RegisterSpectator: make(chan interfaces.Client),
+		Unregister:        make(chan interfaces.Client),
+		Broadcast:         make(chan []byte),
+		ReceiveMove:       make(chan *models.PlayerMove),
+		Commands:          make(chan *Command),
+		creatorSymbol:     creatorSymbol,
+		state:             StateWaiting,
+		banned:            make(map[string]bool),
+		disconnected:      make(map[string]time.Time),
+		snapshotReq:       make(chan chan Snapshot),
+		reapReq:           make(chan reapQuery),
+		stateSince:        time.Now(),
+		timers:            make(map[string]*time.Timer),
+		timerGens:         make(map[string]uint64),
+		timerFired:        make(chan roomTimer),
+		log:               roomlog.NewLog(nil),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+
+	created := r.log.Append(roomlog.Event{
+		Type:      roomlog.EventRoomCreated,
+		BoardSize: gameState.Board.Size(),
+		WinLength: gameState.WinLength,
+	})
+	r.createdAt = created.At
+
+	return r
+}
+
+// Submit entrega una acción al bucle de la sala. Devuelve false si la sala ya se cerró
+func (r *Room) Submit(cmd *Command) bool {
+	select {
+	case r.Commands <- cmd:
+		return true
+	case <-r.ctx.Done():
+		return false
 	}
 }
 
+// CreatorSymbol devuelve el símbolo que recibe el primer jugador de la sala
+func (r *Room) CreatorSymbol() string {
+	return r.creatorSymbol
+}
+
 // Close cancela el contexto y libera recursos
 func (r *Room) Close() {
 	r.cancel()
@@ -63,32 +191,40 @@ func (r *Room) Run() {
 			"roomID": r.ID,
 		})
 
-		// Informar a los clientes que la sala se ha cerrado
+		// Detener los temporizadores pendientes
+		r.stopAllTimers()
+
+		// Estado final de la sala
+		r.transition(StateClosed, "room_closed")
+
+		// Enviar mensaje de sala cerrada
+		closeMsg := models.BaseMessage{Type: "ROOM_CLOSED"}
+		msgBytes, _ := json.Marshal(closeMsg)
+
+		// Informar a los clientes y espectadores que la sala se ha cerrado
+		r.broadcastToAll(msgBytes, "ROOM_CLOSED")
 		for client := range r.Clients {
-			// Desasociar el cliente de la sala
-			client.SetRoom(nil)
-
-			// Enviar mensaje de sala cerrada
-			closeMsg := models.BaseMessage{Type: "ROOM_CLOSED"}
-			msgBytes, _ := json.Marshal(closeMsg)
-
-			// Add safety check to prevent sending to closed channels
-			select {
-			case client.GetSendChannel() <- msgBytes:
-				// Mensaje enviado con éxito
-			default:
-				// Skip if channel is closed or full
-				logger.Warn("No se pudo enviar mensaje, canal posiblemente cerrado", logger.Fields{
-					"clientID": client.GetID(),
-					"roomID":   r.ID,
-				})
+			// Desasociar el cliente de la sala, salvo que el Hub ya lo haya llevado a otra
+			if client.GetRoom() == r {
+				client.SetRoom(nil)
+			}
+		}
+		for spectator := range r.Spectators {
+			if spectator.GetRoom() == r {
+				spectator.SetRoom(nil)
 			}
 		}
 
-		// Limpiar el mapa de clientes
+		// Limpiar los mapas de clientes
 		r.Clients = make(map[interfaces.Client]bool)
+		r.Spectators = make(map[interfaces.Client]bool)
+		r.refreshInfo()
+		r.publishRemoved()
 	}()
 
+	r.refreshInfo()
+	r.trackEmpty()
+
 	for {
 		select {
 		case <-r.ctx.Done():
@@ -99,535 +235,796 @@ func (r *Room) Run() {
 			return
 
 		case client := <-r.Register:
-			// Check if client is reconnecting
-			isReconnecting := false
-			var reconnectSymbol string
-
-			// Check if this client ID already exists in PlayerSymbols
-			// but is not currently in the Clients map
-			if symbol, exists := r.GameState.PlayerSymbols[client.GetID()]; exists {
-				isReconnecting = true
-				reconnectSymbol = symbol
-				logger.Info("Cliente reconectándose a su juego", logger.Fields{
-					"roomID":   r.ID,
-					"clientID": client.GetID(),
-					"symbol":   symbol,
-				})
-			}
+			r.handleRegister(client)
 
-			// Añadir cliente a r.Clients
-			r.Clients[client] = true
-
-			// Determine if we should treat this as a reconnection
-			if isReconnecting {
-				// Restore the player's symbol if reconnecting
-				r.GameState.PlayerSymbols[client.GetID()] = reconnectSymbol
-
-				// Convert board to JSON string for GameState
-				boardData := getBoardJSON(r.GameState.Board)
-				boardString, _ := json.Marshal(boardData)
-
-				// First send appropriate room joined message
-				roomJoinedMsg := models.RoomJoinedResponse{
-					Type:      "ROOM_JOINED",
-					RoomID:    r.ID,
-					PlayerID:  client.GetID(),
-					Symbol:    reconnectSymbol,
-					GameState: string(boardString),
-				}
-				joinedBytes, _ := json.Marshal(roomJoinedMsg)
-
-				select {
-				case client.GetSendChannel() <- joinedBytes:
-					logger.Info("Información de sala enviada a cliente reconectado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-						"symbol":   reconnectSymbol,
-					})
-				default:
-					logger.Warn("No se pudo enviar ROOM_JOINED, canal posiblemente cerrado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-					})
-				}
-
-				// Send current game state to the reconnected player
-				boardJSON := getBoardJSON(r.GameState.Board)
-
-				// Check if game is already in progress
-				if len(r.GameState.PlayerSymbols) == 2 {
-					// First send a more comprehensive GAME_START message with all player data
-					gameStartMsg := models.GameStartResponse{
-						Type:        "GAME_START",
-						Board:       boardJSON,
-						CurrentTurn: r.GameState.CurrentTurnSymbol,
-						Players:     r.GameState.PlayerSymbols,
-					}
-					startBytes, _ := json.Marshal(gameStartMsg)
-
-					select {
-					case client.GetSendChannel() <- startBytes:
-						logger.Info("Estado inicial enviado a cliente reconectado", logger.Fields{
-							"clientID": client.GetID(),
-							"roomID":   r.ID,
-							"symbol":   reconnectSymbol,
-						})
-					default:
-						logger.Warn("No se pudo enviar GAME_START, canal posiblemente cerrado", logger.Fields{
-							"clientID": client.GetID(),
-							"roomID":   r.ID,
-						})
-					}
-
-					// Then send the current game update with last move if available
-					updateMsg := models.GameUpdateResponse{
-						Type:        "GAME_UPDATE",
-						Board:       boardJSON,
-						CurrentTurn: r.GameState.CurrentTurnSymbol,
-					}
-					updateBytes, _ := json.Marshal(updateMsg)
-
-					select {
-					case client.GetSendChannel() <- updateBytes:
-						// Message sent successfully
-						logger.Info("Estado del juego enviado a cliente reconectado", logger.Fields{
-							"clientID": client.GetID(),
-							"roomID":   r.ID,
-						})
-					default:
-						logger.Warn("No se pudo enviar GAME_UPDATE, canal posiblemente cerrado", logger.Fields{
-							"clientID": client.GetID(),
-							"roomID":   r.ID,
-						})
-					}
-
-					// Also notify other players about reconnection
-					for c := range r.Clients {
-						if c.GetID() != client.GetID() {
-							reconnectMsg := models.PlayerReconnectedResponse{
-								Type:     "PLAYER_RECONNECTED",
-								PlayerID: client.GetID(),
-							}
-							msgBytes, _ := json.Marshal(reconnectMsg)
-
-							select {
-							case c.GetSendChannel() <- msgBytes:
-								// Message sent successfully
-							default:
-								logger.Warn("No se pudo enviar notificación de reconexión, canal posiblemente cerrado", logger.Fields{
-									"clientID": c.GetID(),
-									"roomID":   r.ID,
-								})
-							}
-						}
-					}
-
-					continue // Skip the normal flow for new connections
-				}
-			}
+		case spectator := <-r.RegisterSpectator:
+			r.handleSpectatorJoin(spectator)
 
-			// If not reconnecting or if reconnecting to a waiting room,
-			// continue with the normal connection flow
+		case client := <-r.Unregister:
+			r.handleUnregister(client)
 
-			// Determinar cuántos jugadores hay en la sala
-			playerCount := len(r.Clients)
+		case cmd := <-r.Commands:
+			r.handleCommand(cmd)
 
-			// Si hay más de 2 jugadores, rechazar
-			if playerCount > 2 {
-				errors.RoomFull(client.GetSendChannel(), client.GetID())
+		case message := <-r.Broadcast:
+			// Enviar el mensaje a jugadores y espectadores
+			r.broadcastToAll(message, "broadcast")
 
-				// Eliminar el cliente
-				delete(r.Clients, client)
-				client.SetRoom(nil)
-				continue
-			}
+		case ev := <-r.timerFired:
+			r.handleTimer(ev)
 
-			// Mejorada lógica de asignación de símbolos
-			// Verificar si ya hay símbolos asignados (por si acaso)
-			var symbol string
-
-			// Si es el primer jugador o no hay símbolos asignados todavía
-			if playerCount == 1 || len(r.GameState.PlayerSymbols) == 0 {
-				symbol = "X" // Primer jugador siempre es X
-
-				// Reiniciar símbolos por si hay una reconexión
-				r.GameState.PlayerSymbols = make(map[string]string)
-				r.GameState.PlayerSymbols[client.GetID()] = symbol
-
-				// Enviar mensaje de espera con información de la sala
-				roomInfo := models.RoomCreatedResponse{
-					Type:     "WAITING_FOR_OPPONENT",
-					RoomID:   r.ID,
-					PlayerID: client.GetID(),
-					Symbol:   symbol,
-				}
-				msgBytes, _ := json.Marshal(roomInfo)
-
-				select {
-				case client.GetSendChannel() <- msgBytes:
-					// Mensaje enviado con éxito
-				default:
-					logger.Warn("No se pudo enviar WAITING_FOR_OPPONENT, canal posiblemente cerrado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-					})
-				}
-
-				logger.Info("Jugador esperando oponente", logger.Fields{
-					"roomID":   r.ID,
-					"clientID": client.GetID(),
-					"symbol":   symbol,
-				})
-			} else if playerCount == 2 {
-				// Para el segundo jugador, asignar el símbolo contrario al del primer jugador
-				var firstPlayerSymbol string
-				var firstPlayer interfaces.Client
-
-				// Obtener el símbolo del primer jugador
-				for c := range r.Clients {
-					if c.GetID() != client.GetID() {
-						firstPlayer = c
-						firstPlayerSymbol = r.GameState.PlayerSymbols[c.GetID()]
-						break
-					}
-				}
-
-				// Asignar símbolo opuesto al segundo jugador
-				if firstPlayerSymbol == "X" {
-					symbol = "O"
-				} else {
-					symbol = "X"
-				}
-
-				// Guardar símbolo del segundo jugador
-				r.GameState.PlayerSymbols[client.GetID()] = symbol
-
-				// Establecer turno actual (siempre empieza X)
-				r.GameState.CurrentTurnSymbol = "X"
-
-				// Notificar al primer jugador que se unió un oponente
-				playerJoinedMsg := models.PlayerJoinedResponse{
-					Type:     "PLAYER_JOINED",
-					PlayerID: client.GetID(),
-				}
-				joinedBytes, _ := json.Marshal(playerJoinedMsg)
-
-				select {
-				case firstPlayer.GetSendChannel() <- joinedBytes:
-					// Mensaje enviado con éxito
-				default:
-					logger.Warn("No se pudo enviar PLAYER_JOINED, canal posiblemente cerrado", logger.Fields{
-						"clientID": firstPlayer.GetID(),
-						"roomID":   r.ID,
-					})
-				}
-
-				// Informar al segundo jugador que se unió a la sala
-				roomJoinedMsg := models.RoomJoinedResponse{
-					Type:     "ROOM_JOINED",
-					RoomID:   r.ID,
-					PlayerID: client.GetID(),
-					Symbol:   symbol,
-				}
-				joinedMsgBytes, _ := json.Marshal(roomJoinedMsg)
-
-				select {
-				case client.GetSendChannel() <- joinedMsgBytes:
-					// Mensaje enviado con éxito
-				default:
-					logger.Warn("No se pudo enviar ROOM_JOINED, canal posiblemente cerrado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-					})
-				}
-
-				// Convertir el tablero a formato JSON para el mensaje
-				boardJSON := getBoardJSON(r.GameState.Board)
-
-				// Mensaje mejorado de inicio de juego con estado completo
-				gameStartMsg := models.GameStartResponse{
-					Type:        "GAME_START",
-					Board:       boardJSON,
-					CurrentTurn: r.GameState.CurrentTurnSymbol,
-					Players:     r.GameState.PlayerSymbols,
-				}
-				startBytes, _ := json.Marshal(gameStartMsg)
-
-				// Enviar mensaje GAME_START a ambos jugadores
-				for c := range r.Clients {
-					select {
-					case c.GetSendChannel() <- startBytes:
-						// Mensaje enviado con éxito
-					default:
-						logger.Warn("No se pudo enviar GAME_START, canal posiblemente cerrado", logger.Fields{
-							"clientID": c.GetID(),
-							"roomID":   r.ID,
-						})
-					}
-				}
-
-				logger.Info("Juego iniciado", logger.Fields{
-					"roomID":        r.ID,
-					"player1ID":     firstPlayer.GetID(),
-					"player1Symbol": r.GameState.PlayerSymbols[firstPlayer.GetID()],
-					"player2ID":     client.GetID(),
-					"player2Symbol": symbol,
-				})
-			}
+		case moveReq := <-r.ReceiveMove:
+			r.handleMove(moveReq)
 
-		case client := <-r.Unregister:
-			if _, ok := r.Clients[client]; ok {
-				// Obtener el símbolo del jugador que se va
-				symbol, exists := r.GameState.PlayerSymbols[client.GetID()]
+		case reply := <-r.snapshotReq:
+			reply <- r.snapshot()
 
-				// Eliminar cliente de r.Clients
-				delete(r.Clients, client)
+		case query := <-r.reapReq:
+			query.reply <- r.reapReason(query.policy, query.now)
+		}
 
-				// Eliminar símbolo del jugador
-				if exists {
-					delete(r.GameState.PlayerSymbols, client.GetID())
-				}
+		// Publicar el resumen actualizado para el Hub
+		r.refreshInfo()
+		r.trackEmpty()
+	}
+}
 
-				// Actualizar client.Room = nil
-				client.SetRoom(nil)
+// handleRegister sienta a un jugador en la sala, ya sea nuevo o reconectándose
+func (r *Room) handleRegister(client interfaces.Client) {
+	// Check if this client ID already exists in PlayerSymbols
+	// but is not currently in the Clients map
+	if symbol, exists := r.GameState.PlayerSymbols[client.GetID()]; exists {
+		logger.Info("Cliente reconectándose a su juego", logger.Fields{
+			"roomID":   r.ID,
+			"clientID": client.GetID(),
+			"symbol":   symbol,
+		})
 
-				// Notificar al otro jugador (si existe) con PLAYER_LEFT
-				if len(r.Clients) > 0 {
-					playerLeftMsg := models.PlayerLeftResponse{
-						Type:     "PLAYER_LEFT",
-						PlayerID: client.GetID(),
-					}
-					msgBytes, _ := json.Marshal(playerLeftMsg)
-
-					for c := range r.Clients {
-						select {
-						case c.GetSendChannel() <- msgBytes:
-							// Mensaje enviado con éxito
-						default:
-							logger.Warn("No se pudo enviar PLAYER_LEFT, canal posiblemente cerrado", logger.Fields{
-								"clientID": c.GetID(),
-								"roomID":   r.ID,
-							})
-						}
-
-						// También enviar un mensaje GAME_OVER ya que no se puede continuar
-						// si un jugador abandona
-						gameOverMsg := models.GameOverResponse{
-							Type:   "GAME_OVER",
-							Board:  getBoardJSON(r.GameState.Board),
-							Winner: c.GetID(), // El jugador que queda gana por abandono
-							IsDraw: false,
-						}
-						overBytes, _ := json.Marshal(gameOverMsg)
-
-						select {
-						case c.GetSendChannel() <- overBytes:
-							// Mensaje enviado con éxito
-						default:
-							logger.Warn("No se pudo enviar GAME_OVER por abandono, canal posiblemente cerrado", logger.Fields{
-								"clientID": c.GetID(),
-								"roomID":   r.ID,
-							})
-						}
-					}
-
-					logger.Info("Jugador abandonó la sala", logger.Fields{
-						"roomID":   r.ID,
-						"clientID": client.GetID(),
-						"symbol":   symbol,
-					})
-				}
-
-				// Si la sala queda vacía, programar auto-destrucción con un temporizador
-				// para permitir reconexiones durante navegación de páginas
-				if len(r.Clients) == 0 {
-					logger.Info("Sala vacía, programando eliminación con retraso", logger.Fields{"roomID": r.ID})
-
-					// Usar una goroutine con temporizador para eliminar la sala después de un tiempo
-					go func(roomID string) {
-						// Esperar 30 segundos antes de verificar si aún está vacía
-						time.Sleep(30 * time.Second)
-
-						// Verificar si la sala aún existe y está vacía
-						if len(r.Clients) == 0 {
-							logger.Info("Sala sigue vacía después del tiempo de gracia, eliminando", logger.Fields{"roomID": roomID})
-
-							// Verificar si el Hub tiene método para eliminar salas
-							hubWithDelete, ok := r.Hub.(interface {
-								DeleteRoom(roomID string)
-							})
-
-							if ok {
-								// Informar al Hub que elimine esta sala
-								hubWithDelete.DeleteRoom(roomID)
-							}
-						} else {
-							logger.Info("Sala ya no está vacía, cancelando eliminación", logger.Fields{"roomID": roomID})
-						}
-					}(r.ID)
-				}
+		// Si el jugador seguía con otra conexión abierta (por ejemplo, tras cambiar
+		// de red), la nueva conexión la sustituye
+		for c := range r.Clients {
+			if c != client && c.GetID() == client.GetID() {
+				delete(r.Clients, c)
+				c.SetRoom(nil)
 			}
+		}
 
-		case message := <-r.Broadcast:
-			// Iterar sobre r.Clients y enviar el mensaje a client.Send
-			for client := range r.Clients {
-				select {
-				case client.GetSendChannel() <- message:
-					// Mensaje enviado con éxito
-				default:
-					// Error al enviar, cliente probablemente desconectado
-					logger.Warn("No se pudo enviar mensaje broadcast, canal posiblemente cerrado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-					})
-					// Ya no cerramos el canal aquí, lo dejamos para el Hub
-				}
+		r.Clients[client] = true
+		wasDisconnected := r.endGracePeriod(client.GetID())
+		if r.handleReconnect(client, symbol) {
+			if wasDisconnected {
+				r.resumeGame("player_reconnected")
 			}
+			return
+		}
+	} else {
+		// Los nuevos jugadores deben pasar los controles del anfitrión
+		if !r.admit(client) {
+			return
+		}
+		r.Clients[client] = true
+	}
 
-		case moveReq := <-r.ReceiveMove:
-			// Obtener client y moveData del PlayerMove
-			moveClient, ok := moveReq.Client.(interfaces.Client)
-			if !ok {
-				logger.Error("Cliente en ReceiveMove no es del tipo correcto", nil)
-				continue
-			}
+	// If not reconnecting or if reconnecting to a waiting room,
+	// continue with the normal connection flow
 
-			moveData := moveReq.MoveData
+	// Determinar cuántos jugadores hay en la sala
+	playerCount := len(r.Clients)
 
-			// Obtener el símbolo del cliente
-			playerSymbol, ok := r.GameState.PlayerSymbols[moveClient.GetID()]
-			if !ok {
-				// Cliente no registrado en el juego
-				errors.NotInGame(moveClient.GetSendChannel(), moveClient.GetID())
-				continue
-			}
+	// Si hay más de 2 jugadores, rechazar
+	if playerCount > 2 {
+		errors.RoomFull(client.GetSendChannel(), client.GetID())
 
-			// Validar si es el turno del cliente
-			if r.GameState.CurrentTurnSymbol != playerSymbol {
-				// No es el turno de este jugador
-				errors.NotYourTurn(moveClient.GetSendChannel(), moveClient.GetID())
-				continue
-			}
+		// Eliminar el cliente
+		delete(r.Clients, client)
+		client.SetRoom(nil)
+		return
+	}
 
-			// Aplicar el movimiento
-			err := game.ApplyMove(r.GameState, playerSymbol, moveData.Row, moveData.Col)
-			if err != nil {
-				// Movimiento inválido
-				errors.InvalidMove(moveClient.GetSendChannel(), err.Error(), moveClient.GetID())
-				continue
-			}
+	// Mejorada lógica de asignación de símbolos
+	// Verificar si ya hay símbolos asignados (por si acaso)
+	var symbol string
 
-			// Obtener el tablero en formato JSON
-			boardJSON := getBoardJSON(r.GameState.Board)
+	// Si es el primer jugador o no hay símbolos asignados todavía
+	if playerCount == 1 || len(r.GameState.PlayerSymbols) == 0 {
+		symbol = r.creatorSymbol // Símbolo elegido en la configuración de la sala
 
-			// Movimiento válido, informar a todos los clientes
-			updateMsg := models.GameUpdateResponse{
-				Type:        "GAME_UPDATE",
-				Board:       boardJSON,
-				CurrentTurn: r.GameState.CurrentTurnSymbol,
-				LastMove:    moveData,
-			}
-			updateBytes, _ := json.Marshal(updateMsg)
-
-			// Enviar actualización a todos los jugadores
-			for client := range r.Clients {
-				select {
-				case client.GetSendChannel() <- updateBytes:
-					// Mensaje enviado con éxito
-				default:
-					logger.Warn("No se pudo enviar GAME_UPDATE, canal posiblemente cerrado", logger.Fields{
-						"clientID": client.GetID(),
-						"roomID":   r.ID,
-					})
-				}
-			}
+		// El primer jugador de la sala es su anfitrión
+		if r.HostID == "" {
+			r.assignHost(client.GetID(), "room_created")
+		}
 
-			logger.Info("Movimiento realizado", logger.Fields{
-				"roomID":   r.ID,
-				"clientID": moveClient.GetID(),
-				"symbol":   playerSymbol,
-				"row":      moveData.Row,
-				"col":      moveData.Col,
-			})
+		// Reiniciar símbolos por si hay una reconexión
+		for playerID := range r.GameState.PlayerSymbols {
+			r.unseatPlayer(playerID, "seats_reset")
+		}
+		r.seatPlayer(client.GetID(), symbol)
+
+		// Enviar mensaje de espera con información de la sala
+		roomInfo := models.RoomCreatedResponse{
+			Type:     "WAITING_FOR_OPPONENT",
+			RoomID:   r.ID,
+			RoomCode: r.Code,
+			PlayerID: client.GetID(),
+			Symbol:   symbol,
+			Settings: r.Settings,
+		}
+		msgBytes, _ := json.Marshal(roomInfo)
+		r.sendToClient(client, msgBytes, "WAITING_FOR_OPPONENT")
+
+		logger.Info("Jugador esperando oponente", logger.Fields{
+			"roomID":   r.ID,
+			"clientID": client.GetID(),
+			"symbol":   symbol,
+		})
+		return
+	}
+
+	// Para el segundo jugador, asignar el símbolo contrario al del primer jugador
+	var firstPlayerSymbol string
+
+	// Obtener el símbolo del primer jugador
+	for c := range r.Clients {
+		if c.GetID() != client.GetID() {
+			firstPlayerSymbol = r.GameState.PlayerSymbols[c.GetID()]
+			break
+		}
+	}
+
+	// Si la partida anterior terminó (por ejemplo por abandono), empezar con un tablero nuevo
+	if r.state == StateFinished {
+		r.resetGame()
+	}
+
+	// Asignar símbolo opuesto al segundo jugador
+	symbol = game.OppositeSymbol(firstPlayerSymbol)
+
+	// Guardar símbolo del segundo jugador
+	r.seatPlayer(client.GetID(), symbol)
+
+	// Notificar al primer jugador (y a los espectadores) que se unió un oponente
+	playerJoinedMsg := models.PlayerJoinedResponse{
+		Type:     "PLAYER_JOINED",
+		PlayerID: client.GetID(),
+		Profile:  r.playerProfile(client.GetID()),
+	}
+	joinedBytes, _ := json.Marshal(playerJoinedMsg)
+	r.broadcastExcept(client, joinedBytes, "PLAYER_JOINED")
+
+	// Informar al segundo jugador que se unió a la sala
+	roomJoinedMsg := models.RoomJoinedResponse{
+		Type:     "ROOM_JOINED",
+		RoomID:   r.ID,
+		RoomCode: r.Code,
+		PlayerID: client.GetID(),
+		Symbol:   symbol,
+		Settings: r.Settings,
+		HostID:   r.HostID,
+		Profiles: r.seatedProfiles(),
+	}
+	joinedMsgBytes, _ := json.Marshal(roomJoinedMsg)
+	r.sendToClient(client, joinedMsgBytes, "ROOM_JOINED")
+
+	// Con ready-check, la partida empieza cuando todos confirman
+	if r.Settings.ReadyCheck {
+		r.startReadyCheck()
+		return
+	}
+
+	r.beginGame()
+}
+
+// handleReconnect devuelve a un jugador el estado de la sala según su fase.
+// Devuelve false si la sala sigue esperando jugadores y debe seguirse el flujo normal
+func (r *Room) handleReconnect(client interfaces.Client, symbol string) bool {
+	// Convert board to JSON string for GameState
+	boardData := getBoardJSON(r.GameState.Board)
+	boardString, _ := json.Marshal(boardData)
+
+	// First send appropriate room joined message
+	roomJoinedMsg := models.RoomJoinedResponse{
+		Type:      "ROOM_JOINED",
+		RoomID:    r.ID,
+		RoomCode:  r.Code,
+		PlayerID:  client.GetID(),
+		Symbol:    symbol,
+		GameState: string(boardString),
+		Settings:  r.Settings,
+		HostID:    r.HostID,
+		Profiles:  r.seatedProfiles(),
+	}
+	joinedBytes, _ := json.Marshal(roomJoinedMsg)
+	r.sendToClient(client, joinedBytes, "ROOM_JOINED")
+
+	switch r.state {
+	case StateReadyCheck:
+		// Reenviar el ready-check o el sorteo, según la fase en curso
+		if r.coinFlip != nil {
+			r.sendCoinFlipState(client)
+		} else {
+			r.sendReadyCheck(client)
+		}
+		return true
+
+	case StatePlaying, StatePaused:
+		boardJSON := getBoardJSON(r.GameState.Board)
+
+		// First send a more comprehensive GAME_START message with all player data
+		gameStartMsg := models.GameStartResponse{
+			Type:        "GAME_START",
+			GameID:      r.gameID,
+			Board:       boardJSON,
+			CurrentTurn: r.GameState.CurrentTurnSymbol,
+			Players:     r.GameState.PlayerSymbols,
+			Clocks:      r.clockMillis(),
+			Profiles:    r.seatedProfiles(),
+		}
+		startBytes, _ := json.Marshal(gameStartMsg)
+		r.sendToClient(client, startBytes, "GAME_START")
+
+		// Then send the current game update
+		updateMsg := models.GameUpdateResponse{
+			Type:        "GAME_UPDATE",
+			Board:       boardJSON,
+			CurrentTurn: r.GameState.CurrentTurnSymbol,
+			Clocks:      r.clockMillis(),
+		}
+		updateBytes, _ := json.Marshal(updateMsg)
+		r.sendToClient(client, updateBytes, "GAME_UPDATE")
+
+		logger.Info("Estado del juego enviado a cliente reconectado", logger.Fields{
+			"clientID": client.GetID(),
+			"roomID":   r.ID,
+			"symbol":   symbol,
+		})
 
-			// Si el juego ha terminado, enviar mensaje adicional
-			if r.GameState.IsGameOver {
-				var winner string
-				isDraw := false
-
-				if r.GameState.Winner != "" {
-					// Encontrar el ID del jugador ganador basado en su símbolo
-					for clientID, symbol := range r.GameState.PlayerSymbols {
-						if symbol == r.GameState.Winner {
-							winner = clientID
-							break
-						}
-					}
-					logger.Info("Juego terminado con ganador", logger.Fields{
-						"roomID":    r.ID,
-						"winnerID":  winner,
-						"winSymbol": r.GameState.Winner,
-					})
-				} else {
-					isDraw = true
-					logger.Info("Juego terminado en empate", logger.Fields{"roomID": r.ID})
-				}
-
-				// Enviar mensaje GAME_OVER con información detallada
-				endMsg := models.GameOverResponse{
-					Type:   "GAME_OVER",
-					Board:  boardJSON,
-					Winner: winner,
-					IsDraw: isDraw,
-				}
-				endBytes, _ := json.Marshal(endMsg)
-
-				for client := range r.Clients {
-					select {
-					case client.GetSendChannel() <- endBytes:
-						// Mensaje enviado con éxito
-					default:
-						logger.Warn("No se pudo enviar GAME_OVER, canal posiblemente cerrado", logger.Fields{
-							"clientID": client.GetID(),
-							"roomID":   r.ID,
-						})
-					}
-				}
-
-				// Task 33: Programar la eliminación de la sala después de que el juego termina
-				// ya que no se espera más actividad en ella
-				logger.Info("Juego terminado, programando eliminación de sala", logger.Fields{"roomID": r.ID})
-
-				// Verificar si el Hub tiene método para eliminar salas
-				hubWithDelete, ok := r.Hub.(interface {
-					DeleteRoom(roomID string)
-				})
-
-				if ok {
-					// Informar al Hub que elimine esta sala
-					hubWithDelete.DeleteRoom(r.ID)
-				}
+		// Also notify other players and spectators about reconnection
+		reconnectMsg := models.PlayerReconnectedResponse{
+			Type:     "PLAYER_RECONNECTED",
+			PlayerID: client.GetID(),
+		}
+		msgBytes, _ := json.Marshal(reconnectMsg)
+		r.broadcastExcept(client, msgBytes, "PLAYER_RECONNECTED")
+		return true
+
+	case StateFinished:
+		// La partida ya terminó: se reenvía el resultado en lugar de reanudarla
+		if r.lastResult != nil {
+			overBytes, _ := json.Marshal(r.lastResult)
+			r.sendToClient(client, overBytes, "GAME_OVER")
+		}
+		return true
+	}
+
+	return false
+}
+
+// handleUnregister retira a un jugador o espectador de la sala
+func (r *Room) handleUnregister(client interfaces.Client) {
+	// Los espectadores salen sin afectar a la partida
+	if _, ok := r.Spectators[client]; ok {
+		delete(r.Spectators, client)
+		client.SetRoom(nil)
+		r.record(roomlog.Event{Type: roomlog.EventSpectatorLeft, PlayerID: client.GetID(), Reason: "left"})
+		logger.Info("Espectador salió de la sala", logger.Fields{
+			"roomID":   r.ID,
+			"clientID": client.GetID(),
+		})
+		r.migrateHost(client.GetID())
+		return
+	}
+
+	if _, ok := r.Clients[client]; !ok {
+		return
+	}
+
+	// Durante la partida, el jugador conserva su asiento durante el tiempo de gracia
+	if r.gameInProgress() && r.Settings.DisconnectGrace > 0 {
+		r.startGracePeriod(client)
+		return
+	}
+
+	// Obtener el símbolo del jugador que se va
+	symbol := r.GameState.PlayerSymbols[client.GetID()]
+
+	// Eliminar cliente de la sala junto con su símbolo
+	delete(r.Clients, client)
+	r.unseatPlayer(client.GetID(), "left")
+	client.SetRoom(nil)
+
+	// Si se fue el anfitrión, cederlo a otro miembro
+	r.migrateHost(client.GetID())
+
+	// Si se estaba confirmando el inicio, se cancelan el ready-check y el sorteo
+	r.cancelReadyCheck()
+	r.cancelCoinFlip()
+
+	// Notificar al otro jugador (si existe) y a los espectadores con PLAYER_LEFT
+	if len(r.Clients) > 0 {
+		playerLeftMsg := models.PlayerLeftResponse{
+			Type:     "PLAYER_LEFT",
+			PlayerID: client.GetID(),
+		}
+		msgBytes, _ := json.Marshal(playerLeftMsg)
+		r.broadcastToAll(msgBytes, "PLAYER_LEFT")
+
+		// También terminar la partida, ya que no se puede continuar si un jugador abandona
+		if r.gameInProgress() {
+			var remaining interfaces.Client
+			for c := range r.Clients {
+				remaining = c
 			}
+
+			r.GameState.IsGameOver = true
+			r.GameState.Winner = r.GameState.PlayerSymbols[remaining.GetID()]
+			r.stopClock()
+
+			// El jugador que queda gana por abandono
+			r.announceGameOver(remaining.GetID(), false, "abandonment")
 		}
+
+		logger.Info("Jugador abandonó la sala", logger.Fields{
+			"roomID":   r.ID,
+			"clientID": client.GetID(),
+			"symbol":   symbol,
+		})
 	}
 }
 
-// getBoardJSON convierte el tablero del juego a formato JSON
-func getBoardJSON(board [3][3]string) [][]string {
-	return [][]string{
-		{board[0][0], board[0][1], board[0][2]},
-		{board[1][0], board[1][1], board[1][2]},
-		{board[2][0], board[2][1], board[2][2]},
+// handleTimer procesa un temporizador vencido de la sala
+func (r *Room) handleTimer(ev roomTimer) {
+	if !r.acceptTimer(ev) {
+		return
+	}
+
+	switch ev.kind {
+	case timerClock:
+		r.handleClockExpired()
+	case timerReady:
+		r.handleReadyTimeout()
+	case timerCoinFlip:
+		r.handleCoinFlipTimeout()
+	case timerPause:
+		r.handlePauseExpired()
+	default:
+		if playerID, ok := graceTimerPlayer(ev.kind); ok {
+			r.handleGraceExpired(playerID)
+		}
 	}
 }
 
-// GetPlayerIDs returns a slice of player IDs in this room
-func (r *Room) GetPlayerIDs() []string {
-	playerIDs := make([]string, 0, len(r.Clients))
+// handleMove valida y aplica la jugada de un jugador
+func (r *Room) handleMove(moveReq *models.PlayerMove) {
+	// Obtener client y moveData del PlayerMove
+	moveClient, ok := moveReq.Client.(interfaces.Client)
+	if !ok {
+		logger.Error("Cliente en ReceiveMove no es del tipo correcto", nil)
+		return
+	}
+
+	moveData := moveReq.MoveData
+
+	// Obtener el símbolo del cliente
+	playerSymbol, ok := r.GameState.PlayerSymbols[moveClient.GetID()]
+	if !ok {
+		// Cliente no registrado en el juego
+		errors.NotInGame(moveClient.GetSendChannel(), moveClient.GetID())
+		return
+	}
+
+	// La partida debe haber empezado (por ejemplo, tras el ready-check)
+	if r.state == StateWaiting || r.state == StateReadyCheck {
+		errors.GameNotStarted(moveClient.GetSendChannel(), moveClient.GetID())
+		return
+	}
+
+	// Con la partida en pausa no se admiten jugadas
+	if r.state == StatePaused {
+		errors.GamePaused(moveClient.GetSendChannel(), moveClient.GetID())
+		return
+	}
+
+	// Validar si es el turno del cliente
+	if r.GameState.CurrentTurnSymbol != playerSymbol {
+		// No es el turno de este jugador
+		errors.NotYourTurn(moveClient.GetSendChannel(), moveClient.GetID())
+		return
+	}
+
+	// Si el reloj del jugador ya se agotó, la partida termina por tiempo
+	if r.Clock != nil {
+		if _, flagged := r.Clock.Flagged(time.Now()); flagged {
+			r.handleClockExpired()
+			return
+		}
+	}
+
+	// Aplicar el movimiento
+	err := game.ApplyMove(r.GameState, playerSymbol, moveData.Row, moveData.Col)
+	if err != nil {
+		// Movimiento inválido
+		errors.InvalidMove(moveClient.GetSendChannel(), err.Error(), moveClient.GetID())
+		return
+	}
+
+	r.record(roomlog.Event{
+		Type:     roomlog.EventMoveApplied,
+		PlayerID: moveClient.GetID(),
+		Symbol:   playerSymbol,
+		Move:     &models.MovePayload{Row: moveData.Row, Col: moveData.Col},
+	})
+
+	// Una solicitud de pausa pendiente caduca con la siguiente jugada
+	r.pauseRequestedBy = ""
+
+	// Actualizar el reloj: se detiene al terminar o pasa al rival
+	if r.GameState.IsGameOver {
+		r.stopClock()
+	} else {
+		r.switchClock()
+	}
+
+	// Movimiento válido, informar a todos los jugadores y espectadores
+	r.moveNumber++
+	r.broadcastGameUpdate(moveData, []models.CellChange{
+		{Row: moveData.Row, Col: moveData.Col, Symbol: playerSymbol},
+	})
+
+	logger.Info("Movimiento realizado", logger.Fields{
+		"roomID":   r.ID,
+		"clientID": moveClient.GetID(),
+		"symbol":   playerSymbol,
+		"row":      moveData.Row,
+		"col":      moveData.Col,
+	})
+
+	if !r.GameState.IsGameOver {
+		return
+	}
+
+	// Si el juego ha terminado, enviar mensaje adicional
+	var winner string
+	isDraw := false
+	reason := "win"
+
+	if r.GameState.Winner != "" {
+		// Encontrar el ID del jugador ganador basado en su símbolo
+		winner = r.playerIDForSymbol(r.GameState.Winner)
+		logger.Info("Juego terminado con ganador", logger.Fields{
+			"roomID":    r.ID,
+			"winnerID":  winner,
+			"winSymbol": r.GameState.Winner,
+		})
+	} else {
+		isDraw = true
+		reason = "draw"
+		logger.Info("Juego terminado en empate", logger.Fields{"roomID": r.ID})
+	}
+
+	r.announceGameOver(winner, isDraw, reason)
+}
+
+// startGame pone en marcha la partida y envía GAME_START a jugadores y espectadores
+func (r *Room) startGame() {
+	r.gameID = uuid.NewString()
+	r.moveNumber = 0
+	r.record(roomlog.Event{Type: roomlog.EventGameStarted, GameID: r.gameID, Symbol: r.GameState.CurrentTurnSymbol})
+	r.transition(StatePlaying, "game_started")
+
+	// Poner en marcha el reloj si la sala tiene control de tiempo
+	r.startClock()
+
+	// Convertir el tablero a formato JSON para el mensaje
+	boardJSON := getBoardJSON(r.GameState.Board)
+
+	// Mensaje mejorado de inicio de juego con estado completo
+	gameStartMsg := models.GameStartResponse{
+		Type:        "GAME_START",
+		GameID:      r.gameID,
+		Board:       boardJSON,
+		CurrentTurn: r.GameState.CurrentTurnSymbol,
+		Players:     r.GameState.PlayerSymbols,
+		Clocks:      r.clockMillis(),
+		Profiles:    r.seatedProfiles(),
+	}
+	startBytes, _ := json.Marshal(gameStartMsg)
+
+	// Enviar mensaje GAME_START a ambos jugadores y a los espectadores
+	r.broadcastToAll(startBytes, "GAME_START")
+
+	logger.Info("Juego iniciado", logger.Fields{
+		"roomID":      r.ID,
+		"players":     r.GameState.PlayerSymbols,
+		"currentTurn": r.GameState.CurrentTurnSymbol,
+	})
+}
+
+// announceGameOver cierra la partida y envía GAME_OVER a jugadores y espectadores
+func (r *Room) announceGameOver(winner string, isDraw bool, reason string) {
+	// Enviar mensaje GAME_OVER con información detallada
+	r.lastResult = &models.GameOverResponse{
+		Type:   "GAME_OVER",
+		GameID: r.gameID,
+		Board:  getBoardJSON(r.GameState.Board),
+		Winner: winner,
+		IsDraw: isDraw,
+		Reason: reason,
+		Clocks: r.clockMillis(),
+	}
+
+	r.record(roomlog.Event{
+		Type:     roomlog.EventGameOver,
+		GameID:   r.gameID,
+		PlayerID: winner,
+		Symbol:   r.GameState.Winner,
+		IsDraw:   isDraw,
+		Reason:   reason,
+	})
+
+	// Los jugadores se toman del inicio de la partida: quien abandona ya no tiene asiento
+	if record, ok := r.finishedGame(); ok {
+		r.lastResult.RatingChanges = r.rateGame(record)
+		record.RatingChanges = r.lastResult.RatingChanges
+		r.archiveGame(record)
+		r.reportResult(record)
+	}
+
+	r.clearPause()
+	r.transition(StateFinished, reason)
+
+	endBytes, _ := json.Marshal(r.lastResult)
+	r.broadcastToAll(endBytes, "GAME_OVER")
+}
+
+// handleCommand despacha una acción de sala enviada por un cliente
+func (r *Room) handleCommand(cmd *Command) {
+	switch cmd.Type {
+	case "KICK_PLAYER":
+		r.handleKickPlayer(cmd)
+	case "LOCK_ROOM":
+		r.handleSetLocked(cmd, true)
+	case "UNLOCK_ROOM":
+		r.handleSetLocked(cmd, false)
+	case "TRANSFER_HOST":
+		r.handleTransferHost(cmd)
+	case "PLAYER_READY":
+		r.handlePlayerReady(cmd)
+	case "COIN_FLIP_COMMIT":
+		r.handleCoinFlipCommit(cmd)
+	case "COIN_FLIP_REVEAL":
+		r.handleCoinFlipReveal(cmd)
+	case "REQUEST_PAUSE":
+		r.handleRequestPause(cmd)
+	case "ACCEPT_PAUSE":
+		r.handleAcceptPause(cmd)
+	case "RESUME":
+		r.handleResumeGame(cmd)
+	case "RESYNC":
+		r.handleResync(cmd)
+	default:
+		errors.UnknownMessageType(cmd.Client.GetSendChannel(), cmd.Type, cmd.Client.GetID())
+	}
+}
+
+// admit comprueba si un cliente nuevo puede entrar a la sala (no expulsado y sala sin bloquear).
+// Si no puede, le envía el error correspondiente y lo desasocia de la sala
+func (r *Room) admit(client interfaces.Client) bool {
+	if r.banned[client.GetID()] {
+		errors.Kicked(client.GetSendChannel(), client.GetID())
+		client.SetRoom(nil)
+		return false
+	}
+
+	if r.locked {
+		errors.RoomLocked(client.GetSendChannel(), client.GetID())
+		client.SetRoom(nil)
+		return false
+	}
+
+	return true
+}
+
+// resetGame prepara un tablero nuevo conservando los símbolos de los jugadores
+func (r *Room) resetGame() {
+	playerSymbols := r.GameState.PlayerSymbols
+	r.GameState = game.NewGameStateWithSize(r.GameState.Board.Size(), r.GameState.WinLength)
+	r.GameState.PlayerSymbols = playerSymbols
+	r.record(roomlog.Event{Type: roomlog.EventGameReset})
+	r.Clock = nil
+	r.lastResult = nil
+	r.pausesUsed = 0
+	r.clearPause()
+	r.transition(StateWaiting, "new_game")
+}
+
+// handleSpectatorJoin registra a un cliente como espectador si la configuración lo permite
+func (r *Room) handleSpectatorJoin(spectator interfaces.Client) {
+	if !r.admit(spectator) {
+		return
+	}
+
+	if !r.Settings.AllowSpectators {
+		errors.SpectatorsNotAllowed(spectator.GetSendChannel(), spectator.GetID())
+		spectator.SetRoom(nil)
+		return
+	}
+
+	if len(r.Spectators) >= r.Settings.MaxSpectators {
+		errors.SpectatorsFull(spectator.GetSendChannel(), spectator.GetID())
+		spectator.SetRoom(nil)
+		return
+	}
+
+	r.Spectators[spectator] = true
+	r.record(roomlog.Event{Type: roomlog.EventSpectatorJoined, PlayerID: spectator.GetID()})
+
+	// Enviar al espectador el estado actual de la partida
+	spectatingMsg := models.SpectatingResponse{
+		Type:        "SPECTATING",
+		RoomID:      r.ID,
+		RoomCode:    r.Code,
+		Settings:    r.Settings,
+		Board:       getBoardJSON(r.GameState.Board),
+		CurrentTurn: r.GameState.CurrentTurnSymbol,
+		Players:     r.GameState.PlayerSymbols,
+		Clocks:      r.clockMillis(),
+		HostID:      r.HostID,
+		Locked:      r.locked,
+		State:       string(r.state),
+		Profiles:    r.seatedProfiles(),
+	}
+	msgBytes, _ := json.Marshal(spectatingMsg)
+	r.sendToClient(spectator, msgBytes, "SPECTATING")
+
+	logger.Info("Espectador unido a sala", logger.Fields{
+		"roomID":     r.ID,
+		"clientID":   spectator.GetID(),
+		"spectators": len(r.Spectators),
+	})
+}
+
+// handleClockExpired termina la partida cuando se agota el reloj del jugador en turno
+func (r *Room) handleClockExpired() {
+	if r.Clock == nil || !r.gameInProgress() {
+		return
+	}
+
+	flaggedSymbol, flagged := r.Clock.Flagged(time.Now())
+	if !flagged {
+		// El reloj aún tiene tiempo (por ejemplo tras un incremento), reprogramar
+		r.scheduleClockTimer()
+		return
+	}
+
+	r.Clock.Stop(time.Now())
+
+	winnerSymbol := game.OppositeSymbol(flaggedSymbol)
+	r.GameState.IsGameOver = true
+	r.GameState.Winner = winnerSymbol
+
+	winner := r.playerIDForSymbol(winnerSymbol)
+
+	logger.Info("Juego terminado por tiempo", logger.Fields{
+		"roomID":        r.ID,
+		"flaggedSymbol": flaggedSymbol,
+		"winnerID":      winner,
+	})
+
+	r.announceGameOver(winner, false, "timeout")
+}
+
+// startClock crea el reloj de la partida (si hay control de tiempo) y lo pone en marcha
+func (r *Room) startClock() {
+	tc := r.Settings.TimeControl
+	if tc.InitialSeconds <= 0 {
+		return
+	}
+
+	r.Clock = game.NewClock(
+		time.Duration(tc.InitialSeconds)*time.Second,
+		time.Duration(tc.IncrementSeconds)*time.Second,
+	)
+	r.Clock.Start(r.GameState.CurrentTurnSymbol, time.Now())
+	r.scheduleClockTimer()
+}
+
+// switchClock pasa el reloj al jugador en turno tras una jugada
+func (r *Room) switchClock() {
+	if r.Clock == nil {
+		return
+	}
+
+	r.Clock.Switch(r.GameState.CurrentTurnSymbol, time.Now())
+	r.scheduleClockTimer()
+}
+
+// stopClock detiene el reloj y su temporizador
+func (r *Room) stopClock() {
+	if r.Clock == nil {
+		return
+	}
+
+	r.Clock.Stop(time.Now())
+	r.stopTimer(timerClock)
+}
+
+// scheduleClockTimer programa el temporizador que detecta la caída del reloj en marcha
+func (r *Room) scheduleClockTimer() {
+	if r.Clock == nil || r.Clock.Running == "" {
+		return
+	}
+
+	r.startTimer(timerClock, r.Clock.RemainingFor(r.Clock.Running, time.Now()))
+}
+
+// clockMillis devuelve el tiempo restante de cada símbolo, o nil si no hay reloj
+func (r *Room) clockMillis() map[string]int64 {
+	if r.Clock == nil {
+		return nil
+	}
+	return r.Clock.Millis(time.Now())
+}
+
+// playerIDForSymbol devuelve el ID del jugador que juega con el símbolo indicado
+func (r *Room) playerIDForSymbol(symbol string) string {
+	for clientID, s := range r.GameState.PlayerSymbols {
+		if s == symbol {
+			return clientID
+		}
+	}
+	return ""
+}
+
+// sendToClient envía un mensaje a un cliente sin bloquear el bucle de la sala.
+// El mensaje lleva la versión actual de la sala
+func (r *Room) sendToClient(client interfaces.Client, msgBytes []byte, msgType string) {
+	r.deliver(client, r.stamp(msgBytes), msgType)
+}
+
+// deliver entrega un mensaje ya versionado sin bloquear el bucle de la sala
+func (r *Room) deliver(client interfaces.Client, msgBytes []byte, msgType string) {
+	select {
+	case client.GetSendChannel() <- msgBytes:
+		// Mensaje enviado con éxito
+	default:
+		logger.Warn("No se pudo enviar "+msgType+", canal posiblemente cerrado", logger.Fields{
+			"clientID": client.GetID(),
+			"roomID":   r.ID,
+		})
+	}
+}
+
+// broadcastToAll envía un mensaje a todos los jugadores y espectadores de la sala
+func (r *Room) broadcastToAll(msgBytes []byte, msgType string) {
+	r.broadcastExcept(nil, msgBytes, msgType)
+}
+
+// broadcastExcept envía un mensaje a jugadores y espectadores, excepto a except. Cada
+// difusión avanza la versión de la sala, así que quien se pierda una detecta el salto
+func (r *Room) broadcastExcept(except interfaces.Client, msgBytes []byte, msgType string) {
+	r.version++
+	stamped := r.stamp(msgBytes)
 	for client := range r.Clients {
-		playerIDs = append(playerIDs, client.GetID())
+		if client != except {
+			r.deliver(client, stamped, msgType)
+		}
+	}
+	for spectator := range r.Spectators {
+		if spectator != except {
+			r.deliver(spectator, stamped, msgType)
+		}
 	}
-	return playerIDs
+}
+
+// getBoardJSON convierte el tablero del juego a formato JSON
+func getBoardJSON(board game.Board) [][]string {
+	return board.Copy()
+}
+
+// GetPlayerIDs returns a slice of player IDs in this room. It reads the published
+// snapshot, so it is safe to call from outside the room loop
+func (r *Room) GetPlayerIDs() []string {
+	return r.Info().Players
 }
 
 // Este paquete será implementado en la Fase 3
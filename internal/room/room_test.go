@@ -1,10 +1,22 @@
 package room
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
+
+	"nvivas/backend/tictactoe-go-server/internal/coinflip"
+	"nvivas/backend/tictactoe-go-server/internal/errors"
 	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
 )
 
 // TestNewRoom verifica que la creación de una sala inicialice correctamente sus campos
@@ -103,3 +115,397 @@ func TestGameStateMethods(t *testing.T) {
 		t.Errorf("Ganador incorrecto, esperado 'X', obtenido '%s'", gs.Winner)
 	}
 }
+
+// fakeClient implementa interfaces.Client sin conexión WebSocket para probar el bucle de la sala
+type fakeClient struct {
+	id   string
+	send chan []byte
+	room interface{}
+}
+
+func newFakeClient(id string) *fakeClient {
+	return &fakeClient{id: id, send: make(chan []byte, 64)}
+}
+
+func (f *fakeClient) GetID() string                  { return f.id }
+func (f *fakeClient) GetSendChannel() chan []byte    { return f.send }
+func (f *fakeClient) GetConnection() *websocket.Conn { return nil }
+func (f *fakeClient) SetRoom(room interface{})       { f.room = room }
+func (f *fakeClient) GetRoom() interface{}           { return f.room }
+func (f *fakeClient) Close()                         {}
+
+// waitForMessage lee mensajes del cliente hasta encontrar uno del tipo indicado
+func waitForMessage(t *testing.T, c *fakeClient, msgType string) map[string]interface{} {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case msgBytes := <-c.send:
+			var msg map[string]interface{}
+			if err := json.Unmarshal(msgBytes, &msg); err != nil {
+				t.Fatalf("Mensaje inválido: %v", err)
+			}
+			if msg["type"] == msgType {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("No se recibió %s para %s", msgType, c.id)
+			return nil
+		}
+	}
+}
+
+// TestMain inicializa el logger, necesario para ejecutar el bucle de la sala
+func TestMain(m *testing.M) {
+	logger.Initialize()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// TestRoomSpectatorReceivesGame verifica que un espectador reciba el inicio y las jugadas
+func TestRoomSpectatorReceivesGame(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRoom("test-room", nil, ctx)
+	go r.Run()
+
+	p1, p2, spectator := newFakeClient("p1"), newFakeClient("p2"), newFakeClient("s1")
+	r.Register <- p1
+	r.RegisterSpectator <- spectator
+	waitForMessage(t, spectator, "SPECTATING")
+
+	r.Register <- p2
+	waitForMessage(t, spectator, "GAME_START")
+
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 1, Col: 1}}
+	update := waitForMessage(t, spectator, "GAME_UPDATE")
+	if update["currentTurn"] != "O" {
+		t.Errorf("Turno incorrecto tras la jugada, esperado 'O', obtenido '%v'", update["currentTurn"])
+	}
+
+	// Un espectador no puede mover
+	r.ReceiveMove <- &models.PlayerMove{Client: spectator, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, spectator, errors.ErrorNotInGame)
+}
+
+// TestRoomClockTimeout verifica que la partida termine cuando se agota el reloj
+func TestRoomClockTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Se usa un reloj de un segundo (por debajo del mínimo validado) para no alargar la prueba
+	settings := DefaultSettings()
+	settings.BoardSize = game.ClassicBoardSize
+	settings.TimeControl = models.TimeControl{InitialSeconds: 1}
+	r := NewRoomWithSettings("timed-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	start := waitForMessage(t, p2, "GAME_START")
+	if start["clocks"] == nil {
+		t.Error("GAME_START debería incluir los relojes")
+	}
+
+	over := waitForMessage(t, p2, "GAME_OVER")
+	if over["reason"] != "timeout" {
+		t.Errorf("Motivo incorrecto, esperado 'timeout', obtenido '%v'", over["reason"])
+	}
+	if over["winner"] != "p2" {
+		t.Errorf("Ganador incorrecto, esperado 'p2', obtenido '%v'", over["winner"])
+	}
+}
+
+// TestRoomHostControls verifica permisos de anfitrión, bloqueo, expulsión y migración
+func TestRoomHostControls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRoom("host-room", nil, ctx)
+	go r.Run()
+
+	host, spectator, troll := newFakeClient("host"), newFakeClient("s1"), newFakeClient("troll")
+	r.Register <- host
+	r.RegisterSpectator <- spectator
+	r.RegisterSpectator <- troll
+	waitForMessage(t, troll, "SPECTATING")
+
+	// Un espectador que no es anfitrión no puede bloquear la sala
+	r.Submit(&Command{Client: troll, Type: "LOCK_ROOM"})
+	waitForMessage(t, troll, errors.ErrorNotHost)
+
+	// El anfitrión expulsa al espectador molesto
+	payload, _ := json.Marshal(models.TargetPlayerPayload{PlayerID: "troll"})
+	r.Submit(&Command{Client: host, Type: "KICK_PLAYER", Payload: payload})
+	waitForMessage(t, troll, "KICKED_FROM_ROOM")
+	waitForMessage(t, spectator, "PLAYER_KICKED")
+
+	// El expulsado no puede volver
+	r.RegisterSpectator <- troll
+	waitForMessage(t, troll, errors.ErrorKicked)
+
+	// Con la sala bloqueada nadie nuevo puede entrar
+	r.Submit(&Command{Client: host, Type: "LOCK_ROOM"})
+	waitForMessage(t, spectator, "ROOM_LOCKED")
+	newcomer := newFakeClient("p2")
+	r.Register <- newcomer
+	waitForMessage(t, newcomer, errors.ErrorRoomLocked)
+
+	// Si el anfitrión se va, el rol pasa al miembro restante
+	r.Unregister <- host
+	changed := waitForMessage(t, spectator, "HOST_CHANGED")
+	if changed["hostId"] != "s1" || changed["reason"] != "host_left" {
+		t.Errorf("Migración de anfitrión incorrecta: %v", changed)
+	}
+}
+
+// TestRoomReadyCheck verifica que la partida solo empiece cuando ambos jugadores confirman
+func TestRoomReadyCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.ReadyCheck = true
+	settings.ReadyTimeout = 1
+	r := NewRoomWithSettings("ready-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p1, "READY_CHECK")
+
+	// No se puede mover antes de empezar
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, p1, errors.ErrorGameNotStarted)
+
+	r.Submit(&Command{Client: p1, Type: "PLAYER_READY"})
+	r.Submit(&Command{Client: p2, Type: "PLAYER_READY"})
+	waitForMessage(t, p2, "GAME_START")
+}
+
+// TestRoomReadyCheckTimeout verifica que se retire a quien no confirma a tiempo
+func TestRoomReadyCheckTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.ReadyCheck = true
+	settings.ReadyTimeout = 1
+	r := NewRoomWithSettings("ready-timeout-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p1, "READY_CHECK")
+
+	r.Submit(&Command{Client: p1, Type: "PLAYER_READY"})
+
+	removed := waitForMessage(t, p2, "REMOVED_FROM_ROOM")
+	if removed["reason"] != "not_ready" {
+		t.Errorf("Motivo incorrecto, esperado 'not_ready', obtenido '%v'", removed["reason"])
+	}
+
+	cancelled := waitForMessage(t, p1, "READY_CHECK_CANCELLED")
+	if cancelled["reason"] != "timeout" {
+		t.Errorf("Motivo incorrecto, esperado 'timeout', obtenido '%v'", cancelled["reason"])
+	}
+}
+
+// TestRoomCoinFlip verifica el sorteo commit-reveal del primer movimiento
+func TestRoomCoinFlip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.FirstMove = FirstMoveCoinFlip
+	r := NewRoomWithSettings("flip-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p1, "COIN_FLIP")
+
+	nonce1 := bytes.Repeat([]byte{0x10}, 32)
+	nonce2 := bytes.Repeat([]byte{0x11}, 32)
+
+	commit := func(c *fakeClient, nonce []byte) {
+		payload, _ := json.Marshal(models.CoinFlipCommitPayload{Commitment: coinflip.Commit(nonce)})
+		r.Submit(&Command{Client: c, Type: "COIN_FLIP_COMMIT", Payload: payload})
+	}
+	reveal := func(c *fakeClient, nonce []byte) {
+		payload, _ := json.Marshal(models.CoinFlipRevealPayload{Nonce: hex.EncodeToString(nonce)})
+		r.Submit(&Command{Client: c, Type: "COIN_FLIP_REVEAL", Payload: payload})
+	}
+
+	// No se puede revelar antes de que ambos se comprometan
+	reveal(p1, nonce1)
+	waitForMessage(t, p1, errors.ErrorInvalidCoinFlip)
+
+	commit(p1, nonce1)
+	commit(p2, nonce2)
+	reveal(p1, nonce1)
+	reveal(p2, nonce2)
+
+	result := waitForMessage(t, p2, "COIN_FLIP_RESULT")
+	// 0x10 XOR 0x11 = 0x01, así que empieza el rival del creador
+	if result["firstPlayerId"] != "p2" || result["reason"] != "xor" {
+		t.Errorf("Resultado del sorteo incorrecto: %v", result)
+	}
+
+	start := waitForMessage(t, p2, "GAME_START")
+	if start["currentTurn"] != "O" {
+		t.Errorf("Turno inicial incorrecto, esperado 'O', obtenido '%v'", start["currentTurn"])
+	}
+}
+
+// TestRoomReplacesStaleConnection verifica que una nueva conexión del mismo jugador
+// sustituya a la anterior y continúe la partida
+func TestRoomReplacesStaleConnection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRoom("resume-room", nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p1, "GAME_START")
+
+	// El jugador vuelve con otra conexión sin que la anterior se haya cerrado
+	resumed := newFakeClient("p1")
+	r.Register <- resumed
+	start := waitForMessage(t, resumed, "GAME_START")
+	if start["currentTurn"] != "X" {
+		t.Errorf("Turno incorrecto tras reanudar, obtenido '%v'", start["currentTurn"])
+	}
+	waitForMessage(t, p2, "PLAYER_RECONNECTED")
+
+	r.ReceiveMove <- &models.PlayerMove{Client: resumed, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, p2, "GAME_UPDATE")
+
+	if p1.GetRoom() != nil {
+		t.Error("La conexión anterior debería quedar fuera de la sala")
+	}
+	// La conexión anterior no recibe nada después del reemplazo
+	for len(p1.send) > 0 {
+		var msg map[string]interface{}
+		json.Unmarshal(<-p1.send, &msg)
+		if msg["type"] == "GAME_UPDATE" {
+			t.Error("La conexión anterior no debería recibir jugadas")
+		}
+	}
+}
+
+// TestRoomDisconnectGrace verifica que una desconexión pause la partida y que el
+// jugador pueda volver antes de que venza el tiempo de gracia
+func TestRoomDisconnectGrace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRoom("grace-room", nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	r.Unregister <- p1
+	disconnected := waitForMessage(t, p2, "PLAYER_DISCONNECTED")
+	if disconnected["playerId"] != "p1" || disconnected["graceSeconds"] != float64(defaultDisconnectGrace) {
+		t.Errorf("PLAYER_DISCONNECTED incorrecto: %v", disconnected)
+	}
+
+	// El rival no puede aprovechar la pausa
+	r.ReceiveMove <- &models.PlayerMove{Client: p2, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, p2, errors.ErrorGamePaused)
+
+	// El jugador vuelve con una conexión nueva y la partida continúa
+	back := newFakeClient("p1")
+	r.Register <- back
+	waitForMessage(t, back, "GAME_START")
+	waitForMessage(t, p2, "PLAYER_RECONNECTED")
+	changed := waitForMessage(t, p2, "ROOM_STATE_CHANGED")
+	if changed["state"] != string(StatePlaying) {
+		t.Errorf("Se esperaba reanudar la partida, estado '%v'", changed["state"])
+	}
+
+	r.ReceiveMove <- &models.PlayerMove{Client: back, MoveData: models.MovePayload{Row: 1, Col: 1}}
+	waitForMessage(t, p2, "GAME_UPDATE")
+}
+
+// TestRoomDisconnectGraceExpires verifica que el jugador que no vuelve pierda por abandono
+func TestRoomDisconnectGraceExpires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.DisconnectGrace = 1
+	r := NewRoomWithSettings("grace-expire-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	r.Unregister <- p1
+	waitForMessage(t, p2, "PLAYER_DISCONNECTED")
+
+	gameOver := waitForMessage(t, p2, "GAME_OVER")
+	if gameOver["winner"] != "p2" || gameOver["reason"] != "abandonment" {
+		t.Errorf("GAME_OVER incorrecto: %v", gameOver)
+	}
+}
+
+// TestRoomMutualPause verifica la pausa de mutuo acuerdo y su límite por partida
+func TestRoomMutualPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.MaxPauses = 1
+	settings.TimeControl = models.TimeControl{InitialSeconds: 60}
+	r := NewRoomWithSettings("pause-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	// Quien pide la pausa no puede aceptarla
+	r.Submit(&Command{Client: p1, Type: "REQUEST_PAUSE"})
+	waitForMessage(t, p2, "PAUSE_REQUESTED")
+	r.Submit(&Command{Client: p1, Type: "ACCEPT_PAUSE"})
+	waitForMessage(t, p1, errors.ErrorInvalidPause)
+
+	r.Submit(&Command{Client: p2, Type: "ACCEPT_PAUSE"})
+	paused := waitForMessage(t, p1, "GAME_PAUSED")
+	if paused["pausesUsed"] != float64(1) || paused["acceptedBy"] != "p2" {
+		t.Errorf("GAME_PAUSED incorrecto: %v", paused)
+	}
+
+	// Las jugadas se rechazan y el reloj no corre durante la pausa
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, p1, errors.ErrorGamePaused)
+
+	r.Submit(&Command{Client: p2, Type: "RESUME"})
+	resumed := waitForMessage(t, p1, "GAME_RESUMED")
+	if resumed["resumedBy"] != "p2" || resumed["reason"] != "resumed" {
+		t.Errorf("GAME_RESUMED incorrecto: %v", resumed)
+	}
+
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, p2, "GAME_UPDATE")
+
+	// Solo se permitía una pausa
+	r.Submit(&Command{Client: p2, Type: "REQUEST_PAUSE"})
+	waitForMessage(t, p2, errors.ErrorInvalidPause)
+}
@@ -0,0 +1,191 @@
+package room
+
+import (
+	"fmt"
+	"strings"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+const (
+	// VisibilityPublic indica que la sala aparece en LIST_ROOMS
+	VisibilityPublic = "public"
+
+	// VisibilityPrivate indica que solo se puede entrar con el ID o el código
+	VisibilityPrivate = "private"
+
+	// SymbolRandom indica que el símbolo del creador se sortea
+	SymbolRandom = "random"
+
+	// Límites del control de tiempo
+	minInitialSeconds   = 10
+	maxInitialSeconds   = 2 * 60 * 60
+	maxIncrementSeconds = 60
+
+	// Límites del ready-check
+	minReadyTimeout     = 5
+	maxReadyTimeout     = 300
+	defaultReadyTimeout = 30
+
+	// Tiempo de gracia para volver tras una desconexión (0 = abandono inmediato)
+	maxDisconnectGrace     = 300
+	defaultDisconnectGrace = 30
+
+	// Pausas de mutuo acuerdo por partida
+	maxPausesLimit         = 10
+	defaultMaxPauses       = 2
+	minPauseSeconds        = 10
+	maxPauseSecondsLimit   = 24 * 60 * 60
+	defaultMaxPauseSeconds = 5 * 60
+
+	// Límite de espectadores por sala
+	maxSpectatorsLimit   = 50
+	defaultMaxSpectators = 10
+)
+
+// SettingsError agrupa los problemas encontrados al validar la configuración
+type SettingsError struct {
+	Problems []string
+}
+
+// Error implementa la interfaz error
+func (e *SettingsError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// DefaultSettings devuelve la configuración usada cuando el cliente no indica nada
+func DefaultSettings() models.RoomSettings {
+	return models.RoomSettings{
+		Variant:         game.VariantClassic,
+		BoardSize:       0,
+		Visibility:      VisibilityPublic,
+		PreferredSymbol: "X",
+		Rated:           false,
+		AllowSpectators: true,
+		MaxSpectators:   defaultMaxSpectators,
+		ReadyCheck:      false,
+		ReadyTimeout:    defaultReadyTimeout,
+		FirstMove:       FirstMoveCreator,
+		DisconnectGrace: defaultDisconnectGrace,
+		MaxPauses:       defaultMaxPauses,
+		MaxPauseSeconds: defaultMaxPauseSeconds,
+	}
+}
+
+// ValidateSettings valida la configuración recibida y devuelve la configuración efectiva,
+// con los valores por defecto ya resueltos
+func ValidateSettings(settings models.RoomSettings) (models.RoomSettings, error) {
+	var problems []string
+
+	// Variante y tamaño del tablero
+	variant, ok := game.LookupVariant(settings.Variant)
+	if !ok {
+		problems = append(problems, fmt.Sprintf("variant '%s' no soportada (valores válidos: %s)",
+			settings.Variant, strings.Join(game.VariantNames(), ", ")))
+	} else {
+		if settings.BoardSize == 0 {
+			settings.BoardSize = variant.DefaultSize
+		}
+		if settings.BoardSize < variant.MinSize || settings.BoardSize > variant.MaxSize {
+			problems = append(problems, fmt.Sprintf("boardSize %d fuera de rango para '%s' (%d-%d)",
+				settings.BoardSize, variant.Name, variant.MinSize, variant.MaxSize))
+		}
+	}
+
+	// Control de tiempo
+	tc := settings.TimeControl
+	if tc.InitialSeconds < 0 || tc.IncrementSeconds < 0 {
+		problems = append(problems, "timeControl no admite valores negativos")
+	} else if tc.InitialSeconds == 0 && tc.IncrementSeconds > 0 {
+		problems = append(problems, "timeControl.incrementSeconds requiere initialSeconds")
+	} else if tc.InitialSeconds > 0 {
+		if tc.InitialSeconds < minInitialSeconds || tc.InitialSeconds > maxInitialSeconds {
+			problems = append(problems, fmt.Sprintf("timeControl.initialSeconds debe estar entre %d y %d",
+				minInitialSeconds, maxInitialSeconds))
+		}
+		if tc.IncrementSeconds > maxIncrementSeconds {
+			problems = append(problems, fmt.Sprintf("timeControl.incrementSeconds no puede superar %d",
+				maxIncrementSeconds))
+		}
+	}
+
+	// Visibilidad
+	if settings.Visibility == "" {
+		settings.Visibility = VisibilityPublic
+	}
+	if settings.Visibility != VisibilityPublic && settings.Visibility != VisibilityPrivate {
+		problems = append(problems, fmt.Sprintf("visibility '%s' inválida (public o private)", settings.Visibility))
+	}
+
+	// Símbolo preferido
+	settings.PreferredSymbol = strings.ToUpper(settings.PreferredSymbol)
+	switch settings.PreferredSymbol {
+	case "":
+		settings.PreferredSymbol = "X"
+	case "X", "O":
+	case strings.ToUpper(SymbolRandom):
+		settings.PreferredSymbol = SymbolRandom
+	default:
+		problems = append(problems, fmt.Sprintf("preferredSymbol '%s' inválido (X, O o random)", settings.PreferredSymbol))
+	}
+
+	// Espectadores
+	if settings.MaxSpectators < 0 || settings.MaxSpectators > maxSpectatorsLimit {
+		problems = append(problems, fmt.Sprintf("maxSpectators debe estar entre 0 y %d", maxSpectatorsLimit))
+	}
+	if !settings.AllowSpectators {
+		settings.MaxSpectators = 0
+	} else if settings.MaxSpectators == 0 {
+		problems = append(problems, "maxSpectators debe ser mayor que 0 si se permiten espectadores")
+	}
+
+	// Política de primer movimiento
+	switch settings.FirstMove {
+	case "":
+		settings.FirstMove = FirstMoveCreator
+	case FirstMoveCreator, FirstMoveJoiner, FirstMoveRandom, FirstMoveCoinFlip:
+	default:
+		problems = append(problems, fmt.Sprintf("firstMove '%s' inválido (creator, joiner, random o coinflip)", settings.FirstMove))
+	}
+
+	// Ready-check
+	if !settings.ReadyCheck {
+		settings.ReadyTimeout = 0
+	} else {
+		if settings.ReadyTimeout == 0 {
+			settings.ReadyTimeout = defaultReadyTimeout
+		}
+		if settings.ReadyTimeout < minReadyTimeout || settings.ReadyTimeout > maxReadyTimeout {
+			problems = append(problems, fmt.Sprintf("readyTimeout debe estar entre %d y %d segundos",
+				minReadyTimeout, maxReadyTimeout))
+		}
+	}
+
+	// Tiempo de gracia por desconexión
+	if settings.DisconnectGrace < 0 || settings.DisconnectGrace > maxDisconnectGrace {
+		problems = append(problems, fmt.Sprintf("disconnectGrace debe estar entre 0 y %d segundos", maxDisconnectGrace))
+	}
+
+	// Pausas
+	if settings.MaxPauses < 0 || settings.MaxPauses > maxPausesLimit {
+		problems = append(problems, fmt.Sprintf("maxPauses debe estar entre 0 y %d", maxPausesLimit))
+	}
+	if settings.MaxPauses == 0 {
+		settings.MaxPauseSeconds = 0
+	} else {
+		if settings.MaxPauseSeconds == 0 {
+			settings.MaxPauseSeconds = defaultMaxPauseSeconds
+		}
+		if settings.MaxPauseSeconds < minPauseSeconds || settings.MaxPauseSeconds > maxPauseSecondsLimit {
+			problems = append(problems, fmt.Sprintf("maxPauseSeconds debe estar entre %d y %d segundos",
+				minPauseSeconds, maxPauseSecondsLimit))
+		}
+	}
+
+	if len(problems) > 0 {
+		return settings, &SettingsError{Problems: problems}
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,64 @@
+package room
+
+import (
+	"strings"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+)
+
+// TestValidateDefaultSettings verifica que la configuración por defecto sea válida
+func TestValidateDefaultSettings(t *testing.T) {
+	settings, err := ValidateSettings(DefaultSettings())
+	if err != nil {
+		t.Fatalf("La configuración por defecto debería ser válida: %v", err)
+	}
+
+	if settings.BoardSize != game.ClassicBoardSize {
+		t.Errorf("Tamaño de tablero efectivo incorrecto, esperado %d, obtenido %d", game.ClassicBoardSize, settings.BoardSize)
+	}
+}
+
+// TestValidateSettingsVariantDefaults verifica que se resuelva el tamaño por defecto de la variante
+func TestValidateSettingsVariantDefaults(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Variant = game.VariantGomoku
+	settings.PreferredSymbol = "o"
+
+	effective, err := ValidateSettings(settings)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if effective.BoardSize != 15 {
+		t.Errorf("Tamaño de tablero efectivo incorrecto, esperado 15, obtenido %d", effective.BoardSize)
+	}
+	if effective.PreferredSymbol != "O" {
+		t.Errorf("Símbolo preferido incorrecto, esperado 'O', obtenido '%s'", effective.PreferredSymbol)
+	}
+}
+
+// TestValidateSettingsErrors verifica que se informen todos los problemas encontrados
+func TestValidateSettingsErrors(t *testing.T) {
+	settings := DefaultSettings()
+	settings.BoardSize = 5
+	settings.Visibility = "hidden"
+	settings.TimeControl.IncrementSeconds = 3
+	settings.DisconnectGrace = -1
+
+	_, err := ValidateSettings(settings)
+	if err == nil {
+		t.Fatal("Se esperaba error de validación")
+	}
+
+	settingsErr, ok := err.(*SettingsError)
+	if !ok {
+		t.Fatalf("Se esperaba *SettingsError, se obtuvo %T", err)
+	}
+	if len(settingsErr.Problems) != 4 {
+		t.Errorf("Se esperaban 4 problemas, se obtuvieron %d: %v", len(settingsErr.Problems), settingsErr.Problems)
+	}
+	if !strings.Contains(err.Error(), "boardSize") {
+		t.Errorf("El mensaje debería mencionar boardSize: %s", err.Error())
+	}
+}
@@ -0,0 +1,179 @@
+package room
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/internal/interfaces"
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// restoreGrace es el plazo mínimo que tienen los jugadores para volver a una partida
+// restaurada tras un reinicio, aunque la sala tenga un tiempo de gracia menor
+const restoreGrace = 2 * time.Minute
+
+// Snapshot es la copia serializable de una sala que se guarda para restaurarla tras un reinicio
+type Snapshot struct {
+	ID            string                   `json:"id"`
+	Code          string                   `json:"code"`
+	Settings      models.RoomSettings      `json:"settings"`
+	State         State                    `json:"state"`
+	HostID        string                   `json:"hostId"`
+	CreatorSymbol string                   `json:"creatorSymbol"`
+	Game          *game.GameState          `json:"game"`
+	Clocks        map[string]int64         `json:"clocks,omitempty"` // Milisegundos restantes por símbolo
+	Locked        bool                     `json:"locked"`
+	Banned        []string                 `json:"banned,omitempty"`
+	PausesUsed    int                      `json:"pausesUsed"`
+	LastResult    *models.GameOverResponse `json:"lastResult,omitempty"`
+	Events        []roomlog.Event          `json:"events,omitempty"`
+	Version       uint64                   `json:"version"`
+	SavedAt       time.Time                `json:"savedAt"`
+}
+
+// Snapshot pide al bucle de la sala una copia de su estado. Devuelve false si la sala ya se cerró
+func (r *Room) Snapshot() (Snapshot, bool) {
+	reply := make(chan Snapshot, 1)
+
+	select {
+	case r.snapshotReq <- reply:
+	case <-r.ctx.Done():
+		return Snapshot{}, false
+	}
+
+	select {
+	case snap := <-reply:
+		return snap, true
+	case <-r.ctx.Done():
+		return Snapshot{}, false
+	}
+}
+
+// snapshot copia el estado de la sala. Solo se llama desde el bucle de la sala
+func (r *Room) snapshot() Snapshot {
+	gameState := *r.GameState
+	gameState.Board = r.GameState.Board.Copy()
+	gameState.PlayerSymbols = make(map[string]string, len(r.GameState.PlayerSymbols))
+	for playerID, symbol := range r.GameState.PlayerSymbols {
+		gameState.PlayerSymbols[playerID] = symbol
+	}
+
+	banned := make([]string, 0, len(r.banned))
+	for playerID := range r.banned {
+		banned = append(banned, playerID)
+	}
+	sort.Strings(banned)
+
+	return Snapshot{
+		ID:            r.ID,
+		Code:          r.Code,
+		Settings:      r.Settings,
+		State:         r.state,
+		HostID:        r.HostID,
+		CreatorSymbol: r.creatorSymbol,
+		Game:          &gameState,
+		Clocks:        r.clockMillis(),
+		Locked:        r.locked,
+		Banned:        banned,
+		PausesUsed:    r.pausesUsed,
+		LastResult:    r.lastResult,
+		Events:        r.log.Events(),
+		Version:       r.version,
+		SavedAt:       time.Now(),
+	}
+}
+
+// RestoreRoom reconstruye una sala a partir de una copia guardada. Nadie está conectado
+// al restaurarla: las partidas en curso quedan en pausa y sus jugadores disponen de un
+// tiempo de gracia para volver con su token de sesión. El ready-check y el sorteo no se
+// restauran; esas salas vuelven a esperar rival con su creador. Si la copia incluye el
+// registro de eventos, la partida se reconstruye reproduciéndolo
+func RestoreRoom(snap Snapshot, hub interfaces.Hub, parentCtx context.Context) *Room {
+	r := NewRoomWithSettings(snap.ID, snap.Settings, hub, parentCtx)
+	r.Code = snap.Code
+	r.HostID = snap.HostID
+	r.locked = snap.Locked
+	r.pausesUsed = snap.PausesUsed
+	r.lastResult = snap.LastResult
+	r.version = snap.Version
+
+	if snap.CreatorSymbol != "" {
+		r.creatorSymbol = snap.CreatorSymbol
+	}
+	for _, playerID := range snap.Banned {
+		r.banned[playerID] = true
+	}
+
+	if snap.Game != nil {
+		r.GameState = snap.Game
+		if r.GameState.PlayerSymbols == nil {
+			r.GameState.PlayerSymbols = make(map[string]string)
+		}
+	}
+
+	if len(snap.Events) > 0 {
+		replayed, err := roomlog.ReplayRoom(snap.Events)
+		if err != nil {
+			// Se conserva el tablero guardado y el registro empieza de nuevo
+			logger.Warn("No se pudo reproducir el registro de la sala", logger.Fields{
+				"roomID": snap.ID,
+				"error":  err.Error(),
+			})
+		} else {
+			r.GameState = replayed.Game
+			r.gameID = replayed.GameID
+			r.moveNumber = replayed.Moves
+			r.log = roomlog.NewLog(snap.Events)
+			r.createdAt = snap.Events[0].At
+		}
+	}
+
+	// El reloj se restaura detenido y arranca al reanudarse la partida
+	if snap.Clocks != nil && snap.Settings.TimeControl.InitialSeconds > 0 {
+		r.Clock = game.NewClock(0, time.Duration(snap.Settings.TimeControl.IncrementSeconds)*time.Second)
+		for symbol, millis := range snap.Clocks {
+			r.Clock.Remaining[symbol] = time.Duration(millis) * time.Millisecond
+		}
+	}
+
+	switch snap.State {
+	case StatePlaying, StatePaused:
+		r.state = StatePaused
+		r.record(roomlog.Event{Type: roomlog.EventStateChanged, State: string(r.state), Reason: "room_restored"})
+
+		grace := time.Duration(snap.Settings.DisconnectGrace) * time.Second
+		if grace < restoreGrace {
+			grace = restoreGrace
+		}
+		for playerID := range r.GameState.PlayerSymbols {
+			r.disconnected[playerID] = time.Now().Add(grace)
+			r.record(roomlog.Event{Type: roomlog.EventDisconnected, PlayerID: playerID})
+			r.startTimer(graceTimerKind(playerID), grace)
+		}
+
+	case StateFinished:
+		r.state = StateFinished
+
+	default:
+		r.state = StateWaiting
+		r.record(roomlog.Event{Type: roomlog.EventStateChanged, State: string(r.state), Reason: "room_restored"})
+		creatorID := r.playerIDForSymbol(r.creatorSymbol)
+		for playerID := range r.GameState.PlayerSymbols {
+			if playerID != creatorID {
+				r.unseatPlayer(playerID, "room_restored")
+			}
+		}
+	}
+
+	logger.Info("Sala restaurada", logger.Fields{
+		"roomID":  r.ID,
+		"state":   string(r.state),
+		"players": len(r.GameState.PlayerSymbols),
+	})
+
+	return r
+}
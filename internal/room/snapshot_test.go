@@ -0,0 +1,72 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// TestRoomSnapshotRestore verifica que una partida guardada se pueda restaurar y continuar
+func TestRoomSnapshotRestore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.TimeControl = models.TimeControl{InitialSeconds: 60}
+	r := NewRoomWithSettings("persist-room", settings, nil, ctx)
+	r.Code = "K7M4PQ"
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	waitForMessage(t, p2, "GAME_START")
+
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 1, Col: 1}}
+	waitForMessage(t, p2, "GAME_UPDATE")
+
+	snap, ok := r.Snapshot()
+	if !ok {
+		t.Fatal("No se obtuvo la copia de la sala")
+	}
+	cancel()
+
+	// La copia debe sobrevivir a la serialización
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("Error serializando la copia: %v", err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error deserializando la copia: %v", err)
+	}
+
+	restoreCtx, restoreCancel := context.WithCancel(context.Background())
+	defer restoreCancel()
+
+	restored := RestoreRoom(decoded, nil, restoreCtx)
+	if restored.state != StatePaused || len(restored.disconnected) != 2 {
+		t.Fatalf("La partida restaurada debería quedar en pausa esperando a ambos jugadores")
+	}
+	if restored.Code != "K7M4PQ" || restored.GameState.Board[1][1] != "X" {
+		t.Errorf("Estado restaurado incorrecto")
+	}
+	go restored.Run()
+
+	back1, back2 := newFakeClient("p1"), newFakeClient("p2")
+	restored.Register <- back1
+	start := waitForMessage(t, back1, "GAME_START")
+	if start["currentTurn"] != "O" {
+		t.Errorf("Turno restaurado incorrecto: %v", start["currentTurn"])
+	}
+
+	restored.Register <- back2
+	changed := waitForMessage(t, back1, "ROOM_STATE_CHANGED")
+	if changed["state"] != string(StatePlaying) {
+		t.Errorf("La partida debería reanudarse con ambos jugadores, estado '%v'", changed["state"])
+	}
+
+	restored.ReceiveMove <- &models.PlayerMove{Client: back2, MoveData: models.MovePayload{Row: 0, Col: 0}}
+	waitForMessage(t, back1, "GAME_UPDATE")
+}
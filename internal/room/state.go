@@ -0,0 +1,142 @@
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/logger"
+	"nvivas/backend/tictactoe-go-server/internal/roomlog"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// State es la fase del ciclo de vida de una sala
+type State string
+
+const (
+	StateWaiting    State = "waiting"     // Esperando jugadores
+	StateReadyCheck State = "ready_check" // Confirmación de jugadores o sorteo del primer movimiento
+	StatePlaying    State = "playing"     // Partida en curso
+	StatePaused     State = "paused"      // Partida detenida temporalmente
+	StateFinished   State = "finished"    // Partida terminada, la sala admite un nuevo rival
+	StateClosed     State = "closed"      // Sala cerrada, estado final
+)
+
+// transitions define los cambios de estado permitidos
+var transitions = map[State][]State{
+	StateWaiting:    {StateReadyCheck, StatePlaying, StateClosed},
+	StateReadyCheck: {StateWaiting, StatePlaying, StateClosed},
+	StatePlaying:    {StatePaused, StateFinished, StateClosed},
+	StatePaused:     {StatePlaying, StateFinished, StateClosed},
+	StateFinished:   {StateWaiting, StateClosed},
+	StateClosed:     {},
+}
+
+// CanTransitionTo indica si la sala puede pasar del estado s al estado next
+func (s State) CanTransitionTo(next State) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError se devuelve al intentar un cambio de estado no permitido
+type TransitionError struct {
+	From State
+	To   State
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("transición de sala no permitida: %s -> %s", e.From, e.To)
+}
+
+// transition cambia el estado de la sala y emite ROOM_STATE_CHANGED a todos sus miembros.
+// Los cambios no permitidos se rechazan sin modificar el estado
+func (r *Room) transition(next State, reason string) error {
+	previous := r.state
+	if previous == next {
+		return nil
+	}
+
+	if !previous.CanTransitionTo(next) {
+		err := &TransitionError{From: previous, To: next}
+		logger.Warn("Transición de sala rechazada", logger.Fields{
+			"roomID": r.ID,
+			"from":   string(previous),
+			"to":     string(next),
+			"reason": reason,
+		})
+		return err
+	}
+
+	r.state = next
+	r.stateSince = time.Now()
+	r.record(roomlog.Event{Type: roomlog.EventStateChanged, State: string(next), Reason: reason})
+
+	stateMsg := models.RoomStateChangedResponse{
+		Type:          "ROOM_STATE_CHANGED",
+		RoomID:        r.ID,
+		State:         string(next),
+		PreviousState: string(previous),
+		Reason:        reason,
+	}
+	msgBytes, _ := json.Marshal(stateMsg)
+	r.broadcastToAll(msgBytes, "ROOM_STATE_CHANGED")
+
+	logger.Info("Estado de sala cambiado", logger.Fields{
+		"roomID": r.ID,
+		"from":   string(previous),
+		"to":     string(next),
+		"reason": reason,
+	})
+
+	return nil
+}
+
+// Info devuelve una copia del resumen público de la sala. Se puede llamar desde
+// fuera del bucle de la sala (por ejemplo, desde el Hub)
+func (r *Room) Info() models.RoomInfo {
+	r.infoMu.RLock()
+	defer r.infoMu.RUnlock()
+
+	info := r.info
+	info.Players = append([]string(nil), r.info.Players...)
+	return info
+}
+
+// refreshInfo actualiza el resumen público de la sala. Solo se llama desde el bucle de la sala
+func (r *Room) refreshInfo() {
+	// Jugadores con asiento en la sala
+	players := make([]string, 0, len(r.GameState.PlayerSymbols))
+	for playerID := range r.GameState.PlayerSymbols {
+		players = append(players, playerID)
+	}
+	sort.Strings(players)
+
+	info := models.RoomInfo{
+		RoomID:     r.ID,
+		RoomCode:   r.Code,
+		Players:    players,
+		IsFull:     len(players) >= 2,
+		Settings:   r.Settings,
+		State:      string(r.state),
+		HostID:     r.HostID,
+		Spectators: len(r.Spectators),
+		CreatedAt:  r.createdAt.UnixMilli(),
+		Profiles:   r.playerProfiles(players),
+	}
+
+	changed := !reflect.DeepEqual(info, r.info)
+
+	r.infoMu.Lock()
+	r.info = info
+	r.infoMu.Unlock()
+
+	if changed {
+		r.publishInfo(info)
+	}
+}
@@ -0,0 +1,98 @@
+package room
+
+import (
+	"context"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// TestStateTransitions verifica la tabla de transiciones permitidas
+func TestStateTransitions(t *testing.T) {
+	tests := []struct {
+		from, to State
+		allowed  bool
+	}{
+		{StateWaiting, StateReadyCheck, true},
+		{StateWaiting, StatePlaying, true},
+		{StateReadyCheck, StateWaiting, true},
+		{StatePlaying, StatePaused, true},
+		{StatePaused, StatePlaying, true},
+		{StatePlaying, StateFinished, true},
+		{StateFinished, StateWaiting, true},
+		{StateFinished, StateClosed, true},
+		{StateWaiting, StateFinished, false},
+		{StateWaiting, StatePaused, false},
+		{StateFinished, StatePlaying, false},
+		{StateClosed, StateWaiting, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+			t.Errorf("%s -> %s: esperado %v, obtenido %v", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+}
+
+// TestRoomRejectsInvalidTransition verifica que un cambio no permitido no altere el estado
+func TestRoomRejectsInvalidTransition(t *testing.T) {
+	r := NewRoom("state-room", nil, context.Background())
+
+	if err := r.transition(StateFinished, "test"); err == nil {
+		t.Fatal("Se esperaba un error al pasar de waiting a finished")
+	}
+	if r.state != StateWaiting {
+		t.Errorf("Estado incorrecto, esperado %s, obtenido %s", StateWaiting, r.state)
+	}
+}
+
+// TestRoomLifecycle verifica los eventos ROOM_STATE_CHANGED y el estado publicado en Info
+func TestRoomLifecycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.ReadyCheck = true
+	settings.ReadyTimeout = 30
+	r := NewRoomWithSettings("lifecycle-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+
+	changed := waitForMessage(t, p1, "ROOM_STATE_CHANGED")
+	if changed["state"] != string(StateReadyCheck) || changed["previousState"] != string(StateWaiting) {
+		t.Errorf("Transición incorrecta: %v", changed)
+	}
+
+	r.Submit(&Command{Client: p1, Type: "PLAYER_READY"})
+	r.Submit(&Command{Client: p2, Type: "PLAYER_READY"})
+
+	changed = waitForMessage(t, p1, "ROOM_STATE_CHANGED")
+	if changed["state"] != string(StatePlaying) {
+		t.Errorf("Se esperaba el estado playing, obtenido %v", changed["state"])
+	}
+
+	// Ganar la partida con X en la primera fila
+	moves := []struct {
+		client *fakeClient
+		row    int
+		col    int
+	}{
+		{p1, 0, 0}, {p2, 1, 0}, {p1, 0, 1}, {p2, 1, 1}, {p1, 0, 2},
+	}
+	for _, m := range moves {
+		r.ReceiveMove <- &models.PlayerMove{Client: m.client, MoveData: models.MovePayload{Row: m.row, Col: m.col}}
+	}
+
+	changed = waitForMessage(t, p1, "ROOM_STATE_CHANGED")
+	if changed["state"] != string(StateFinished) || changed["reason"] != "win" {
+		t.Errorf("Se esperaba el estado finished por victoria, obtenido %v", changed)
+	}
+	waitForMessage(t, p1, "GAME_OVER")
+
+	if info := r.Info(); info.State != string(StateFinished) || len(info.Players) != 2 {
+		t.Errorf("Resumen de sala incorrecto: %+v", info)
+	}
+}
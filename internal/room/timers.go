@@ -0,0 +1,70 @@
+package room
+
+import (
+	"time"
+)
+
+// Tipos de temporizador de la sala
+const (
+	// timerClock se dispara cuando se agota el reloj del jugador en turno
+	timerClock = "clock"
+
+	// timerReady se dispara cuando vence el plazo del ready-check
+	timerReady = "ready"
+
+	// timerCoinFlip se dispara cuando vence una fase del sorteo commit-reveal
+	timerCoinFlip = "coinflip"
+
+	// timerPause se dispara cuando una pausa de mutuo acuerdo alcanza su duración máxima
+	timerPause = "pause"
+)
+
+// roomTimer es el evento que recibe el bucle de la sala cuando vence un temporizador
+type roomTimer struct {
+	kind string
+	gen  uint64
+}
+
+// startTimer programa (o reprograma) el temporizador kind. Al vencer, el evento
+// se entrega a través del canal timerFired para procesarlo dentro de Run
+func (r *Room) startTimer(kind string, d time.Duration) {
+	r.stopTimer(kind)
+
+	r.timerGen++
+	gen := r.timerGen
+	r.timerGens[kind] = gen
+
+	r.timers[kind] = time.AfterFunc(d, func() {
+		select {
+		case r.timerFired <- roomTimer{kind: kind, gen: gen}:
+		case <-r.ctx.Done():
+		}
+	})
+}
+
+// stopTimer cancela el temporizador kind si está programado
+func (r *Room) stopTimer(kind string) {
+	if t, ok := r.timers[kind]; ok {
+		t.Stop()
+		delete(r.timers, kind)
+		delete(r.timerGens, kind)
+	}
+}
+
+// stopAllTimers cancela todos los temporizadores de la sala
+func (r *Room) stopAllTimers() {
+	for kind := range r.timers {
+		r.stopTimer(kind)
+	}
+}
+
+// acceptTimer indica si el evento corresponde al temporizador vigente de su tipo.
+// Los eventos de temporizadores cancelados o reprogramados se descartan
+func (r *Room) acceptTimer(ev roomTimer) bool {
+	if r.timerGens[ev.kind] != ev.gen {
+		return false
+	}
+	delete(r.timers, ev.kind)
+	delete(r.timerGens, ev.kind)
+	return true
+}
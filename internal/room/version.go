@@ -0,0 +1,57 @@
+package room
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// stamp añade la versión actual de la sala a un mensaje JSON ya serializado.
+// Los mensajes que no son objetos JSON se devuelven sin cambios
+func (r *Room) stamp(msgBytes []byte) []byte {
+	if len(msgBytes) < 2 || msgBytes[0] != '{' {
+		return msgBytes
+	}
+
+	prefix := `{"version":` + strconv.FormatUint(r.version, 10)
+	stamped := make([]byte, 0, len(prefix)+len(msgBytes))
+	stamped = append(stamped, prefix...)
+	if msgBytes[1] != '}' {
+		stamped = append(stamped, ',')
+	}
+	return append(stamped, msgBytes[1:]...)
+}
+
+// handleResync envía al cliente el estado completo de la sala para que recupere
+// los mensajes que haya perdido
+func (r *Room) handleResync(cmd *Command) {
+	disconnected := make([]string, 0, len(r.disconnected))
+	for playerID := range r.disconnected {
+		disconnected = append(disconnected, playerID)
+	}
+	sort.Strings(disconnected)
+
+	resyncMsg := models.ResyncResponse{
+		Type:         "RESYNC",
+		RoomID:       r.ID,
+		RoomCode:     r.Code,
+		State:        string(r.state),
+		Settings:     r.Settings,
+		GameID:       r.gameID,
+		MoveNumber:   r.moveNumber,
+		Board:        getBoardJSON(r.GameState.Board),
+		CurrentTurn:  r.GameState.CurrentTurnSymbol,
+		Players:      r.GameState.PlayerSymbols,
+		Clocks:       r.clockMillis(),
+		HostID:       r.HostID,
+		Locked:       r.locked,
+		Spectators:   len(r.Spectators),
+		Disconnected: disconnected,
+		Result:       r.lastResult,
+		Profiles:     r.seatedProfiles(),
+	}
+	msgBytes, _ := json.Marshal(resyncMsg)
+	r.sendToClient(cmd.Client, msgBytes, "RESYNC")
+}
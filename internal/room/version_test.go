@@ -0,0 +1,103 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+func TestStamp(t *testing.T) {
+	r := &Room{version: 7}
+
+	cases := map[string]string{
+		`{"type":"GAME_UPDATE"}`: `{"version":7,"type":"GAME_UPDATE"}`,
+		`{}`:                     `{"version":7}`,
+		`[1,2]`:                  `[1,2]`,
+	}
+	for in, want := range cases {
+		if got := string(r.stamp([]byte(in))); got != want {
+			t.Errorf("stamp(%s) = %s, esperado %s", in, got, want)
+		}
+	}
+}
+
+// TestRoomVersionAndResync verifica que los mensajes de sala lleven una versión creciente
+// y que RESYNC devuelva el estado completo con la versión actual
+func TestRoomVersionAndResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	settings.PreferredSymbol = "X"
+	r := NewRoomWithSettings("version-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	r.Register <- p2
+	start := waitForMessage(t, p1, "GAME_START")
+
+	r.ReceiveMove <- &models.PlayerMove{Client: p1, MoveData: models.MovePayload{Row: 2, Col: 2}}
+	update := waitForMessage(t, p1, "GAME_UPDATE")
+
+	startVersion, _ := start["version"].(float64)
+	updateVersion, _ := update["version"].(float64)
+	if startVersion == 0 || updateVersion != startVersion+1 {
+		t.Errorf("Versiones incorrectas: GAME_START %v, GAME_UPDATE %v", start["version"], update["version"])
+	}
+
+	payload, _ := json.Marshal(struct{}{})
+	r.Submit(&Command{Client: p2, Type: "RESYNC", Payload: payload})
+	resync := waitForMessage(t, p2, "RESYNC")
+
+	if resync["version"] != update["version"] {
+		t.Errorf("RESYNC debería llevar la versión actual %v, obtenida %v", update["version"], resync["version"])
+	}
+	if resync["state"] != string(StatePlaying) || resync["currentTurn"] != "O" || resync["gameId"] != start["gameId"] {
+		t.Errorf("Estado de RESYNC incorrecto: %v", resync)
+	}
+	board, _ := resync["board"].([]interface{})
+	if len(board) != 3 || board[2].([]interface{})[2] != "X" {
+		t.Errorf("Tablero de RESYNC incorrecto: %v", resync["board"])
+	}
+}
+
+// TestPlayerJoinedAdvancesVersion verifica que los mensajes que no llegan a todos, como
+// PLAYER_JOINED, también avancen la versión
+func TestPlayerJoinedAdvancesVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, _ := ValidateSettings(DefaultSettings())
+	r := NewRoomWithSettings("joined-version-room", settings, nil, ctx)
+	go r.Run()
+
+	p1, p2 := newFakeClient("p1"), newFakeClient("p2")
+	r.Register <- p1
+	waiting := waitForMessage(t, p1, "WAITING_FOR_OPPONENT")
+	last, _ := waiting["version"].(float64)
+
+	r.Register <- p2
+	for {
+		select {
+		case msgBytes := <-p1.send:
+			var msg map[string]interface{}
+			if err := json.Unmarshal(msgBytes, &msg); err != nil {
+				t.Fatalf("Mensaje inválido: %v", err)
+			}
+			version, _ := msg["version"].(float64)
+			if msg["type"] == "PLAYER_JOINED" {
+				if version != last+1 {
+					t.Errorf("PLAYER_JOINED debería llevar la versión %v, obtenida %v", last+1, version)
+				}
+				return
+			}
+			last = version
+		case <-time.After(2 * time.Second):
+			t.Fatal("No se recibió PLAYER_JOINED")
+		}
+	}
+}
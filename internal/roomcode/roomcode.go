@@ -0,0 +1,79 @@
+package roomcode
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const (
+	// Alphabet es un alfabeto estilo Crockford sin caracteres ambiguos
+	// (se excluyen 0, O, 1, I, L y U) para que los códigos se puedan dictar
+	Alphabet = "23456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// Length es la cantidad de caracteres de un código de sala
+	Length = 6
+
+	// maxAttempts limita los reintentos ante colisiones
+	maxAttempts = 32
+)
+
+// ErrNoCodeAvailable se devuelve cuando no se encontró un código libre
+var ErrNoCodeAvailable = errors.New("no se pudo generar un código de sala libre")
+
+// Generate crea un código aleatorio que no esté en uso según taken
+func Generate(taken func(code string) bool) (string, error) {
+	max := big.NewInt(int64(len(Alphabet)))
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var sb strings.Builder
+		sb.Grow(Length)
+
+		for i := 0; i < Length; i++ {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteByte(Alphabet[n.Int64()])
+		}
+
+		code := sb.String()
+		if taken == nil || !taken(code) {
+			return code, nil
+		}
+	}
+
+	return "", ErrNoCodeAvailable
+}
+
+// Normalize convierte una entrada del usuario a la forma canónica del código:
+// mayúsculas y sin espacios ni guiones
+func Normalize(input string) string {
+	var sb strings.Builder
+	sb.Grow(len(input))
+
+	for _, r := range strings.ToUpper(input) {
+		if r == ' ' || r == '-' || r == '\t' {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// IsValid indica si el código (ya normalizado) tiene el formato esperado
+func IsValid(code string) bool {
+	if len(code) != Length {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(Alphabet, code[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
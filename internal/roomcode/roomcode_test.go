@@ -0,0 +1,67 @@
+package roomcode
+
+import (
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	code, err := Generate(nil)
+	if err != nil {
+		t.Fatalf("Error inesperado generando código: %v", err)
+	}
+
+	if !IsValid(code) {
+		t.Errorf("Código generado inválido: '%s'", code)
+	}
+}
+
+func TestGenerateAvoidsCollisions(t *testing.T) {
+	used := make(map[string]bool)
+
+	for i := 0; i < 200; i++ {
+		code, err := Generate(func(c string) bool { return used[c] })
+		if err != nil {
+			t.Fatalf("Error inesperado generando código: %v", err)
+		}
+		if used[code] {
+			t.Fatalf("Código repetido: '%s'", code)
+		}
+		used[code] = true
+	}
+}
+
+func TestGenerateExhausted(t *testing.T) {
+	_, err := Generate(func(string) bool { return true })
+	if err != ErrNoCodeAvailable {
+		t.Errorf("Se esperaba ErrNoCodeAvailable, se obtuvo %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"abc234":   "ABC234",
+		" abc-234": "ABC234",
+		"AB C2 34": "ABC234",
+	}
+
+	for input, expected := range cases {
+		if got := Normalize(input); got != expected {
+			t.Errorf("Normalize(%q) = %q, se esperaba %q", input, got, expected)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	if IsValid("ABC23") {
+		t.Error("Un código corto no debería ser válido")
+	}
+	if IsValid("ABCO23") {
+		t.Error("Un código con 'O' no debería ser válido")
+	}
+	if IsValid("ABC123") {
+		t.Error("Un código con '1' no debería ser válido")
+	}
+	if !IsValid("ABC234") {
+		t.Error("'ABC234' debería ser válido")
+	}
+}
@@ -0,0 +1,162 @@
+// Package roomlist filtra, ordena y pagina la lista de salas públicas de LIST_ROOMS
+package roomlist
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Criterios de orden
+const (
+	SortNewest     = "newest"     // Más recientes primero (por defecto)
+	SortSpectators = "spectators" // Más espectadores primero
+	SortRating     = "rating"     // Mayor puntuación media de los jugadores primero
+)
+
+// Tamaño de las páginas
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor indica un cursor que no se puede interpretar o que es de otro orden
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// cursor es la posición tras la última sala de una página. Guarda la clave de orden
+// y el ID de la sala, así que sigue siendo válido aunque se abran o cierren salas
+type cursor struct {
+	Sort   string  `json:"s"`
+	Key    float64 `json:"k"`
+	RoomID string  `json:"id"`
+}
+
+// Validate comprueba la consulta y devuelve la consulta efectiva, con los valores por defecto
+func Validate(query models.ListRoomsPayload) (models.ListRoomsPayload, error) {
+	if query.Sort == "" {
+		query.Sort = SortNewest
+	}
+	switch query.Sort {
+	case SortNewest, SortSpectators, SortRating:
+	default:
+		return query, fmt.Errorf("sort '%s' no soportado (newest, spectators o rating)", query.Sort)
+	}
+
+	if query.Variant != "" {
+		if _, ok := game.LookupVariant(query.Variant); !ok {
+			return query, fmt.Errorf("variant '%s' no soportada", query.Variant)
+		}
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = DefaultLimit
+	}
+	if query.Limit > MaxLimit {
+		query.Limit = MaxLimit
+	}
+
+	if query.Cursor != "" {
+		if _, err := decodeCursor(query.Cursor, query.Sort); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
+
+// Page devuelve las salas que cumplen los filtros, ordenadas, a partir del cursor de la
+// consulta. nextCursor está vacío si no hay más salas. La consulta debe venir validada
+func Page(rooms []models.RoomInfo, query models.ListRoomsPayload) (page []models.RoomInfo, nextCursor string) {
+	matching := make([]models.RoomInfo, 0, len(rooms))
+	for _, info := range rooms {
+		if matches(info, query) {
+			matching = append(matching, info)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return before(sortKey(matching[i], query.Sort), matching[i].RoomID, sortKey(matching[j], query.Sort), matching[j].RoomID)
+	})
+
+	start := 0
+	if query.Cursor != "" {
+		after, _ := decodeCursor(query.Cursor, query.Sort)
+		start = sort.Search(len(matching), func(i int) bool {
+			return before(after.Key, after.RoomID, sortKey(matching[i], query.Sort), matching[i].RoomID)
+		})
+	}
+
+	end := start + query.Limit
+	if end >= len(matching) {
+		return append([]models.RoomInfo{}, matching[start:]...), ""
+	}
+
+	page = append([]models.RoomInfo{}, matching[start:end]...)
+	last := page[len(page)-1]
+	return page, encodeCursor(cursor{Sort: query.Sort, Key: sortKey(last, query.Sort), RoomID: last.RoomID})
+}
+
+// matches indica si una sala cumple los filtros de la consulta
+func matches(info models.RoomInfo, query models.ListRoomsPayload) bool {
+	if query.Variant != "" && info.Settings.Variant != query.Variant {
+		return false
+	}
+	if query.HasOpenSeat != nil && *query.HasOpenSeat == info.IsFull {
+		return false
+	}
+	if query.Rated != nil && *query.Rated != info.Settings.Rated {
+		return false
+	}
+	if query.TimeControl != nil && *query.TimeControl != info.Settings.TimeControl {
+		return false
+	}
+	if query.Spectatable != nil {
+		spectatable := info.Settings.AllowSpectators && info.Spectators < info.Settings.MaxSpectators
+		if *query.Spectatable != spectatable {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKey devuelve la clave de orden de una sala; las claves mayores van primero
+func sortKey(info models.RoomInfo, sortBy string) float64 {
+	switch sortBy {
+	case SortSpectators:
+		return float64(info.Spectators)
+	case SortRating:
+		return info.Rating
+	default:
+		return float64(info.CreatedAt)
+	}
+}
+
+// before indica si la sala (keyA, idA) va antes que (keyB, idB): clave descendente
+// y, a igualdad, ID ascendente
+func before(keyA float64, idA string, keyB float64, idB string) bool {
+	if keyA != keyB {
+		return keyA > keyB
+	}
+	return idA < idB
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s, sortBy string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sortBy || c.RoomID == "" {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,150 @@
+package roomlist
+
+import (
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+func room(id string, createdAt int64, spectators int, rating float64, settings models.RoomSettings, full bool) models.RoomInfo {
+	return models.RoomInfo{
+		RoomID:     id,
+		IsFull:     full,
+		Spectators: spectators,
+		CreatedAt:  createdAt,
+		Rating:     rating,
+		Settings:   settings,
+	}
+}
+
+func ids(rooms []models.RoomInfo) []string {
+	out := make([]string, 0, len(rooms))
+	for _, r := range rooms {
+		out = append(out, r.RoomID)
+	}
+	return out
+}
+
+func sameIDs(got []models.RoomInfo, want ...string) bool {
+	g := ids(got)
+	if len(g) != len(want) {
+		return false
+	}
+	for i := range g {
+		if g[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func validated(t *testing.T, query models.ListRoomsPayload) models.ListRoomsPayload {
+	t.Helper()
+	q, err := Validate(query)
+	if err != nil {
+		t.Fatalf("Consulta inválida %+v: %v", query, err)
+	}
+	return q
+}
+
+func sampleRooms() []models.RoomInfo {
+	classic := models.RoomSettings{Variant: "classic", AllowSpectators: true, MaxSpectators: 2}
+	rated := classic
+	rated.Rated = true
+	blitz := classic
+	blitz.TimeControl = models.TimeControl{InitialSeconds: 60, IncrementSeconds: 1}
+	gomoku := models.RoomSettings{Variant: "gomoku"}
+
+	return []models.RoomInfo{
+		room("a", 100, 0, 1500, classic, false),
+		room("b", 300, 2, 1700, rated, true),
+		room("c", 200, 1, 1600, blitz, false),
+		room("d", 400, 0, 0, gomoku, false),
+	}
+}
+
+// TestPageFilters verifica cada filtro por separado
+func TestPageFilters(t *testing.T) {
+	yes, no := true, false
+	rooms := sampleRooms()
+
+	cases := []struct {
+		name  string
+		query models.ListRoomsPayload
+		want  []string
+	}{
+		{"sin filtros", models.ListRoomsPayload{}, []string{"d", "b", "c", "a"}},
+		{"variante", models.ListRoomsPayload{Variant: "gomoku"}, []string{"d"}},
+		{"con asiento libre", models.ListRoomsPayload{HasOpenSeat: &yes}, []string{"d", "c", "a"}},
+		{"llenas", models.ListRoomsPayload{HasOpenSeat: &no}, []string{"b"}},
+		{"puntuadas", models.ListRoomsPayload{Rated: &yes}, []string{"b"}},
+		{"control de tiempo", models.ListRoomsPayload{TimeControl: &models.TimeControl{InitialSeconds: 60, IncrementSeconds: 1}}, []string{"c"}},
+		{"admiten espectadores", models.ListRoomsPayload{Spectatable: &yes}, []string{"c", "a"}},
+	}
+	for _, c := range cases {
+		page, next := Page(rooms, validated(t, c.query))
+		if !sameIDs(page, c.want...) || next != "" {
+			t.Errorf("%s: obtenido %v (cursor %q), se esperaba %v", c.name, ids(page), next, c.want)
+		}
+	}
+}
+
+// TestPageSort verifica los criterios de orden y el desempate por ID
+func TestPageSort(t *testing.T) {
+	rooms := append(sampleRooms(), room("aa", 50, 1, 1600, models.RoomSettings{Variant: "classic"}, false))
+
+	if page, _ := Page(rooms, validated(t, models.ListRoomsPayload{Sort: SortSpectators})); !sameIDs(page, "b", "aa", "c", "a", "d") {
+		t.Errorf("Orden por espectadores incorrecto: %v", ids(page))
+	}
+	if page, _ := Page(rooms, validated(t, models.ListRoomsPayload{Sort: SortRating})); !sameIDs(page, "b", "aa", "c", "a", "d") {
+		t.Errorf("Orden por puntuación incorrecto: %v", ids(page))
+	}
+}
+
+// TestPageCursor verifica que recorrer las páginas devuelva cada sala una sola vez,
+// aunque entre páginas se abran y cierren salas
+func TestPageCursor(t *testing.T) {
+	rooms := sampleRooms()
+	query := validated(t, models.ListRoomsPayload{Limit: 2})
+
+	first, next := Page(rooms, query)
+	if !sameIDs(first, "d", "b") || next == "" {
+		t.Fatalf("Primera página incorrecta: %v (cursor %q)", ids(first), next)
+	}
+
+	// Entre páginas se cierra una sala ya vista y se abre otra más reciente
+	rooms = append(rooms[:3], room("e", 500, 0, 0, models.RoomSettings{Variant: "classic"}, false))
+
+	query.Cursor = next
+	second, next := Page(rooms, validated(t, query))
+	if !sameIDs(second, "c", "a") || next != "" {
+		t.Errorf("Segunda página incorrecta: %v (cursor %q)", ids(second), next)
+	}
+}
+
+// TestValidate verifica los valores por defecto y el rechazo de consultas inválidas
+func TestValidate(t *testing.T) {
+	q := validated(t, models.ListRoomsPayload{Limit: 1000})
+	if q.Sort != SortNewest || q.Limit != MaxLimit {
+		t.Errorf("Valores por defecto incorrectos: %+v", q)
+	}
+	if q := validated(t, models.ListRoomsPayload{}); q.Limit != DefaultLimit {
+		t.Errorf("Límite por defecto incorrecto: %d", q.Limit)
+	}
+
+	if _, err := Validate(models.ListRoomsPayload{Sort: "oldest"}); err == nil {
+		t.Error("Un orden desconocido debería rechazarse")
+	}
+	if _, err := Validate(models.ListRoomsPayload{Variant: "chess"}); err == nil {
+		t.Error("Una variante desconocida debería rechazarse")
+	}
+	if _, err := Validate(models.ListRoomsPayload{Cursor: "%%%"}); err != ErrInvalidCursor {
+		t.Errorf("Se esperaba ErrInvalidCursor, obtenido %v", err)
+	}
+
+	// Un cursor de otro orden no sirve
+	_, next := Page(sampleRooms(), validated(t, models.ListRoomsPayload{Limit: 1}))
+	if _, err := Validate(models.ListRoomsPayload{Sort: SortRating, Cursor: next}); err != ErrInvalidCursor {
+		t.Errorf("Se esperaba ErrInvalidCursor con un cursor de otro orden, obtenido %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Package roomlog define el registro de eventos de una sala. Cada cambio de la sala se
+// anota como un evento inmutable, y el estado se reconstruye aplicando los eventos en orden
+package roomlog
+
+import (
+	"fmt"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// EventType identifica el tipo de un evento
+type EventType string
+
+const (
+	EventRoomCreated     EventType = "room_created"     // BoardSize, WinLength
+	EventPlayerJoined    EventType = "player_joined"    // PlayerID, Symbol
+	EventPlayerLeft      EventType = "player_left"      // PlayerID, Reason
+	EventDisconnected    EventType = "disconnected"     // PlayerID (conserva el asiento)
+	EventReconnected     EventType = "reconnected"      // PlayerID
+	EventSpectatorJoined EventType = "spectator_joined" // PlayerID
+	EventSpectatorLeft   EventType = "spectator_left"   // PlayerID, Reason
+	EventHostChanged     EventType = "host_changed"     // PlayerID (nuevo anfitrión), Reason
+	EventLockChanged     EventType = "lock_changed"     // Locked
+	EventStateChanged    EventType = "state_changed"    // State, Reason
+	EventGameStarted     EventType = "game_started"     // GameID, Symbol (quien mueve primero)
+	EventMoveApplied     EventType = "move_applied"     // PlayerID, Symbol, Move
+	EventGameOver        EventType = "game_over"        // GameID, PlayerID (ganador), Symbol (ganador), IsDraw, Reason
+	EventGameReset       EventType = "game_reset"       // Tablero nuevo conservando los asientos
+)
+
+// Event es un cambio registrado en una sala. Solo se rellenan los campos de su tipo
+type Event struct {
+	Seq       int64               `json:"seq"`
+	Type      EventType           `json:"type"`
+	At        time.Time           `json:"at"`
+	GameID    string              `json:"gameId,omitempty"`
+	PlayerID  string              `json:"playerId,omitempty"`
+	Symbol    string              `json:"symbol,omitempty"`
+	Move      *models.MovePayload `json:"move,omitempty"`
+	BoardSize int                 `json:"boardSize,omitempty"`
+	WinLength int                 `json:"winLength,omitempty"`
+	State     string              `json:"state,omitempty"`
+	Locked    bool                `json:"locked,omitempty"`
+	IsDraw    bool                `json:"isDraw,omitempty"`
+	Reason    string              `json:"reason,omitempty"`
+}
+
+// Log es un registro de eventos de solo anexado. No es seguro para uso concurrente:
+// lo usa únicamente el bucle de su sala
+type Log struct {
+	events []Event
+}
+
+// NewLog crea un registro, opcionalmente a partir de eventos ya guardados
+func NewLog(events []Event) *Log {
+	return &Log{events: append([]Event(nil), events...)}
+}
+
+// Append asigna número de secuencia (y hora, si no la tiene) al evento y lo anexa
+func (l *Log) Append(e Event) Event {
+	e.Seq = int64(len(l.events)) + 1
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	l.events = append(l.events, e)
+	return e
+}
+
+// Events devuelve una copia de los eventos registrados
+func (l *Log) Events() []Event {
+	return append([]Event(nil), l.events...)
+}
+
+// Len devuelve el número de eventos registrados
+func (l *Log) Len() int {
+	return len(l.events)
+}
+
+// RoomState es el estado de una sala derivado de su registro de eventos
+type RoomState struct {
+	Game         *game.GameState
+	GameID       string // Partida actual o última jugada
+	Moves        int    // Jugadas de esa partida
+	State        string
+	HostID       string
+	Locked       bool
+	Spectators   map[string]bool
+	Disconnected map[string]bool
+}
+
+// Replay reconstruye el estado de la partida aplicando los eventos en orden
+func Replay(events []Event) (*game.GameState, error) {
+	state, err := ReplayRoom(events)
+	if err != nil {
+		return nil, err
+	}
+	return state.Game, nil
+}
+
+// ReplayRoom reconstruye el estado completo de la sala aplicando los eventos en orden
+func ReplayRoom(events []Event) (*RoomState, error) {
+	state := &RoomState{
+		Spectators:   make(map[string]bool),
+		Disconnected: make(map[string]bool),
+	}
+	for _, e := range events {
+		if err := state.Apply(e); err != nil {
+			return nil, err
+		}
+	}
+	if state.Game == nil {
+		return nil, fmt.Errorf("el registro no contiene el evento %s", EventRoomCreated)
+	}
+	return state, nil
+}
+
+// Apply aplica un evento al estado
+func (s *RoomState) Apply(e Event) error {
+	if s.Game == nil && e.Type != EventRoomCreated {
+		return fmt.Errorf("evento %d (%s) antes de %s", e.Seq, e.Type, EventRoomCreated)
+	}
+
+	switch e.Type {
+	case EventRoomCreated:
+		s.Game = game.NewGameStateWithSize(e.BoardSize, e.WinLength)
+
+	case EventPlayerJoined:
+		s.Game.PlayerSymbols[e.PlayerID] = e.Symbol
+
+	case EventPlayerLeft:
+		delete(s.Game.PlayerSymbols, e.PlayerID)
+		delete(s.Disconnected, e.PlayerID)
+
+	case EventDisconnected:
+		s.Disconnected[e.PlayerID] = true
+
+	case EventReconnected:
+		delete(s.Disconnected, e.PlayerID)
+
+	case EventSpectatorJoined:
+		s.Spectators[e.PlayerID] = true
+
+	case EventSpectatorLeft:
+		delete(s.Spectators, e.PlayerID)
+
+	case EventHostChanged:
+		s.HostID = e.PlayerID
+
+	case EventLockChanged:
+		s.Locked = e.Locked
+
+	case EventStateChanged:
+		s.State = e.State
+
+	case EventGameStarted:
+		s.GameID = e.GameID
+		s.Moves = 0
+		s.Game.CurrentTurnSymbol = e.Symbol
+
+	case EventMoveApplied:
+		if e.Move == nil {
+			return fmt.Errorf("evento %d (%s) sin jugada", e.Seq, e.Type)
+		}
+		if err := game.ApplyMove(s.Game, e.Symbol, e.Move.Row, e.Move.Col); err != nil {
+			return fmt.Errorf("evento %d (%s): %w", e.Seq, e.Type, err)
+		}
+		s.Moves++
+
+	case EventGameOver:
+		s.Game.IsGameOver = true
+		s.Game.Winner = e.Symbol
+		s.Game.IsDraw = e.IsDraw
+
+	case EventGameReset:
+		playerSymbols := s.Game.PlayerSymbols
+		s.Game = game.NewGameStateWithSize(s.Game.Board.Size(), s.Game.WinLength)
+		s.Game.PlayerSymbols = playerSymbols
+
+	default:
+		return fmt.Errorf("evento %d de tipo desconocido: %s", e.Seq, e.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package roomlog
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"nvivas/backend/tictactoe-go-server/internal/game"
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+func move(playerID, symbol string, row, col int) Event {
+	return Event{Type: EventMoveApplied, PlayerID: playerID, Symbol: symbol, Move: &models.MovePayload{Row: row, Col: col}}
+}
+
+func TestAppendAssignsSequence(t *testing.T) {
+	l := NewLog(nil)
+	first := l.Append(Event{Type: EventRoomCreated, BoardSize: 3, WinLength: 3})
+	second := l.Append(Event{Type: EventPlayerJoined, PlayerID: "p1", Symbol: "X"})
+
+	if first.Seq != 1 || second.Seq != 2 || first.At.IsZero() {
+		t.Errorf("Secuencia incorrecta: %d, %d", first.Seq, second.Seq)
+	}
+
+	// Events devuelve una copia que no altera el registro
+	events := l.Events()
+	events[0].Type = EventGameReset
+	if l.Events()[0].Type != EventRoomCreated || l.Len() != 2 {
+		t.Error("El registro no debería cambiar al modificar la copia")
+	}
+}
+
+func TestReplayMatchesLiveGame(t *testing.T) {
+	l := NewLog(nil)
+	l.Append(Event{Type: EventRoomCreated, BoardSize: 3, WinLength: 3})
+	l.Append(Event{Type: EventPlayerJoined, PlayerID: "p1", Symbol: "O"})
+	l.Append(Event{Type: EventPlayerJoined, PlayerID: "p2", Symbol: "X"})
+	l.Append(Event{Type: EventGameStarted, Symbol: "O"})
+
+	live := game.NewGameState()
+	live.PlayerSymbols = map[string]string{"p1": "O", "p2": "X"}
+	live.CurrentTurnSymbol = "O"
+
+	moves := []Event{
+		move("p1", "O", 0, 0),
+		move("p2", "X", 1, 1),
+		move("p1", "O", 0, 1),
+		move("p2", "X", 2, 2),
+		move("p1", "O", 0, 2),
+	}
+	for _, m := range moves {
+		l.Append(m)
+		if err := game.ApplyMove(live, m.Symbol, m.Move.Row, m.Move.Col); err != nil {
+			t.Fatalf("Jugada inválida: %v", err)
+		}
+	}
+	l.Append(Event{Type: EventGameOver, PlayerID: "p1", Symbol: "O", Reason: "win"})
+
+	// El registro debe sobrevivir a la serialización
+	data, _ := json.Marshal(l.Events())
+	var decoded []Event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error deserializando eventos: %v", err)
+	}
+
+	replayed, err := Replay(decoded)
+	if err != nil {
+		t.Fatalf("Error reproduciendo: %v", err)
+	}
+	if !reflect.DeepEqual(replayed, live) {
+		t.Errorf("Estado reproducido distinto:\n%+v\n%+v", replayed, live)
+	}
+
+	// Un prefijo del registro reconstruye un momento anterior de la partida
+	partial, err := Replay(decoded[:5])
+	if err != nil {
+		t.Fatalf("Error reproduciendo prefijo: %v", err)
+	}
+	if partial.Board[0][0] != "O" || partial.Board[1][1] != "" || partial.CurrentTurnSymbol != "X" {
+		t.Errorf("Prefijo reproducido incorrecto: %+v", partial)
+	}
+}
+
+func TestReplayRoomState(t *testing.T) {
+	events := []Event{
+		{Type: EventRoomCreated, BoardSize: 3, WinLength: 3},
+		{Type: EventPlayerJoined, PlayerID: "p1", Symbol: "X"},
+		{Type: EventHostChanged, PlayerID: "p1"},
+		{Type: EventSpectatorJoined, PlayerID: "s1"},
+		{Type: EventLockChanged, Locked: true},
+		{Type: EventPlayerJoined, PlayerID: "p2", Symbol: "O"},
+		{Type: EventStateChanged, State: "playing"},
+		{Type: EventDisconnected, PlayerID: "p2"},
+		{Type: EventGameOver, PlayerID: "p1", Symbol: "X", Reason: "abandonment"},
+		{Type: EventPlayerLeft, PlayerID: "p2"},
+		{Type: EventGameReset},
+	}
+
+	state, err := ReplayRoom(events)
+	if err != nil {
+		t.Fatalf("Error reproduciendo: %v", err)
+	}
+	if state.HostID != "p1" || !state.Locked || !state.Spectators["s1"] || state.State != "playing" {
+		t.Errorf("Estado de sala incorrecto: %+v", state)
+	}
+	if len(state.Disconnected) != 0 {
+		t.Error("El jugador que se fue no debería seguir desconectado")
+	}
+	if state.Game.IsGameOver || len(state.Game.PlayerSymbols) != 1 {
+		t.Errorf("El reinicio debería conservar asientos con un tablero nuevo: %+v", state.Game)
+	}
+}
+
+func TestReplayRejectsInvalidLog(t *testing.T) {
+	if _, err := Replay(nil); err == nil {
+		t.Error("Se esperaba error para un registro vacío")
+	}
+
+	if _, err := Replay([]Event{{Type: EventPlayerJoined, PlayerID: "p1", Symbol: "X"}}); err == nil {
+		t.Error("Se esperaba error sin room_created")
+	}
+
+	illegal := []Event{
+		{Type: EventRoomCreated, BoardSize: 3, WinLength: 3},
+		move("p1", "O", 0, 0),
+	}
+	if _, err := Replay(illegal); err == nil {
+		t.Error("Se esperaba error para una jugada fuera de turno")
+	}
+}
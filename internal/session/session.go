@@ -0,0 +1,118 @@
+// Package session emite y verifica tokens firmados que permiten a un cliente
+// recuperar su identidad (y su sala) al reconectarse
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTTL es la vigencia de un token si no se configura otra
+	DefaultTTL = 24 * time.Hour
+
+	// SecretBytes es el tamaño del secreto generado cuando no se configura uno
+	SecretBytes = 32
+)
+
+var (
+	// ErrInvalidToken indica un token mal formado o con firma incorrecta
+	ErrInvalidToken = errors.New("token de sesión inválido")
+
+	// ErrExpiredToken indica un token con firma válida pero vencido
+	ErrExpiredToken = errors.New("token de sesión vencido")
+)
+
+// Manager firma y verifica tokens de sesión con HMAC-SHA256. Es inmutable, por lo que
+// se puede usar desde varias goroutines
+type Manager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewManager crea un gestor de sesiones. Un ttl no positivo usa DefaultTTL
+func NewManager(secret []byte, ttl time.Duration) *Manager {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Manager{
+		secret: append([]byte(nil), secret...),
+		ttl:    ttl,
+	}
+}
+
+// TTL devuelve la vigencia de los tokens. Pasado ese tiempo sin conectarse, un jugador ya
+// no puede recuperar su identidad
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+// GenerateSecret devuelve un secreto aleatorio. Los tokens firmados con él dejan de
+// ser válidos al reiniciar el servidor
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, SecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Issue crea un token para el jugador indicado y devuelve también su vencimiento.
+// El formato es base64url("playerID|vencimiento") + "." + base64url(firma)
+func (m *Manager) Issue(playerID string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(m.ttl)
+	payload := playerID + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	signature := base64.RawURLEncoding.EncodeToString(m.sign(encoded))
+
+	return encoded + "." + signature, expiresAt
+}
+
+// Verify comprueba la firma y el vencimiento de un token y devuelve el ID del jugador
+func (m *Manager) Verify(token string, now time.Time) (string, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrInvalidToken
+	}
+
+	givenMAC, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(givenMAC, m.sign(encoded)) {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	playerID, expiry, found := strings.Cut(string(payload), "|")
+	if !found || playerID == "" {
+		return "", ErrInvalidToken
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	if now.Unix() >= expiresAt {
+		return "", ErrExpiredToken
+	}
+
+	return playerID, nil
+}
+
+// sign calcula la firma HMAC-SHA256 de la parte codificada del token
+func (m *Manager) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
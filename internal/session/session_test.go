@@ -0,0 +1,60 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	m := NewManager([]byte("secreto-de-prueba"), time.Hour)
+	now := time.Now()
+
+	token, expiresAt := m.Issue("player-1", now)
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Vencimiento incorrecto: %v", expiresAt)
+	}
+
+	playerID, err := m.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Token válido rechazado: %v", err)
+	}
+	if playerID != "player-1" {
+		t.Errorf("ID incorrecto, esperado 'player-1', obtenido '%s'", playerID)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	m := NewManager([]byte("secreto-de-prueba"), time.Minute)
+	now := time.Now()
+
+	token, _ := m.Issue("player-1", now)
+	if _, err := m.Verify(token, now.Add(2*time.Minute)); err != ErrExpiredToken {
+		t.Errorf("Se esperaba ErrExpiredToken, obtenido %v", err)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	m := NewManager([]byte("secreto-de-prueba"), time.Hour)
+	other := NewManager([]byte("otro-secreto"), time.Hour)
+	now := time.Now()
+
+	token, _ := m.Issue("player-1", now)
+	forged, _ := other.Issue("player-1", now)
+	encoded, signature, _ := strings.Cut(token, ".")
+	otherEncoded, _, _ := strings.Cut(forged, ".")
+
+	tests := map[string]string{
+		"firma de otro secreto": forged,
+		"payload cambiado":      otherEncoded + "x." + signature,
+		"sin firma":             encoded,
+		"vacío":                 "",
+		"firma no base64":       encoded + ".***",
+	}
+
+	for name, bad := range tests {
+		if _, err := m.Verify(bad, now); err != ErrInvalidToken {
+			t.Errorf("%s: se esperaba ErrInvalidToken, obtenido %v", name, err)
+		}
+	}
+}
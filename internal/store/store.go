@@ -0,0 +1,116 @@
+// Package store guarda documentos JSON en disco para que el estado del servidor
+// sobreviva a reinicios y despliegues
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// validName restringe los nombres de documento para que no puedan salir del directorio
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ErrInvalidName indica un nombre de documento no permitido
+var ErrInvalidName = errors.New("nombre de documento inválido")
+
+// FileStore guarda cada documento como un archivo JSON dentro de un directorio.
+// Las escrituras son atómicas: un documento nunca queda a medio escribir
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore crea el directorio si no existe y devuelve el almacén
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creando directorio de datos: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Dir devuelve el directorio del almacén
+func (s *FileStore) Dir() string {
+	return s.dir
+}
+
+// Save serializa v como JSON y lo guarda con el nombre indicado
+func (s *FileStore) Save(name string, v interface{}) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("serializando %s: %w", name, err)
+	}
+
+	// Escribir en un archivo temporal y renombrarlo sobre el definitivo
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creando archivo temporal para %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("escribiendo %s: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sincronizando %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cerrando %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("reemplazando %s: %w", name, err)
+	}
+	return nil
+}
+
+// Load lee el documento indicado en v. Devuelve false si el documento no existe
+func (s *FileStore) Load(name string, v interface{}) (bool, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("leyendo %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("deserializando %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Delete elimina el documento indicado. No es un error que no exista
+func (s *FileStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("eliminando %s: %w", name, err)
+	}
+	return nil
+}
+
+// path devuelve la ruta del archivo de un documento
+func (s *FileStore) path(name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type document struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "data"))
+	if err != nil {
+		t.Fatalf("Error creando almacén: %v", err)
+	}
+
+	if err := s.Save("rooms", document{Name: "a", Count: 2}); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	var loaded document
+	found, err := s.Load("rooms", &loaded)
+	if err != nil || !found {
+		t.Fatalf("Documento no cargado: found=%v err=%v", found, err)
+	}
+	if loaded.Name != "a" || loaded.Count != 2 {
+		t.Errorf("Documento incorrecto: %+v", loaded)
+	}
+
+	// No deben quedar archivos temporales
+	entries, _ := os.ReadDir(s.Dir())
+	if len(entries) != 1 {
+		t.Errorf("Se esperaba un único archivo, hay %d", len(entries))
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	s, _ := NewFileStore(t.TempDir())
+
+	var loaded document
+	found, err := s.Load("missing", &loaded)
+	if err != nil || found {
+		t.Errorf("Se esperaba documento inexistente sin error: found=%v err=%v", found, err)
+	}
+
+	if err := s.Delete("missing"); err != nil {
+		t.Errorf("Eliminar un documento inexistente no debería fallar: %v", err)
+	}
+}
+
+func TestInvalidName(t *testing.T) {
+	s, _ := NewFileStore(t.TempDir())
+
+	for _, name := range []string{"", "../etc", "a/b", "a.b"} {
+		if err := s.Save(name, document{}); err != ErrInvalidName {
+			t.Errorf("%q: se esperaba ErrInvalidName, obtenido %v", name, err)
+		}
+	}
+}
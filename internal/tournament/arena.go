@@ -0,0 +1,103 @@
+package tournament
+
+import (
+	"sort"
+	"time"
+)
+
+// Puntuación de la arena: con una racha de arenaStreak victorias seguidas, el jugador está
+// "en racha" y sus resultados valen el doble hasta que deje de ganar
+const (
+	arenaWinPoints  = 2
+	arenaDrawPoints = 1
+	arenaStreak     = 2
+)
+
+// arenaPoints devuelve los puntos base, doblados si el jugador está en racha
+func arenaPoints(base float64, p *Player) float64 {
+	if p.Streak >= arenaStreak {
+		return base * 2
+	}
+	return base
+}
+
+// OnFire indica si el jugador está en racha en una arena
+func (t *Tournament) OnFire(p *Player) bool {
+	return t.Format == FormatArena && p.Streak >= arenaStreak
+}
+
+// pairArena empareja a los jugadores de la arena que esperan rival, de dos en dos por orden
+// de clasificación, evitando repetir el último rival mientras haya más partidas en juego.
+// Pasado el tiempo no se emparejan partidas nuevas y la arena termina cuando acaban las que
+// quedan. Devuelve las partidas que necesitan sala
+func (t *Tournament) pairArena(now time.Time) []*Match {
+	busy := make(map[string]bool)
+	for _, m := range t.matches {
+		if m.Status != MatchFinished {
+			busy[m.First] = true
+			busy[m.Second] = true
+		}
+	}
+
+	if !now.Before(t.EndsAt) {
+		if len(busy) == 0 {
+			t.Status = StatusFinished
+			t.FinishedAt = now
+			if standings := t.Standings(); len(standings) > 0 {
+				t.Winner = standings[0].ID
+			}
+		}
+		return nil
+	}
+
+	var waiting []*Player
+	for _, p := range t.players {
+		if !busy[p.ID] && !p.Paused {
+			waiting = append(waiting, p)
+		}
+	}
+	sort.SliceStable(waiting, func(i, j int) bool {
+		if waiting[i].Points != waiting[j].Points {
+			return waiting[i].Points > waiting[j].Points
+		}
+		return waiting[i].Seed < waiting[j].Seed
+	})
+
+	var pairs []pairing
+	for len(waiting) >= 2 {
+		head := waiting[0]
+		rival := -1
+		for i := 1; i < len(waiting); i++ {
+			if !rematch(head, waiting[i]) {
+				rival = i
+				break
+			}
+		}
+		if rival < 0 {
+			// Solo queda su último rival: se repite si no hay nadie más jugando que pueda
+			// quedar libre pronto
+			if len(busy) > 0 {
+				waiting = waiting[1:]
+				continue
+			}
+			rival = 1
+		}
+		pairs = append(pairs, t.ordered(head.ID, waiting[rival].ID, ""))
+		waiting = append(waiting[1:rival:rival], waiting[rival+1:]...)
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	t.Round++
+	matches := make([]*Match, 0, len(pairs))
+	for i, pair := range pairs {
+		matches = append(matches, t.addMatch(i, pair))
+	}
+	return matches
+}
+
+// rematch indica si b fue el último rival de a
+func rematch(a, b *Player) bool {
+	return len(a.Opponents) > 0 && a.Opponents[len(a.Opponents)-1] == b.ID
+}
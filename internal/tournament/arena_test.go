@@ -0,0 +1,82 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+// TestArena verifica que se empareje a los jugadores al terminar, sin repetir el último
+// rival, que las rachas dupliquen los puntos y que la arena termine al acabar el tiempo
+func TestArena(t *testing.T) {
+	tour := newTournament(t, FormatArena, 4)
+	now := time.Now()
+
+	round1, _ := tour.Start(now)
+	if !samePairs(round1, "p1-p2", "p3-p4") || !tour.EndsAt.Equal(now.Add(DefaultArenaDuration)) {
+		t.Fatalf("Primera tanda incorrecta: %v, termina %v", pairs(round1), tour.EndsAt)
+	}
+	// p1 y p2 acaban de jugar entre sí: esperan a que haya otro rival libre
+	if next := report(t, tour, round1[0], "p1"); next != nil {
+		t.Fatalf("No debería repetirse el último rival: %v", pairs(next))
+	}
+	round2 := report(t, tour, round1[1], "p3")
+	if !samePairs(round2, "p1-p3", "p2-p4") {
+		t.Fatalf("Segunda tanda incorrecta: %v", pairs(round2))
+	}
+	report(t, tour, round2[0], "p1")
+	round3 := report(t, tour, round2[1], "p2")
+	if !samePairs(round3, "p1-p2", "p3-p4") {
+		t.Fatalf("Tercera tanda incorrecta: %v", pairs(round3))
+	}
+
+	// Con dos victorias seguidas, la tercera vale el doble
+	p1 := tour.byID["p1"]
+	if !tour.OnFire(p1) {
+		t.Fatalf("p1 debería estar en racha: %+v", p1)
+	}
+	report(t, tour, round3[0], "p1")
+	if p1.Points != 2*arenaWinPoints+2*arenaWinPoints || p1.Streak != 3 {
+		t.Errorf("Puntos de racha incorrectos: %+v", p1)
+	}
+
+	// Pasado el tiempo no hay partidas nuevas y la arena termina con la última
+	later := tour.EndsAt.Add(time.Second)
+	if next, err := tour.Report(round3[1].ID, "", true, later); err != nil || next != nil {
+		t.Fatalf("No debería emparejar tras el final: %v, %v", pairs(next), err)
+	}
+	if tour.Status != StatusFinished || tour.Winner != "p1" {
+		t.Errorf("La arena debería terminar con p1 campeón: %s, %q", tour.Status, tour.Winner)
+	}
+}
+
+// TestArenaJoinAndLeave verifica las inscripciones tardías, las retiradas y las
+// incomparecencias durante la arena
+func TestArenaJoinAndLeave(t *testing.T) {
+	tour := newTournament(t, FormatArena, 2)
+	now := time.Now()
+	round1, _ := tour.Start(now)
+
+	if err := tour.Register("p3", 1500); err != nil {
+		t.Fatalf("Debería poder entrar tarde: %v", err)
+	}
+	if next := tour.Tick(now); next != nil {
+		t.Fatalf("p3 no tiene rival libre: %v", pairs(next))
+	}
+	if err := tour.Unregister("p2"); err != nil || !tour.byID["p2"].Paused {
+		t.Fatalf("p2 debería quedar en pausa: %v", err)
+	}
+
+	round2 := report(t, tour, round1[0], "p1")
+	if !samePairs(round2, "p1-p3") {
+		t.Fatalf("p2 está en pausa, p1 debería jugar con p3: %v", pairs(round2))
+	}
+	if err := tour.Register("p2", 1500); err != nil || tour.byID["p2"].Paused {
+		t.Fatalf("p2 debería volver a jugar: %v", err)
+	}
+
+	// p3 no se presenta: queda en pausa y p1 juega con p2
+	round3, _ := tour.NoShow(round2[0].ID, []string{"p1"}, now)
+	if !samePairs(round3, "p1-p2") || !tour.byID["p3"].Paused {
+		t.Fatalf("Tras la incomparecencia, p1 debería jugar con p2: %v", pairs(round3))
+	}
+}
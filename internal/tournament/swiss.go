@@ -0,0 +1,137 @@
+package tournament
+
+import (
+	"math"
+	"sort"
+)
+
+// swissPairingBudget limita los intentos del emparejamiento suizo antes de rendirse y
+// emparejar por orden aunque se repitan rivales
+const swissPairingBudget = 10000
+
+// swissRounds devuelve las rondas de un torneo suizo: las pedidas o, si no se piden, las
+// necesarias para que haya un único invicto. Nunca más de las que permiten no repetir rival
+func swissRounds(players, requested int) int {
+	rounds := requested
+	if rounds == 0 {
+		rounds = int(math.Ceil(math.Log2(float64(players))))
+	}
+	if limit := players - 1; rounds > limit {
+		rounds = limit
+	}
+	if rounds < 1 {
+		rounds = 1
+	}
+	return rounds
+}
+
+// Buchholz devuelve la suma de los puntos de los rivales del jugador, el desempate del
+// sistema suizo
+func (t *Tournament) Buchholz(p *Player) float64 {
+	total := 0.0
+	for _, opponentID := range p.Opponents {
+		if opponent := t.byID[opponentID]; opponent != nil {
+			total += opponent.Points
+		}
+	}
+	return total
+}
+
+// pairSwiss empareja una ronda del sistema suizo. Los jugadores se ordenan por puntos y,
+// dentro de cada grupo de puntos, la mitad de arriba juega contra la de abajo sin repetir
+// rivales. Si son impares, queda exento el peor clasificado que no haya descansado aún
+func (t *Tournament) pairSwiss() []pairing {
+	ranked := make([]*Player, len(t.players))
+	copy(ranked, t.players)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Points != ranked[j].Points {
+			return ranked[i].Points > ranked[j].Points
+		}
+		return ranked[i].Seed < ranked[j].Seed
+	})
+
+	var pairs []pairing
+	if len(ranked)%2 == 1 {
+		rest := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if ranked[i].Byes < ranked[rest].Byes {
+				rest = i
+			}
+		}
+		pairs = append(pairs, pairing{first: ranked[rest].ID})
+		ranked = append(ranked[:rest:rest], ranked[rest+1:]...)
+	}
+
+	budget := swissPairingBudget
+	matched, ok := t.swissMatch(ranked, &budget)
+	if !ok {
+		// No hay forma de evitar las repeticiones: se empareja por orden
+		matched = nil
+		for i := 0; i+1 < len(ranked); i += 2 {
+			matched = append(matched, t.ordered(ranked[i].ID, ranked[i+1].ID, ""))
+		}
+	}
+	return append(matched, pairs...)
+}
+
+// swissMatch empareja recursivamente al primero de la lista con el rival preferido que aún
+// no haya sido su rival, deshaciendo la elección si el resto no se puede emparejar
+func (t *Tournament) swissMatch(ranked []*Player, budget *int) ([]pairing, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+	head := ranked[0]
+	for _, i := range swissCandidates(ranked) {
+		if *budget <= 0 {
+			return nil, false
+		}
+		*budget--
+
+		rival := ranked[i]
+		if played(head, rival.ID) {
+			continue
+		}
+		rest := make([]*Player, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:i]...)
+		rest = append(rest, ranked[i+1:]...)
+		if pairs, ok := t.swissMatch(rest, budget); ok {
+			return append([]pairing{t.ordered(head.ID, rival.ID, "")}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+// swissCandidates devuelve los índices de los posibles rivales del primero de la lista, por
+// preferencia: primero su grupo de puntos, empezando por la mitad de abajo, y después el resto
+func swissCandidates(ranked []*Player) []int {
+	group := 1
+	for group < len(ranked) && ranked[group].Points == ranked[0].Points {
+		group++
+	}
+
+	candidates := make([]int, 0, len(ranked)-1)
+	half := group / 2
+	if half < 1 {
+		half = 1
+	}
+	for i := half; i < group; i++ {
+		candidates = append(candidates, i)
+	}
+	for i := half - 1; i >= 1; i-- {
+		candidates = append(candidates, i)
+	}
+	for i := group; i < len(ranked); i++ {
+		candidates = append(candidates, i)
+	}
+	return candidates
+}
+
+// played indica si el jugador ya se ha enfrentado al rival
+func played(p *Player, rivalID string) bool {
+	for _, opponentID := range p.Opponents {
+		if opponentID == rivalID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSwissRounds verifica las rondas por defecto y sus límites
+func TestSwissRounds(t *testing.T) {
+	for _, tc := range []struct {
+		players, requested, want int
+	}{
+		{2, 0, 1},
+		{5, 0, 3},
+		{16, 0, 4},
+		{17, 0, 5},
+		{8, 5, 5},
+		{4, 10, 3},
+	} {
+		if got := swissRounds(tc.players, tc.requested); got != tc.want {
+			t.Errorf("swissRounds(%d, %d) = %d, se esperaba %d", tc.players, tc.requested, got, tc.want)
+		}
+	}
+}
+
+// TestSwiss verifica que la mitad de arriba de cada grupo de puntos juegue contra la de
+// abajo y que no se repitan rivales
+func TestSwiss(t *testing.T) {
+	tour := newTournament(t, FormatSwiss, 4)
+	tour.Rounds = 3
+
+	round1, _ := tour.Start(time.Now())
+	if !samePairs(round1, "p1-p3", "p2-p4") {
+		t.Fatalf("Primera ronda incorrecta: %v", pairs(round1))
+	}
+	report(t, tour, round1[0], "p1")
+	round2 := report(t, tour, round1[1], "p2")
+	if !samePairs(round2, "p1-p2", "p3-p4") {
+		t.Fatalf("Segunda ronda incorrecta: %v", pairs(round2))
+	}
+	report(t, tour, round2[0], "p1")
+	round3 := report(t, tour, round2[1], "p3")
+	// p1 ya jugó contra p2 y p3, así que le toca p4 aunque esté en otro grupo
+	if !samePairs(round3, "p1-p4", "p2-p3") {
+		t.Fatalf("Tercera ronda incorrecta: %v", pairs(round3))
+	}
+	report(t, tour, round3[0], "p1")
+	if next := report(t, tour, round3[1], ""); next != nil || tour.Status != StatusFinished || tour.Winner != "p1" {
+		t.Fatalf("El torneo debería terminar tras 3 rondas con p1 campeón: %v, %s, %q", pairs(next), tour.Status, tour.Winner)
+	}
+}
+
+// TestSwissByeAndBuchholz verifica que la exención valga un punto y no se repita, que nadie
+// juegue dos veces contra el mismo rival y que los empates se deshagan por Buchholz
+func TestSwissByeAndBuchholz(t *testing.T) {
+	tour := newTournament(t, FormatSwiss, 5)
+
+	played := make(map[string]bool)
+	matches, _ := tour.Start(time.Now())
+	for len(matches) > 0 {
+		var next []*Match
+		for _, m := range matches {
+			if played[m.First+"-"+m.Second] {
+				t.Errorf("Rivales repetidos: %s", m.ID)
+			}
+			played[m.First+"-"+m.Second] = true
+			played[m.Second+"-"+m.First] = true
+			next = report(t, tour, m, m.First)
+		}
+		matches = next
+	}
+
+	if tour.Round != 3 || tour.Status != StatusFinished || tour.Winner != "p1" {
+		t.Fatalf("Se esperaban 3 rondas con p1 campeón: %d, %s, %q", tour.Round, tour.Status, tour.Winner)
+	}
+
+	total := 0.0
+	standings := tour.Standings()
+	for i, p := range standings {
+		total += p.Points
+		if p.Byes > 1 {
+			t.Errorf("%s no debería descansar %d veces", p.ID, p.Byes)
+		}
+		if i > 0 && standings[i-1].Points == p.Points && tour.Buchholz(standings[i-1]) < tour.Buchholz(p) {
+			t.Errorf("%s debería ir por delante de %s por Buchholz", p.ID, standings[i-1].ID)
+		}
+	}
+	// 2 partidas y una exención por ronda
+	if total != 9 {
+		t.Errorf("Se esperaban 9 puntos repartidos, hay %v", total)
+	}
+}
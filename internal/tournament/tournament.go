@@ -0,0 +1,819 @@
+// Package tournament organiza torneos: inscripción, cabezas de serie, emparejamientos de
+// cada ronda y avance de los resultados. No crea salas ni envía mensajes: el Hub crea una
+// sala para cada partida que devuelve el torneo y le comunica los resultados.
+// No es seguro para uso concurrente; el Hub solo lo usa desde su bucle
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Formatos de torneo
+const (
+	FormatSingleElimination = "single-elimination"
+	FormatDoubleElimination = "double-elimination"
+	FormatRoundRobin        = "round-robin"
+	FormatSwiss             = "swiss"
+	FormatArena             = "arena"
+)
+
+// Estados del torneo
+const (
+	StatusRegistering = "registering" // Abierto a inscripciones
+	StatusRunning     = "running"     // En juego
+	StatusFinished    = "finished"    // Terminado
+)
+
+// Estados de una partida del torneo
+const (
+	MatchReady    = "ready"    // Esperando sala
+	MatchPlaying  = "playing"  // Con sala asignada
+	MatchFinished = "finished" // Con resultado
+)
+
+// Cómo se decidió una partida
+const (
+	ResultWin    = "win"     // Victoria en el tablero (o por tiempo o abandono)
+	ResultDraw   = "draw"    // Tablas (solo en formatos que las admiten como resultado)
+	ResultBye    = "bye"     // Sin rival: el jugador queda exento
+	ResultNoShow = "no-show" // Uno o los dos jugadores no se presentaron
+	ResultSeed   = "seed"    // Tablas repetidas en eliminatoria: pasa el mejor cabeza de serie
+)
+
+// Cuadros de la doble eliminación
+const (
+	BracketWinners = "winners"
+	BracketLosers  = "losers"
+	BracketFinal   = "final"
+)
+
+// Límites de inscripción
+const (
+	MinPlayers        = 2
+	DefaultMaxPlayers = 32
+	MaxPlayers        = 256
+	MaxNameLength     = 64
+)
+
+// Límites de las rondas del sistema suizo y de la duración de las arenas
+const (
+	MaxSwissRounds       = 20
+	DefaultArenaDuration = 30 * time.Minute
+	MinArenaDuration     = time.Minute
+	MaxArenaDuration     = 24 * time.Hour
+)
+
+// MaxDrawReplays es cuántas veces se repite una partida eliminatoria que acaba en tablas
+// antes de decidirla por cabeza de serie
+const MaxDrawReplays = 2
+
+// Errores del torneo
+var (
+	ErrRegistrationClosed = errors.New("las inscripciones están cerradas")
+	ErrAlreadyRegistered  = errors.New("ya estás inscrito en el torneo")
+	ErrNotRegistered      = errors.New("no estás inscrito en el torneo")
+	ErrFull               = errors.New("el torneo está completo")
+	ErrNotEnoughPlayers   = errors.New("no hay jugadores suficientes para empezar")
+	ErrMatchNotFound      = errors.New("partida no encontrada")
+)
+
+// Player es un jugador inscrito y sus resultados en el torneo
+type Player struct {
+	ID     string
+	Seed   int // 1 es el mejor; 0 hasta que empieza el torneo
+	Rating float64
+
+	Points float64 // 1 por victoria, 0.5 por tablas
+	Wins   int
+	Losses int
+	Draws  int
+	Byes   int
+
+	Eliminated      bool
+	EliminatedRound int // Ronda en que quedó eliminado
+
+	Opponents []string // Rivales a los que se ha enfrentado, en orden
+	Streak    int      // Arena: victorias seguidas
+	Paused    bool     // Arena: no se le empareja hasta que vuelva
+}
+
+// Match es una partida del torneo. First o Second vacíos indican un jugador exento
+type Match struct {
+	ID      string
+	Round   int
+	Bracket string // Solo en doble eliminación
+	First   string // Mejor cabeza de serie de los dos
+	Second  string
+	Status  string
+	Winner  string // Vacío en tablas o si no se presentó nadie
+	Result  string
+	Games   int    // Partidas jugadas, incluidas las repetidas tras tablas
+	RoomID  string // Sala de la partida en curso
+}
+
+// Options son los parámetros opcionales de un torneo; los valores cero usan los de por defecto
+type Options struct {
+	MaxPlayers int
+	Rounds     int           // Sistema suizo: rondas; 0 las calcula según los inscritos
+	Duration   time.Duration // Arena: duración
+}
+
+// Tournament es un torneo y su estado
+type Tournament struct {
+	ID          string
+	Name        string
+	Format      string
+	OrganizerID string
+	Settings    models.RoomSettings // Configuración de todas las salas del torneo
+	MaxPlayers  int
+	Status      string
+	Round       int
+	Rounds      int           // Sistema suizo: rondas que se jugarán
+	Duration    time.Duration // Arena: duración desde el inicio
+	Winner      string
+
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	EndsAt     time.Time // Arena: a partir de aquí no se emparejan partidas nuevas
+	FinishedAt time.Time
+
+	players []*Player // Por orden de inscripción y, al empezar, por cabeza de serie
+	byID    map[string]*Player
+	matches []*Match
+
+	bracket  []string      // Eliminatoria: jugadores sin eliminar del cuadro de ganadores, en orden ("" = exento)
+	schedule [][][2]string // Liguilla: emparejamientos de todas las rondas
+}
+
+// New crea un torneo abierto a inscripciones
+func New(id, name, format, organizerID string, settings models.RoomSettings, opts Options, now time.Time) (*Tournament, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return nil, fmt.Errorf("el nombre debe tener entre 1 y %d caracteres", MaxNameLength)
+	}
+
+	switch format {
+	case FormatSingleElimination, FormatDoubleElimination, FormatRoundRobin, FormatSwiss, FormatArena:
+	default:
+		return nil, fmt.Errorf("formato '%s' no soportado (%s, %s, %s, %s o %s)", format,
+			FormatSingleElimination, FormatDoubleElimination, FormatRoundRobin, FormatSwiss, FormatArena)
+	}
+
+	maxPlayers := opts.MaxPlayers
+	if maxPlayers == 0 {
+		maxPlayers = DefaultMaxPlayers
+	}
+	if maxPlayers < MinPlayers || maxPlayers > MaxPlayers {
+		return nil, fmt.Errorf("maxPlayers debe estar entre %d y %d", MinPlayers, MaxPlayers)
+	}
+	if opts.Rounds < 0 || opts.Rounds > MaxSwissRounds {
+		return nil, fmt.Errorf("rounds debe estar entre 0 y %d", MaxSwissRounds)
+	}
+
+	duration := opts.Duration
+	if format == FormatArena {
+		if duration == 0 {
+			duration = DefaultArenaDuration
+		}
+		if duration < MinArenaDuration || duration > MaxArenaDuration {
+			return nil, fmt.Errorf("la duración de la arena debe estar entre %s y %s", MinArenaDuration, MaxArenaDuration)
+		}
+	}
+
+	return &Tournament{
+		ID:          id,
+		Name:        name,
+		Format:      format,
+		OrganizerID: organizerID,
+		Settings:    settings,
+		MaxPlayers:  maxPlayers,
+		Rounds:      opts.Rounds,
+		Duration:    duration,
+		Status:      StatusRegistering,
+		CreatedAt:   now,
+		byID:        make(map[string]*Player),
+	}, nil
+}
+
+// Register inscribe a un jugador con su puntuación, que decide su cabeza de serie. En una
+// arena en juego se puede entrar tarde, y quien la había dejado vuelve a ser emparejado
+func (t *Tournament) Register(playerID string, rating float64) error {
+	lateArena := t.Format == FormatArena && t.Status == StatusRunning
+	if t.Status != StatusRegistering && !lateArena {
+		return ErrRegistrationClosed
+	}
+	if p, ok := t.byID[playerID]; ok {
+		if lateArena && p.Paused {
+			p.Paused = false
+			return nil
+		}
+		return ErrAlreadyRegistered
+	}
+	if len(t.players) >= t.MaxPlayers {
+		return ErrFull
+	}
+
+	p := &Player{ID: playerID, Rating: rating}
+	if lateArena {
+		p.Seed = len(t.players) + 1
+	}
+	t.players = append(t.players, p)
+	t.byID[playerID] = p
+	return nil
+}
+
+// Unregister borra la inscripción de un jugador antes de que empiece el torneo. En una arena
+// en juego, el jugador conserva sus puntos pero deja de ser emparejado
+func (t *Tournament) Unregister(playerID string) error {
+	p, ok := t.byID[playerID]
+	if t.Format == FormatArena && t.Status == StatusRunning {
+		if !ok || p.Paused {
+			return ErrNotRegistered
+		}
+		p.Paused = true
+		return nil
+	}
+	if t.Status != StatusRegistering {
+		return ErrRegistrationClosed
+	}
+	if !ok {
+		return ErrNotRegistered
+	}
+
+	delete(t.byID, playerID)
+	for i, p := range t.players {
+		if p.ID == playerID {
+			t.players = append(t.players[:i], t.players[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// IsPlayer indica si el jugador está inscrito
+func (t *Tournament) IsPlayer(playerID string) bool {
+	_, ok := t.byID[playerID]
+	return ok
+}
+
+// Players devuelve los jugadores inscritos: por inscripción o, ya empezado, por cabeza de serie
+func (t *Tournament) Players() []*Player {
+	return t.players
+}
+
+// Match devuelve una partida por su ID
+func (t *Tournament) Match(matchID string) *Match {
+	for _, m := range t.matches {
+		if m.ID == matchID {
+			return m
+		}
+	}
+	return nil
+}
+
+// Start cierra las inscripciones, asigna las cabezas de serie por puntuación (a igualdad,
+// por orden de inscripción) y genera la primera ronda. Devuelve las partidas que necesitan sala
+func (t *Tournament) Start(now time.Time) ([]*Match, error) {
+	if t.Status != StatusRegistering {
+		return nil, ErrRegistrationClosed
+	}
+	if len(t.players) < MinPlayers {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	sort.SliceStable(t.players, func(i, j int) bool {
+		return t.players[i].Rating > t.players[j].Rating
+	})
+	ids := make([]string, len(t.players))
+	for i, p := range t.players {
+		p.Seed = i + 1
+		ids[i] = p.ID
+	}
+
+	switch t.Format {
+	case FormatSingleElimination, FormatDoubleElimination:
+		t.bracket = seedBracket(ids)
+	case FormatRoundRobin:
+		t.schedule = roundRobinSchedule(ids)
+	case FormatSwiss:
+		t.Rounds = swissRounds(len(ids), t.Rounds)
+	}
+
+	t.Status = StatusRunning
+	t.StartedAt = now
+	if t.Format == FormatArena {
+		t.EndsAt = now.Add(t.Duration)
+		return t.pairArena(now), nil
+	}
+	return t.nextRound(now), nil
+}
+
+// Report anota el resultado de una partida jugada. winner vacío y draw false no son válidos.
+// Devuelve las partidas que necesitan sala: la misma si hay que repetirla tras unas tablas
+// o las de la ronda siguiente si esta ronda ha terminado
+func (t *Tournament) Report(matchID, winner string, draw bool, now time.Time) ([]*Match, error) {
+	m := t.Match(matchID)
+	if m == nil || m.Status == MatchFinished {
+		return nil, ErrMatchNotFound
+	}
+	if !draw && winner != m.First && winner != m.Second {
+		return nil, fmt.Errorf("el ganador '%s' no juega la partida %s", winner, matchID)
+	}
+
+	m.Games++
+	m.RoomID = ""
+
+	if draw {
+		if !t.elimination() {
+			t.finish(m, "", ResultDraw)
+			return t.afterResult(now), nil
+		}
+
+		// En eliminatoria hace falta un ganador: se repite la partida
+		t.byID[m.First].Draws++
+		t.byID[m.Second].Draws++
+		if m.Games <= MaxDrawReplays {
+			m.Status = MatchReady
+			return []*Match{m}, nil
+		}
+		t.finish(m, m.First, ResultSeed)
+		return t.afterResult(now), nil
+	}
+
+	t.finish(m, winner, ResultWin)
+	return t.afterResult(now), nil
+}
+
+// NoShow decide una partida a la que no se presentaron uno o los dos jugadores: gana el
+// jugador presente y, si no hay ninguno, pierden los dos
+func (t *Tournament) NoShow(matchID string, present []string, now time.Time) ([]*Match, error) {
+	m := t.Match(matchID)
+	if m == nil || m.Status == MatchFinished {
+		return nil, ErrMatchNotFound
+	}
+
+	winner := ""
+	for _, playerID := range present {
+		if playerID == m.First || playerID == m.Second {
+			winner = playerID
+			break
+		}
+	}
+
+	m.RoomID = ""
+	t.finish(m, winner, ResultNoShow)
+
+	// En una arena, quien no se presenta deja de ser emparejado hasta que vuelva a inscribirse
+	if t.Format == FormatArena {
+		for _, playerID := range []string{m.First, m.Second} {
+			if playerID != winner {
+				t.byID[playerID].Paused = true
+			}
+		}
+	}
+	return t.afterResult(now), nil
+}
+
+// Tick empareja a los jugadores de una arena que esperan rival y la cierra cuando se acaba
+// el tiempo. Devuelve las partidas que necesitan sala. En los demás formatos no hace nada
+func (t *Tournament) Tick(now time.Time) []*Match {
+	if t.Format != FormatArena || t.Status != StatusRunning {
+		return nil
+	}
+	return t.pairArena(now)
+}
+
+// Replay vuelve a dejar una partida pendiente de sala sin anotar resultado, por ejemplo
+// si su sala desapareció antes de terminar
+func (t *Tournament) Replay(matchID string) error {
+	m := t.Match(matchID)
+	if m == nil || m.Status == MatchFinished {
+		return ErrMatchNotFound
+	}
+	m.Status = MatchReady
+	m.RoomID = ""
+	return nil
+}
+
+// Pending devuelve las partidas que esperan sala
+func (t *Tournament) Pending() []*Match {
+	var pending []*Match
+	for _, m := range t.matches {
+		if m.Status == MatchReady {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// SetRoom asocia la sala en la que se juega una partida
+func (t *Tournament) SetRoom(matchID, roomID string) {
+	if m := t.Match(matchID); m != nil {
+		m.RoomID = roomID
+		m.Status = MatchPlaying
+	}
+}
+
+// Standings devuelve la clasificación: en eliminatoria, los que siguen en juego y después
+// los eliminados más tarde; en los demás formatos, por puntos, después por Buchholz (sistema
+// suizo) y por victorias. A igualdad, por cabeza de serie
+func (t *Tournament) Standings() []*Player {
+	buchholz := make(map[string]float64, len(t.players))
+	if t.Format == FormatSwiss {
+		for _, p := range t.players {
+			buchholz[p.ID] = t.Buchholz(p)
+		}
+	}
+
+	standings := append([]*Player{}, t.players...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if t.elimination() {
+			if a.Eliminated != b.Eliminated {
+				return !a.Eliminated
+			}
+			if a.EliminatedRound != b.EliminatedRound {
+				return a.EliminatedRound > b.EliminatedRound
+			}
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if buchholz[a.ID] != buchholz[b.ID] {
+			return buchholz[a.ID] > buchholz[b.ID]
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Seed < b.Seed
+	})
+
+	// El campeón va primero aunque un rival acumule más puntos
+	if t.Winner != "" && len(standings) > 0 && standings[0].ID != t.Winner {
+		for i, p := range standings {
+			if p.ID == t.Winner {
+				copy(standings[1:i+1], standings[:i])
+				standings[0] = p
+				break
+			}
+		}
+	}
+	return standings
+}
+
+// elimination indica si los jugadores quedan eliminados al perder
+func (t *Tournament) elimination() bool {
+	return t.Format == FormatSingleElimination || t.Format == FormatDoubleElimination
+}
+
+// lives es cuántas derrotas aguanta un jugador antes de quedar eliminado
+func (t *Tournament) lives() int {
+	if t.Format == FormatDoubleElimination {
+		return 2
+	}
+	return 1
+}
+
+// finish anota el resultado de una partida en los jugadores
+func (t *Tournament) finish(m *Match, winner, result string) {
+	m.Status = MatchFinished
+	m.Winner = winner
+	m.Result = result
+
+	players := []string{m.First, m.Second}
+	if result == ResultBye {
+		if p := t.byID[winner]; p != nil {
+			p.Byes++
+			// En el sistema suizo la exención vale una victoria
+			if t.Format == FormatSwiss {
+				p.Points++
+			}
+		}
+		return
+	}
+
+	if m.First != "" && m.Second != "" {
+		t.byID[m.First].Opponents = append(t.byID[m.First].Opponents, m.Second)
+		t.byID[m.Second].Opponents = append(t.byID[m.Second].Opponents, m.First)
+	}
+
+	if result == ResultDraw {
+		for _, playerID := range players {
+			p := t.byID[playerID]
+			p.Draws++
+			p.Points += t.drawPoints(p)
+			p.Streak = 0
+		}
+		return
+	}
+
+	for _, playerID := range players {
+		p := t.byID[playerID]
+		if p == nil {
+			continue
+		}
+		if playerID == winner {
+			p.Wins++
+			p.Points += t.winPoints(p)
+			p.Streak++
+			continue
+		}
+		p.Losses++
+		p.Streak = 0
+		if t.elimination() && p.Losses >= t.lives() {
+			p.Eliminated = true
+			p.EliminatedRound = m.Round
+		}
+	}
+}
+
+// winPoints devuelve lo que vale una victoria del jugador, antes de sumarla a su racha
+func (t *Tournament) winPoints(p *Player) float64 {
+	if t.Format == FormatArena {
+		return arenaPoints(arenaWinPoints, p)
+	}
+	return 1
+}
+
+// drawPoints devuelve lo que valen unas tablas para el jugador
+func (t *Tournament) drawPoints(p *Player) float64 {
+	if t.Format == FormatArena {
+		return arenaPoints(arenaDrawPoints, p)
+	}
+	return 0.5
+}
+
+// afterResult genera la ronda siguiente si la actual ha terminado. En una arena, empareja
+// enseguida a los jugadores que acaban de terminar
+func (t *Tournament) afterResult(now time.Time) []*Match {
+	if t.Format == FormatArena {
+		return t.pairArena(now)
+	}
+	for _, m := range t.matches {
+		if m.Round == t.Round && m.Status != MatchFinished {
+			return nil
+		}
+	}
+	return t.nextRound(now)
+}
+
+// nextRound genera rondas hasta encontrar una con partidas que jugar (las exenciones se
+// resuelven solas) o hasta que el torneo termine
+func (t *Tournament) nextRound(now time.Time) []*Match {
+	for t.Status == StatusRunning {
+		if t.elimination() {
+			t.advanceBracket()
+		}
+
+		pairs, done := t.pairRound()
+		if done {
+			t.Status = StatusFinished
+			t.FinishedAt = now
+			if t.Winner == "" && !t.elimination() {
+				if standings := t.Standings(); len(standings) > 0 {
+					t.Winner = standings[0].ID
+				}
+			}
+			return nil
+		}
+
+		t.Round++
+		var playable []*Match
+		for i, pair := range pairs {
+			m := t.addMatch(i, pair)
+			if m.First == "" || m.Second == "" {
+				t.finish(m, m.First+m.Second, ResultBye)
+				continue
+			}
+			playable = append(playable, m)
+		}
+		if len(playable) > 0 {
+			return playable
+		}
+	}
+	return nil
+}
+
+// addMatch crea la partida i de la ronda en curso
+func (t *Tournament) addMatch(i int, pair pairing) *Match {
+	m := &Match{
+		ID:      fmt.Sprintf("r%d-m%d", t.Round, i+1),
+		Round:   t.Round,
+		Bracket: pair.bracket,
+		First:   pair.first,
+		Second:  pair.second,
+		Status:  MatchReady,
+	}
+	t.matches = append(t.matches, m)
+	return m
+}
+
+// pairing es una partida de la ronda antes de crearla
+type pairing struct {
+	first, second string
+	bracket       string
+}
+
+// pairRound empareja la ronda siguiente. done indica que el torneo ha terminado
+func (t *Tournament) pairRound() (pairs []pairing, done bool) {
+	switch t.Format {
+	case FormatRoundRobin:
+		if t.Round >= len(t.schedule) {
+			return nil, true
+		}
+		for _, pair := range t.schedule[t.Round] {
+			pairs = append(pairs, t.ordered(pair[0], pair[1], ""))
+		}
+		return pairs, false
+
+	case FormatSwiss:
+		if t.Round >= t.Rounds {
+			return nil, true
+		}
+		return t.pairSwiss(), false
+
+	case FormatSingleElimination:
+		if len(t.bracket) <= 1 {
+			if len(t.bracket) == 1 {
+				t.Winner = t.bracket[0]
+			}
+			return nil, true
+		}
+		for i := 0; i+1 < len(t.bracket); i += 2 {
+			pairs = append(pairs, t.ordered(t.bracket[i], t.bracket[i+1], ""))
+		}
+		return pairs, false
+
+	default:
+		return t.pairDoubleElimination()
+	}
+}
+
+// pairDoubleElimination empareja una ronda de doble eliminación. Los invictos siguen su
+// cuadro; los que tienen una derrota se emparejan entre sí (el mejor con el peor). Cuando
+// solo queda un invicto y un jugador con una derrota, juegan la final; si la pierde el
+// invicto, ambos tienen una derrota y se juega una final de desempate
+func (t *Tournament) pairDoubleElimination() ([]pairing, bool) {
+	unbeaten := 0
+	for _, playerID := range t.bracket {
+		if playerID != "" {
+			unbeaten++
+		}
+	}
+	var losers []*Player
+	for _, p := range t.players {
+		if p.Losses == 1 && !p.Eliminated {
+			losers = append(losers, p)
+		}
+	}
+
+	switch {
+	case unbeaten+len(losers) <= 1:
+		if unbeaten == 1 {
+			t.Winner = t.bracketChampion()
+		} else if len(losers) == 1 {
+			t.Winner = losers[0].ID
+		}
+		return nil, true
+
+	case unbeaten == 1 && len(losers) == 1:
+		return []pairing{t.ordered(t.bracketChampion(), losers[0].ID, BracketFinal)}, false
+
+	case unbeaten == 0 && len(losers) == 2:
+		return []pairing{t.ordered(losers[0].ID, losers[1].ID, BracketFinal)}, false
+	}
+
+	// Con un solo invicto, este espera sin jugar a que se decida su rival de la final
+	var pairs []pairing
+	if unbeaten > 1 {
+		for i := 0; i+1 < len(t.bracket); i += 2 {
+			pairs = append(pairs, t.ordered(t.bracket[i], t.bracket[i+1], BracketWinners))
+		}
+	}
+	if len(losers) >= 2 {
+		// Si son impares, queda exento quien menos exenciones lleva (a igualdad, el mejor)
+		if len(losers)%2 == 1 {
+			rest := 0
+			for i, p := range losers {
+				if p.Byes < losers[rest].Byes {
+					rest = i
+				}
+			}
+			pairs = append(pairs, pairing{first: losers[rest].ID, bracket: BracketLosers})
+			losers = append(losers[:rest:rest], losers[rest+1:]...)
+		}
+		for i := 0; i < len(losers)/2; i++ {
+			pairs = append(pairs, t.ordered(losers[i].ID, losers[len(losers)-1-i].ID, BracketLosers))
+		}
+	}
+	return pairs, false
+}
+
+// bracketChampion devuelve el único invicto que queda en el cuadro de ganadores
+func (t *Tournament) bracketChampion() string {
+	for _, playerID := range t.bracket {
+		if playerID != "" {
+			return playerID
+		}
+	}
+	return ""
+}
+
+// advanceBracket sustituye el cuadro de ganadores por los ganadores de la última ronda,
+// conservando el orden del cuadro. Solo avanza si la última ronda se jugó en ese cuadro
+func (t *Tournament) advanceBracket() {
+	if t.Round == 0 {
+		return
+	}
+
+	var next []string
+	played := false
+	for _, m := range t.matches {
+		if m.Round != t.Round || (m.Bracket != "" && m.Bracket != BracketWinners) {
+			continue
+		}
+		played = true
+		winner := m.Winner
+		if p := t.byID[winner]; p != nil && p.Losses > 0 {
+			winner = ""
+		}
+		next = append(next, winner)
+	}
+	if !played {
+		// Nadie del cuadro jugó esta ronda; si quedó algún invicto, tras la final ya no lo es
+		for i, playerID := range t.bracket {
+			if p := t.byID[playerID]; p != nil && p.Losses > 0 {
+				t.bracket[i] = ""
+			}
+		}
+		return
+	}
+	t.bracket = next
+}
+
+// ordered coloca primero al mejor cabeza de serie; un jugador vacío indica exención
+func (t *Tournament) ordered(a, b, bracket string) pairing {
+	if a == "" || (b != "" && t.byID[b].Seed < t.byID[a].Seed) {
+		a, b = b, a
+	}
+	return pairing{first: a, second: b, bracket: bracket}
+}
+
+// seedBracket coloca a los jugadores (ya ordenados por cabeza de serie) en un cuadro de
+// tamaño potencia de dos, de modo que los mejores solo se crucen al final. Las posiciones
+// sin jugador son exenciones, que corresponden a los mejores cabezas de serie
+func seedBracket(ids []string) []string {
+	size := 1
+	for size < len(ids) {
+		size *= 2
+	}
+
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+
+	bracket := make([]string, size)
+	for i, seed := range order {
+		if seed <= len(ids) {
+			bracket[i] = ids[seed-1]
+		}
+	}
+	return bracket
+}
+
+// roundRobinSchedule genera una liguilla por el método del círculo: cada jugador se
+// enfrenta una vez a todos los demás. Con un número impar, un jugador descansa en cada ronda
+func roundRobinSchedule(ids []string) [][][2]string {
+	circle := append([]string{}, ids...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "")
+	}
+
+	n := len(circle)
+	rounds := make([][][2]string, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		round := make([][2]string, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			round = append(round, [2]string{circle[i], circle[n-1-i]})
+		}
+		rounds = append(rounds, round)
+
+		// El primero queda fijo y el resto rota una posición
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return rounds
+}
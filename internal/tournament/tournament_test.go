@@ -0,0 +1,272 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// newTournament crea un torneo con n jugadores p1..pn; p1 tiene la mejor puntuación
+func newTournament(t *testing.T, format string, n int) *Tournament {
+	t.Helper()
+	tour, err := New("t1", "Copa", format, "org", models.RoomSettings{Variant: "classic"}, Options{}, time.Now())
+	if err != nil {
+		t.Fatalf("Error creando torneo: %v", err)
+	}
+	for i := 1; i <= n; i++ {
+		if err := tour.Register(fmt.Sprintf("p%d", i), float64(2000-i)); err != nil {
+			t.Fatalf("Error inscribiendo: %v", err)
+		}
+	}
+	return tour
+}
+
+// pairs resume las partidas como "primero-segundo"
+func pairs(matches []*Match) []string {
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.First+"-"+m.Second)
+	}
+	return out
+}
+
+func samePairs(matches []*Match, want ...string) bool {
+	got := pairs(matches)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// report anota un resultado y falla el test si no se acepta
+func report(t *testing.T, tour *Tournament, m *Match, winner string) []*Match {
+	t.Helper()
+	next, err := tour.Report(m.ID, winner, winner == "", time.Now())
+	if err != nil {
+		t.Fatalf("Error anotando %s: %v", m.ID, err)
+	}
+	return next
+}
+
+// TestSeedBracket verifica que los mejores cabezas de serie solo se crucen al final
+// y que las exenciones sean para ellos
+func TestSeedBracket(t *testing.T) {
+	got := seedBracket([]string{"1", "2", "3", "4", "5", "6"})
+	want := []string{"1", "", "4", "5", "2", "", "3", "6"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Cuadro incorrecto: %q, se esperaba %q", got, want)
+	}
+}
+
+// TestSingleElimination verifica las exenciones, el avance del cuadro y el campeón
+func TestSingleElimination(t *testing.T) {
+	tour := newTournament(t, FormatSingleElimination, 5)
+
+	round1, err := tour.Start(time.Now())
+	if err != nil {
+		t.Fatalf("Error empezando: %v", err)
+	}
+	// Cuadro de 8: p1, p2 y p3 quedan exentos
+	if !samePairs(round1, "p4-p5") {
+		t.Fatalf("Primera ronda incorrecta: %v", pairs(round1))
+	}
+
+	round2 := report(t, tour, round1[0], "p5")
+	if !samePairs(round2, "p1-p5", "p2-p3") || tour.Round != 2 {
+		t.Fatalf("Segunda ronda incorrecta: %v (ronda %d)", pairs(round2), tour.Round)
+	}
+	if next := report(t, tour, round2[0], "p5"); next != nil {
+		t.Fatalf("La ronda no debería avanzar con partidas pendientes: %v", pairs(next))
+	}
+	final := report(t, tour, round2[1], "p2")
+	if !samePairs(final, "p2-p5") {
+		t.Fatalf("Final incorrecta: %v", pairs(final))
+	}
+
+	if next := report(t, tour, final[0], "p5"); next != nil || tour.Status != StatusFinished || tour.Winner != "p5" {
+		t.Fatalf("El torneo debería terminar con p5 campeón: %s, %q", tour.Status, tour.Winner)
+	}
+	standings := tour.Standings()
+	if standings[0].ID != "p5" || standings[1].ID != "p2" || !standings[1].Eliminated {
+		t.Errorf("Clasificación incorrecta: %s, %s", standings[0].ID, standings[1].ID)
+	}
+	if _, err := tour.Report(final[0].ID, "p5", false, time.Now()); err != ErrMatchNotFound {
+		t.Errorf("Una partida terminada no admite otro resultado: %v", err)
+	}
+}
+
+// TestEliminationDrawsAreReplayed verifica que las tablas se repitan y, tras varias,
+// pase el mejor cabeza de serie
+func TestEliminationDrawsAreReplayed(t *testing.T) {
+	tour := newTournament(t, FormatSingleElimination, 2)
+	final, _ := tour.Start(time.Now())
+
+	for i := 0; i < MaxDrawReplays; i++ {
+		replay := report(t, tour, final[0], "")
+		if len(replay) != 1 || replay[0] != final[0] || replay[0].Status != MatchReady {
+			t.Fatalf("Unas tablas deberían repetir la partida: %v", pairs(replay))
+		}
+	}
+
+	report(t, tour, final[0], "")
+	if tour.Winner != "p1" || final[0].Result != ResultSeed || final[0].Games != MaxDrawReplays+1 {
+		t.Errorf("Debería pasar el mejor cabeza de serie: %q, %+v", tour.Winner, final[0])
+	}
+}
+
+// TestDoubleElimination verifica el cuadro de perdedores y la final de desempate
+func TestDoubleElimination(t *testing.T) {
+	tour := newTournament(t, FormatDoubleElimination, 4)
+
+	round1, _ := tour.Start(time.Now())
+	if !samePairs(round1, "p1-p4", "p2-p3") {
+		t.Fatalf("Primera ronda incorrecta: %v", pairs(round1))
+	}
+	report(t, tour, round1[0], "p1")
+	round2 := report(t, tour, round1[1], "p2")
+
+	// Los invictos siguen su cuadro y los derrotados juegan entre sí
+	if !samePairs(round2, "p1-p2", "p3-p4") || round2[0].Bracket != BracketWinners || round2[1].Bracket != BracketLosers {
+		t.Fatalf("Segunda ronda incorrecta: %v", pairs(round2))
+	}
+	report(t, tour, round2[0], "p1")
+	round3 := report(t, tour, round2[1], "p3")
+	if !samePairs(round3, "p2-p3") || round3[0].Bracket != BracketLosers {
+		t.Fatalf("Tercera ronda incorrecta: %v", pairs(round3))
+	}
+
+	final := report(t, tour, round3[0], "p2")
+	if !samePairs(final, "p1-p2") || final[0].Bracket != BracketFinal {
+		t.Fatalf("Final incorrecta: %v", pairs(final))
+	}
+
+	// Si pierde el invicto, los dos tienen una derrota y se juega el desempate
+	reset := report(t, tour, final[0], "p2")
+	if !samePairs(reset, "p1-p2") || reset[0].Bracket != BracketFinal {
+		t.Fatalf("Se esperaba la final de desempate: %v", pairs(reset))
+	}
+	report(t, tour, reset[0], "p2")
+	if tour.Status != StatusFinished || tour.Winner != "p2" {
+		t.Errorf("p2 debería ser campeón: %s, %q", tour.Status, tour.Winner)
+	}
+	for _, p := range tour.Players() {
+		if p.ID != "p2" && !p.Eliminated {
+			t.Errorf("%s debería estar eliminado", p.ID)
+		}
+	}
+}
+
+// TestRoundRobin verifica que todos se enfrenten una vez, con un descanso por ronda
+// si son impares, y que gane quien más puntos sume
+func TestRoundRobin(t *testing.T) {
+	tour := newTournament(t, FormatRoundRobin, 5)
+
+	played := make(map[string]int)
+	matches, _ := tour.Start(time.Now())
+	for rounds := 0; len(matches) > 0; rounds++ {
+		if rounds > 5 {
+			t.Fatal("Demasiadas rondas")
+		}
+		if len(matches) != 2 {
+			t.Fatalf("Cada ronda debería tener 2 partidas y un descanso: %v", pairs(matches))
+		}
+		var next []*Match
+		for _, m := range matches {
+			played[m.First+"-"+m.Second]++
+			played[m.Second+"-"+m.First]++
+			// Gana siempre el mejor cabeza de serie, salvo p3-p4, que acaba en tablas
+			winner := m.First
+			if m.First == "p3" && m.Second == "p4" {
+				winner = ""
+			}
+			next = report(t, tour, m, winner)
+		}
+		matches = next
+	}
+
+	if tour.Round != 5 || len(played) != 20 {
+		t.Errorf("Se esperaban 5 rondas y 10 enfrentamientos distintos: %d rondas, %d", tour.Round, len(played)/2)
+	}
+	standings := tour.Standings()
+	if tour.Winner != "p1" || standings[0].Points != 4 || standings[2].ID != "p3" || standings[2].Points != 1.5 || standings[3].ID != "p4" {
+		t.Errorf("Clasificación incorrecta: ganador %q, %+v, %+v", tour.Winner, standings[0], standings[2])
+	}
+	for _, p := range standings {
+		if p.Byes != 1 {
+			t.Errorf("%s debería descansar una vez, descansó %d", p.ID, p.Byes)
+		}
+	}
+}
+
+// TestNoShow verifica que gane el jugador presente y que, sin ninguno, pierdan los dos
+func TestNoShow(t *testing.T) {
+	tour := newTournament(t, FormatSingleElimination, 4)
+	round1, _ := tour.Start(time.Now())
+
+	if _, err := tour.NoShow(round1[0].ID, []string{"p4"}, time.Now()); err != nil {
+		t.Fatalf("Error anotando incomparecencia: %v", err)
+	}
+	round2, _ := tour.NoShow(round1[1].ID, nil, time.Now())
+
+	if round1[0].Winner != "p4" || round1[0].Result != ResultNoShow {
+		t.Errorf("Debería ganar el presente: %+v", round1[0])
+	}
+	// p2 y p3 quedan eliminados, así que p4 es campeón sin jugar la final
+	if len(round2) != 0 || tour.Status != StatusFinished || tour.Winner != "p4" {
+		t.Errorf("p4 debería ganar por exención: %v, %s, %q", pairs(round2), tour.Status, tour.Winner)
+	}
+}
+
+// TestRegistration verifica los límites de inscripción
+func TestRegistration(t *testing.T) {
+	tour, err := New("t1", "Copa", FormatRoundRobin, "org", models.RoomSettings{}, Options{MaxPlayers: 2}, time.Now())
+	if err != nil {
+		t.Fatalf("Error creando torneo: %v", err)
+	}
+	if _, err := tour.Start(time.Now()); err != ErrNotEnoughPlayers {
+		t.Errorf("Se esperaba ErrNotEnoughPlayers, obtenido %v", err)
+	}
+
+	tour.Register("a", 1500)
+	if err := tour.Register("a", 1500); err != ErrAlreadyRegistered {
+		t.Errorf("Se esperaba ErrAlreadyRegistered, obtenido %v", err)
+	}
+	tour.Register("b", 1500)
+	if err := tour.Register("c", 1500); err != ErrFull {
+		t.Errorf("Se esperaba ErrFull, obtenido %v", err)
+	}
+	if err := tour.Unregister("c"); err != ErrNotRegistered {
+		t.Errorf("Se esperaba ErrNotRegistered, obtenido %v", err)
+	}
+
+	tour.Start(time.Now())
+	if err := tour.Register("c", 1500); err != ErrRegistrationClosed {
+		t.Errorf("Se esperaba ErrRegistrationClosed, obtenido %v", err)
+	}
+	if err := tour.Unregister("a"); err != ErrRegistrationClosed {
+		t.Errorf("Se esperaba ErrRegistrationClosed al retirarse, obtenido %v", err)
+	}
+
+	for _, invalid := range []struct {
+		name, format string
+		opts         Options
+	}{
+		{"", FormatRoundRobin, Options{}},
+		{"Copa", "swiss-ish", Options{}},
+		{"Copa", FormatRoundRobin, Options{MaxPlayers: 1}},
+		{"Copa", FormatRoundRobin, Options{MaxPlayers: MaxPlayers + 1}},
+		{"Copa", FormatSwiss, Options{Rounds: MaxSwissRounds + 1}},
+		{"Copa", FormatArena, Options{Duration: time.Second}},
+	} {
+		if _, err := New("x", invalid.name, invalid.format, "org", models.RoomSettings{}, invalid.opts, time.Now()); err == nil {
+			t.Errorf("New(%q, %q, %+v) debería fallar", invalid.name, invalid.format, invalid.opts)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tournament
+
+import (
+	"time"
+
+	"nvivas/backend/tictactoe-go-server/pkg/models"
+)
+
+// Update devuelve el estado completo del torneo para TOURNAMENT_UPDATE
+func (t *Tournament) Update() models.TournamentUpdateResponse {
+	update := models.TournamentUpdateResponse{
+		Type:         "TOURNAMENT_UPDATE",
+		TournamentID: t.ID,
+		Name:         t.Name,
+		Format:       t.Format,
+		Status:       t.Status,
+		OrganizerID:  t.OrganizerID,
+		Settings:     t.Settings,
+		MaxPlayers:   t.MaxPlayers,
+		Round:        t.Round,
+		Rounds:       t.Rounds,
+		Winner:       t.Winner,
+		Standings:    []models.TournamentPlayer{},
+		Matches:      make([]models.TournamentMatch, 0, len(t.matches)),
+		CreatedAt:    t.CreatedAt.UnixMilli(),
+		StartedAt:    unixMilli(t.StartedAt),
+		EndsAt:       unixMilli(t.EndsAt),
+		FinishedAt:   unixMilli(t.FinishedAt),
+	}
+
+	for i, p := range t.Standings() {
+		player := models.TournamentPlayer{
+			Rank:       i + 1,
+			PlayerID:   p.ID,
+			Seed:       p.Seed,
+			Rating:     p.Rating,
+			Points:     p.Points,
+			Wins:       p.Wins,
+			Losses:     p.Losses,
+			Draws:      p.Draws,
+			Byes:       p.Byes,
+			Eliminated: p.Eliminated,
+			Streak:     p.Streak,
+			OnFire:     t.OnFire(p),
+			Paused:     p.Paused,
+		}
+		if t.Format == FormatSwiss {
+			player.Buchholz = t.Buchholz(p)
+		}
+		update.Standings = append(update.Standings, player)
+	}
+	for _, m := range t.matches {
+		update.Matches = append(update.Matches, models.TournamentMatch{
+			MatchID: m.ID,
+			Round:   m.Round,
+			Bracket: m.Bracket,
+			First:   m.First,
+			Second:  m.Second,
+			Status:  m.Status,
+			Winner:  m.Winner,
+			Result:  m.Result,
+			Games:   m.Games,
+			RoomID:  m.RoomID,
+		})
+	}
+	return update
+}
+
+// Summary devuelve el resumen del torneo para TOURNAMENT_LIST
+func (t *Tournament) Summary() models.TournamentSummary {
+	return models.TournamentSummary{
+		TournamentID: t.ID,
+		Name:         t.Name,
+		Format:       t.Format,
+		Status:       t.Status,
+		OrganizerID:  t.OrganizerID,
+		Variant:      t.Settings.Variant,
+		Players:      len(t.players),
+		MaxPlayers:   t.MaxPlayers,
+		Round:        t.Round,
+	}
+}
+
+// unixMilli convierte un instante a milisegundos; el instante cero queda en 0
+func unixMilli(at time.Time) int64 {
+	if at.IsZero() {
+		return 0
+	}
+	return at.UnixMilli()
+}
@@ -27,14 +27,41 @@ type PlayerMove struct {
 	MoveData MovePayload
 }
 
+// TimeControl describes the clock of a game. Zero values mean an untimed game.
+type TimeControl struct {
+	InitialSeconds   int `json:"initialSeconds"`
+	IncrementSeconds int `json:"incrementSeconds"`
+}
+
+// RoomSettings contains the options chosen when a room is created
+type RoomSettings struct {
+	Variant         string      `json:"variant"`         // classic, four-in-a-row or gomoku
+	BoardSize       int         `json:"boardSize"`       // 0 means the variant default
+	TimeControl     TimeControl `json:"timeControl"`     // Clock settings
+	Visibility      string      `json:"visibility"`      // public or private
+	PreferredSymbol string      `json:"preferredSymbol"` // X, O or random
+	Rated           bool        `json:"rated"`           // Rated or casual game
+	AllowSpectators bool        `json:"allowSpectators"` // Whether spectators may join
+	MaxSpectators   int         `json:"maxSpectators"`   // Maximum number of spectators
+	ReadyCheck      bool        `json:"readyCheck"`      // Players must confirm before the game starts
+	ReadyTimeout    int         `json:"readyTimeout"`    // Seconds to confirm before unready players are removed
+	FirstMove       string      `json:"firstMove"`       // creator, joiner, random or coinflip
+	DisconnectGrace int         `json:"disconnectGrace"` // Seconds a disconnected player has to return before forfeiting
+	MaxPauses       int         `json:"maxPauses"`       // Mutual pauses allowed per game, 0 disables pausing
+	MaxPauseSeconds int         `json:"maxPauseSeconds"` // Longest a mutual pause may last before the game resumes
+}
+
 // CreateRoomPayload contains data for creating a room
 type CreateRoomPayload struct {
-	// Empty for now, could contain preferences later
+	Settings RoomSettings `json:"settings"`
 }
 
-// JoinRoomPayload contains data for joining a room
+// JoinRoomPayload contains data for joining a room.
+// RoomID accepts either the internal room ID or the short room code.
 type JoinRoomPayload struct {
-	RoomID string `json:"roomId"`
+	RoomID   string `json:"roomId"`
+	RoomCode string `json:"roomCode,omitempty"`
+	Spectate bool   `json:"spectate,omitempty"`
 }
 
 // MakeMovePayload contains data for making a move
@@ -44,49 +71,130 @@ type MakeMovePayload struct {
 
 // RoomCreatedResponse is sent after a room is created
 type RoomCreatedResponse struct {
-	Type     string `json:"type"`
-	RoomID   string `json:"roomId"`
-	PlayerID string `json:"playerId"`
-	Symbol   string `json:"symbol"`
+	Type     string       `json:"type"`
+	RoomID   string       `json:"roomId"`
+	RoomCode string       `json:"roomCode"`
+	PlayerID string       `json:"playerId"`
+	Symbol   string       `json:"symbol"`
+	Settings RoomSettings `json:"settings"`
 }
 
 // RoomJoinedResponse is sent after successfully joining a room
 type RoomJoinedResponse struct {
-	Type      string `json:"type"`
-	RoomID    string `json:"roomId"`
-	PlayerID  string `json:"playerId"`
-	Symbol    string `json:"symbol"`
-	GameState string `json:"gameState"`
+	Type      string       `json:"type"`
+	RoomID    string       `json:"roomId"`
+	RoomCode  string       `json:"roomCode"`
+	PlayerID  string       `json:"playerId"`
+	Symbol    string       `json:"symbol"`
+	GameState string       `json:"gameState"`
+	Settings  RoomSettings `json:"settings"`
+	HostID    string       `json:"hostId"`
+
+	// Profiles of the seated players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
 }
 
 // PlayerJoinedResponse is sent to the first player when a second player joins
 type PlayerJoinedResponse struct {
-	Type     string `json:"type"`
-	PlayerID string `json:"playerId"`
+	Type     string         `json:"type"`
+	PlayerID string         `json:"playerId"`
+	Profile  *PlayerProfile `json:"profile,omitempty"` // Absent if the player has no profile
 }
 
 // GameStartResponse is sent to both players when the game starts
 type GameStartResponse struct {
 	Type        string            `json:"type"`
+	GameID      string            `json:"gameId,omitempty"`
 	Board       [][]string        `json:"board"`
 	CurrentTurn string            `json:"currentTurn"`
-	Players     map[string]string `json:"players"` // map[playerID]symbol
+	Players     map[string]string `json:"players"`          // map[playerID]symbol
+	Clocks      map[string]int64  `json:"clocks,omitempty"` // map[symbol]remaining milliseconds
+
+	// Profiles of the players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
 }
 
 // GameUpdateResponse is sent after a valid move
 type GameUpdateResponse struct {
-	Type        string      `json:"type"`
-	Board       [][]string  `json:"board"`
-	CurrentTurn string      `json:"currentTurn"`
-	LastMove    MovePayload `json:"lastMove"`
+	Type        string           `json:"type"`
+	Board       [][]string       `json:"board"`
+	CurrentTurn string           `json:"currentTurn"`
+	LastMove    MovePayload      `json:"lastMove"`
+	Clocks      map[string]int64 `json:"clocks,omitempty"` // map[symbol]remaining milliseconds
+}
+
+// CellChange is a single board cell that changed in a delta update
+type CellChange struct {
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	Symbol string `json:"symbol"` // Empty when the cell was cleared
+}
+
+// GameDeltaResponse is the GAME_UPDATE sent to clients that opted into delta updates.
+// It carries only the changed cells; checkpoints also carry the full board
+type GameDeltaResponse struct {
+	Type        string           `json:"type"`
+	Delta       bool             `json:"delta"`
+	MoveNumber  int              `json:"moveNumber"`
+	Changes     []CellChange     `json:"changes"`
+	Checkpoint  bool             `json:"checkpoint,omitempty"`
+	Board       [][]string       `json:"board,omitempty"` // Only in checkpoints
+	CurrentTurn string           `json:"currentTurn"`
+	LastMove    MovePayload      `json:"lastMove"`
+	Clocks      map[string]int64 `json:"clocks,omitempty"` // map[symbol]remaining milliseconds
+}
+
+// SetUpdateModePayload is sent by a client to choose how it receives GAME_UPDATE
+type SetUpdateModePayload struct {
+	Mode string `json:"mode"` // full or delta
+}
+
+// UpdateModeResponse confirms the update mode of a client
+type UpdateModeResponse struct {
+	Type            string `json:"type"`
+	Mode            string `json:"mode"`
+	CheckpointEvery int    `json:"checkpointEvery,omitempty"` // Moves between full-board checkpoints in delta mode
 }
 
 // GameOverResponse is sent when the game ends
 type GameOverResponse struct {
-	Type   string     `json:"type"`
-	Board  [][]string `json:"board"`
-	Winner string     `json:"winner"` // PlayerID or empty for draw
-	IsDraw bool       `json:"isDraw"`
+	Type   string           `json:"type"`
+	GameID string           `json:"gameId,omitempty"` // ID to request the replay
+	Board  [][]string       `json:"board"`
+	Winner string           `json:"winner"` // PlayerID or empty for draw
+	IsDraw bool             `json:"isDraw"`
+	Reason string           `json:"reason,omitempty"` // win, draw, timeout or abandonment
+	Clocks map[string]int64 `json:"clocks,omitempty"` // map[symbol]remaining milliseconds
+
+	// Rating changes of a rated game, map[playerID]change
+	RatingChanges map[string]RatingChange `json:"ratingChanges,omitempty"`
+}
+
+// RatingChange is a player's Glicko-2 rating before and after a rated game
+type RatingChange struct {
+	Before     float64 `json:"before"`
+	After      float64 `json:"after"`
+	Delta      float64 `json:"delta"`
+	Deviation  float64 `json:"deviation"`  // Rating deviation after the game
+	Volatility float64 `json:"volatility"` // Volatility after the game
+}
+
+// SpectatingResponse is sent to a client after it joins a room as a spectator
+type SpectatingResponse struct {
+	Type        string            `json:"type"`
+	RoomID      string            `json:"roomId"`
+	RoomCode    string            `json:"roomCode"`
+	Settings    RoomSettings      `json:"settings"`
+	Board       [][]string        `json:"board"`
+	CurrentTurn string            `json:"currentTurn"`
+	Players     map[string]string `json:"players"`
+	Clocks      map[string]int64  `json:"clocks,omitempty"`
+	HostID      string            `json:"hostId"`
+	Locked      bool              `json:"locked"`
+	State       string            `json:"state"`
+
+	// Profiles of the players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
 }
 
 // ErrorResponse is sent when an error occurs
@@ -101,26 +209,594 @@ type PlayerLeftResponse struct {
 	PlayerID string `json:"playerId"`
 }
 
+// PlayerDisconnectedResponse is sent when a player drops during a game and has a grace period to return
+type PlayerDisconnectedResponse struct {
+	Type         string `json:"type"`
+	PlayerID     string `json:"playerId"`
+	GraceSeconds int    `json:"graceSeconds"`
+	Deadline     int64  `json:"deadline"` // Unix milliseconds
+}
+
 // PlayerReconnectedResponse is sent when a player reconnects to the game
 type PlayerReconnectedResponse struct {
 	Type     string `json:"type"`
 	PlayerID string `json:"playerId"`
 }
 
-// ListRoomsPayload is empty as it doesn't need any parameters
+// ListRoomsPayload filters, sorts and paginates LIST_ROOMS. Every field is optional
 type ListRoomsPayload struct {
-	// Empty for now, could contain filters later
+	Variant     string       `json:"variant,omitempty"`
+	HasOpenSeat *bool        `json:"hasOpenSeat,omitempty"`
+	Rated       *bool        `json:"rated,omitempty"`
+	TimeControl *TimeControl `json:"timeControl,omitempty"` // Exact match
+	Spectatable *bool        `json:"spectatable,omitempty"` // Spectators allowed and not full
+	Sort        string       `json:"sort,omitempty"`        // newest (default), spectators or rating
+	Cursor      string       `json:"cursor,omitempty"`      // nextCursor from the previous page
+	Limit       int          `json:"limit,omitempty"`
 }
 
 // RoomInfo contains information about a room
 type RoomInfo struct {
-	RoomID  string   `json:"roomId"`
-	Players []string `json:"players"`
-	IsFull  bool     `json:"isFull"`
+	RoomID     string       `json:"roomId"`
+	RoomCode   string       `json:"roomCode"`
+	Players    []string     `json:"players"`
+	IsFull     bool         `json:"isFull"`
+	Settings   RoomSettings `json:"settings"`
+	State      string       `json:"state"`
+	HostID     string       `json:"hostId,omitempty"`
+	Spectators int          `json:"spectators"`
+	CreatedAt  int64        `json:"createdAt"`        // Unix milliseconds
+	Rating     float64      `json:"rating,omitempty"` // Average rating of the seated players
+
+	// Profiles of the seated players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
 }
 
 // RoomListPayload contains the list of available rooms
 type RoomListPayload struct {
-	Type  string     `json:"type"`
-	Rooms []RoomInfo `json:"rooms"`
+	Type       string     `json:"type"`
+	Rooms      []RoomInfo `json:"rooms"`
+	NextCursor string     `json:"nextCursor,omitempty"` // Empty on the last page
+}
+
+// TargetPlayerPayload identifies the player targeted by a host action (KICK_PLAYER, TRANSFER_HOST)
+type TargetPlayerPayload struct {
+	PlayerID string `json:"playerId"`
+}
+
+// KickedResponse is sent to a client that was removed from a room by the host
+type KickedResponse struct {
+	Type   string `json:"type"`
+	RoomID string `json:"roomId"`
+	By     string `json:"by"`
+}
+
+// PlayerKickedResponse is sent to the remaining members when someone is kicked
+type PlayerKickedResponse struct {
+	Type     string `json:"type"`
+	PlayerID string `json:"playerId"`
+	By       string `json:"by"`
+}
+
+// RoomLockResponse is sent when the host locks or unlocks the room
+type RoomLockResponse struct {
+	Type   string `json:"type"`
+	RoomID string `json:"roomId"`
+	Locked bool   `json:"locked"`
+	By     string `json:"by"`
+}
+
+// HostChangedResponse is sent when room ownership changes
+type HostChangedResponse struct {
+	Type           string `json:"type"`
+	HostID         string `json:"hostId"`
+	PreviousHostID string `json:"previousHostId"`
+	Reason         string `json:"reason"` // transferred or host_left
+}
+
+// ReadyCheckResponse is sent when both players are seated and must confirm they are ready
+type ReadyCheckResponse struct {
+	Type           string   `json:"type"`
+	Players        []string `json:"players"`
+	Ready          []string `json:"ready"`
+	TimeoutSeconds int      `json:"timeoutSeconds"`
+	Deadline       int64    `json:"deadline"` // Unix milliseconds
+
+	// Profiles of the players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
+}
+
+// ReadyUpdateResponse is sent when a player confirms during the ready check
+type ReadyUpdateResponse struct {
+	Type     string   `json:"type"`
+	PlayerID string   `json:"playerId"`
+	Ready    []string `json:"ready"`
+}
+
+// ReadyCheckCancelledResponse is sent when the ready check ends without starting the game
+type ReadyCheckCancelledResponse struct {
+	Type    string   `json:"type"`
+	Reason  string   `json:"reason"`            // timeout or player_left
+	Removed []string `json:"removed,omitempty"` // Players removed for not being ready
+}
+
+// RemovedFromRoomResponse is sent to a client removed from a room by the server
+type RemovedFromRoomResponse struct {
+	Type   string `json:"type"`
+	RoomID string `json:"roomId"`
+	Reason string `json:"reason"`
+}
+
+// CoinFlipResponse describes the current phase of the commit-reveal coin flip
+type CoinFlipResponse struct {
+	Type        string            `json:"type"`
+	Phase       string            `json:"phase"` // commit or reveal
+	CreatorID   string            `json:"creatorId"`
+	Players     map[string]string `json:"players"`
+	Commitments map[string]string `json:"commitments"` // map[playerID]sha256(nonce) in hex
+	Deadline    int64             `json:"deadline"`    // Unix milliseconds
+}
+
+// CoinFlipCommitPayload carries the SHA-256 hash (hex) of the player's secret nonce
+type CoinFlipCommitPayload struct {
+	Commitment string `json:"commitment"`
+}
+
+// CoinFlipRevealPayload carries the player's secret nonce (hex)
+type CoinFlipRevealPayload struct {
+	Nonce string `json:"nonce"`
+}
+
+// CoinFlipResultResponse is sent when the coin flip is resolved so clients can verify it
+type CoinFlipResultResponse struct {
+	Type          string            `json:"type"`
+	Commitments   map[string]string `json:"commitments"`
+	Nonces        map[string]string `json:"nonces"`
+	FirstPlayerID string            `json:"firstPlayerId"`
+	FirstSymbol   string            `json:"firstSymbol"`
+	Reason        string            `json:"reason"` // xor or forfeit
+}
+
+// RoomStateChangedResponse is broadcast whenever a room moves to another lifecycle state
+type RoomStateChangedResponse struct {
+	Type          string `json:"type"`
+	RoomID        string `json:"roomId"`
+	State         string `json:"state"`
+	PreviousState string `json:"previousState"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// ResumePayload carries the session token sent in a RESUME_SESSION message
+type ResumePayload struct {
+	SessionToken string `json:"sessionToken"`
+}
+
+// SessionResponse gives the client its identity and a token to resume it after reconnecting
+type SessionResponse struct {
+	Type         string `json:"type"`
+	PlayerID     string `json:"playerId"`
+	SessionToken string `json:"sessionToken"`
+	ExpiresAt    int64  `json:"expiresAt"`
+	RoomID       string `json:"roomId,omitempty"`
+}
+
+// PauseRequestedResponse is broadcast when a player asks to pause the game
+type PauseRequestedResponse struct {
+	Type       string `json:"type"`
+	PlayerID   string `json:"playerId"`
+	PausesUsed int    `json:"pausesUsed"`
+	MaxPauses  int    `json:"maxPauses"`
+}
+
+// GamePausedResponse is broadcast when both players agree to pause the game
+type GamePausedResponse struct {
+	Type           string           `json:"type"`
+	RequestedBy    string           `json:"requestedBy"`
+	AcceptedBy     string           `json:"acceptedBy"`
+	ResumeDeadline int64            `json:"resumeDeadline"` // Unix milliseconds
+	PausesUsed     int              `json:"pausesUsed"`
+	MaxPauses      int              `json:"maxPauses"`
+	Clocks         map[string]int64 `json:"clocks,omitempty"`
+}
+
+// GameResumedResponse is broadcast when a mutual pause ends
+type GameResumedResponse struct {
+	Type      string           `json:"type"`
+	ResumedBy string           `json:"resumedBy,omitempty"`
+	Reason    string           `json:"reason"` // resumed or pause_expired
+	Clocks    map[string]int64 `json:"clocks,omitempty"`
+}
+
+// GetReplayPayload is sent by a client to request the replay of a finished game
+type GetReplayPayload struct {
+	GameID string `json:"gameId"`
+}
+
+// RecordedMove is a single move of an archived game
+type RecordedMove struct {
+	Number   int    `json:"number"` // 1-based move number
+	PlayerID string `json:"playerId"`
+	Symbol   string `json:"symbol"`
+	Row      int    `json:"row"`
+	Col      int    `json:"col"`
+	At       int64  `json:"at"` // Unix milliseconds
+}
+
+// GameRecord is the archived history of a finished game
+type GameRecord struct {
+	ID           string            `json:"id"`
+	RoomID       string            `json:"roomId"`
+	RoomCode     string            `json:"roomCode,omitempty"`
+	Variant      string            `json:"variant,omitempty"`
+	BoardSize    int               `json:"boardSize"`
+	WinLength    int               `json:"winLength"`
+	Players      map[string]string `json:"players"` // map[playerID]symbol
+	FirstTurn    string            `json:"firstTurn"`
+	Moves        []RecordedMove    `json:"moves"`
+	Winner       string            `json:"winner"` // PlayerID or empty for draw
+	WinnerSymbol string            `json:"winnerSymbol,omitempty"`
+	IsDraw       bool              `json:"isDraw"`
+	Reason       string            `json:"reason"`
+	StartedAt    int64             `json:"startedAt"` // Unix milliseconds
+	EndedAt      int64             `json:"endedAt"`   // Unix milliseconds
+
+	RatingChanges map[string]RatingChange `json:"ratingChanges,omitempty"` // Rated games only
+}
+
+// ReplayResponse carries an archived game in answer to GET_REPLAY
+type ReplayResponse struct {
+	Type string     `json:"type"`
+	Game GameRecord `json:"game"`
+}
+
+// ResyncResponse is the full authoritative state of a room, sent in answer to RESYNC.
+// Like every room message it carries the room version
+type ResyncResponse struct {
+	Type         string            `json:"type"`
+	RoomID       string            `json:"roomId"`
+	RoomCode     string            `json:"roomCode"`
+	State        string            `json:"state"`
+	Settings     RoomSettings      `json:"settings"`
+	GameID       string            `json:"gameId,omitempty"`
+	MoveNumber   int               `json:"moveNumber"`
+	Board        [][]string        `json:"board"`
+	CurrentTurn  string            `json:"currentTurn"`
+	Players      map[string]string `json:"players"` // map[playerID]symbol
+	Clocks       map[string]int64  `json:"clocks,omitempty"`
+	HostID       string            `json:"hostId"`
+	Locked       bool              `json:"locked"`
+	Spectators   int               `json:"spectators"`
+	Disconnected []string          `json:"disconnected,omitempty"` // Players within their grace period
+	Result       *GameOverResponse `json:"result,omitempty"`       // Last finished game
+
+	// Profiles of the players that have one, map[playerID]profile
+	Profiles map[string]PlayerProfile `json:"profiles,omitempty"`
+}
+
+// FindMatchPayload asks to enter the quick-match queue. Players are only paired with
+// others looking for the same variant, board size and time control
+type FindMatchPayload struct {
+	Variant     string      `json:"variant"`
+	BoardSize   int         `json:"boardSize,omitempty"` // 0 = variant default
+	TimeControl TimeControl `json:"timeControl"`
+}
+
+// MatchSearchingResponse confirms that the client is in the quick-match queue
+type MatchSearchingResponse struct {
+	Type        string      `json:"type"`
+	Variant     string      `json:"variant"`
+	BoardSize   int         `json:"boardSize"`
+	TimeControl TimeControl `json:"timeControl"`
+	Rating      float64     `json:"rating"`
+	QueueSize   int         `json:"queueSize"` // Players waiting, including the client
+}
+
+// MatchCancelledResponse confirms that the client left the quick-match queue
+type MatchCancelledResponse struct {
+	Type string `json:"type"`
+}
+
+// MatchFoundResponse is sent to both players when the queue pairs them. The room
+// follows with its usual ROOM_JOINED / GAME_START messages
+type MatchFoundResponse struct {
+	Type            string         `json:"type"`
+	RoomID          string         `json:"roomId"`
+	RoomCode        string         `json:"roomCode"`
+	PlayerID        string         `json:"playerId"`
+	OpponentID      string         `json:"opponentId"`
+	OpponentRating  float64        `json:"opponentRating"`
+	OpponentProfile *PlayerProfile `json:"opponentProfile,omitempty"`
+	Symbol          string         `json:"symbol"`
+	Settings        RoomSettings   `json:"settings"`
+	WaitedMs        int64          `json:"waitedMs"`
+}
+
+// GetLeaderboardPayload asks for a page of a variant's leaderboard
+type GetLeaderboardPayload struct {
+	Variant string `json:"variant"`
+	Season  int    `json:"season,omitempty"` // 0 = current season
+	Offset  int    `json:"offset,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+// LeaderboardEntry is a player's position in a leaderboard
+type LeaderboardEntry struct {
+	Rank      int     `json:"rank"`
+	PlayerID  string  `json:"playerId"`
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation"`
+	Points    float64 `json:"points"` // Season points: 1 per win, 0.5 per draw
+	Games     int     `json:"games"`  // Rated games played in the season
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+	Draws     int     `json:"draws"`
+}
+
+// SeasonInfo describes a leaderboard season
+type SeasonInfo struct {
+	Number    int   `json:"number"`
+	StartedAt int64 `json:"startedAt"` // Unix milliseconds
+	EndsAt    int64 `json:"endsAt"`    // Unix milliseconds
+	Current   bool  `json:"current"`
+}
+
+// LeaderboardResponse is a page of a leaderboard, sent in answer to GET_LEADERBOARD
+// and returned by GET /leaderboard
+type LeaderboardResponse struct {
+	Type    string             `json:"type"`
+	Variant string             `json:"variant"`
+	Season  SeasonInfo         `json:"season"`
+	Entries []LeaderboardEntry `json:"entries"`
+	Total   int                `json:"total"` // Ranked players in the season
+	Offset  int                `json:"offset"`
+	Limit   int                `json:"limit"`
+	You     *LeaderboardEntry  `json:"you,omitempty"` // The caller's own entry, if ranked
+}
+
+// LobbySnapshotResponse is the lobby state sent after SUBSCRIBE_LOBBY. Later changes
+// arrive as ROOM_ADDED, ROOM_UPDATED, ROOM_REMOVED and ONLINE_COUNT
+type LobbySnapshotResponse struct {
+	Type   string     `json:"type"`
+	Rooms  []RoomInfo `json:"rooms"`  // Public rooms
+	Online int        `json:"online"` // Connected players
+}
+
+// LobbyRoomResponse announces a new public room (ROOM_ADDED) or a change to one (ROOM_UPDATED)
+type LobbyRoomResponse struct {
+	Type string   `json:"type"`
+	Room RoomInfo `json:"room"`
+}
+
+// LobbyRoomRemovedResponse announces that a public room is gone (ROOM_REMOVED)
+type LobbyRoomRemovedResponse struct {
+	Type   string `json:"type"`
+	RoomID string `json:"roomId"`
+}
+
+// OnlineCountResponse carries the number of connected players (ONLINE_COUNT)
+type OnlineCountResponse struct {
+	Type   string `json:"type"`
+	Online int    `json:"online"`
+}
+
+// CreateTournamentPayload opens a tournament for registration (CREATE_TOURNAMENT)
+type CreateTournamentPayload struct {
+	Name            string       `json:"name"`
+	Format          string       `json:"format"`                    // single-elimination, double-elimination, round-robin, swiss or arena
+	Settings        RoomSettings `json:"settings"`                  // Settings for every game of the tournament
+	MaxPlayers      int          `json:"maxPlayers"`                // 0 means the default
+	Rounds          int          `json:"rounds,omitempty"`          // Swiss only; 0 derives it from the number of players
+	DurationMinutes int          `json:"durationMinutes,omitempty"` // Arena only; 0 means the default
+}
+
+// TournamentPayload identifies a tournament (JOIN_TOURNAMENT, LEAVE_TOURNAMENT,
+// START_TOURNAMENT, WATCH_TOURNAMENT and UNWATCH_TOURNAMENT)
+type TournamentPayload struct {
+	TournamentID string `json:"tournamentId"`
+}
+
+// TournamentPlayer is a registered player and their results in a tournament
+type TournamentPlayer struct {
+	Rank       int     `json:"rank"`
+	PlayerID   string  `json:"playerId"`
+	Seed       int     `json:"seed,omitempty"` // Assigned when the tournament starts
+	Rating     float64 `json:"rating"`
+	Points     float64 `json:"points"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	Byes       int     `json:"byes"`
+	Eliminated bool    `json:"eliminated"`
+	Buchholz   float64 `json:"buchholz,omitempty"` // Swiss tiebreak: sum of the opponents' points
+	Streak     int     `json:"streak,omitempty"`   // Arena: consecutive wins
+	OnFire     bool    `json:"onFire,omitempty"`   // Arena: results count double while on a streak
+	Paused     bool    `json:"paused,omitempty"`   // Arena: left or missed a game, not paired until rejoining
+}
+
+// TournamentMatch is a game of a tournament. An empty first or second player is a bye
+type TournamentMatch struct {
+	MatchID string `json:"matchId"`
+	Round   int    `json:"round"`
+	Bracket string `json:"bracket,omitempty"` // winners, losers or final (double elimination only)
+	First   string `json:"first,omitempty"`
+	Second  string `json:"second,omitempty"`
+	Status  string `json:"status"`           // ready, playing or finished
+	Winner  string `json:"winner,omitempty"` // Empty for draws and double no-shows
+	Result  string `json:"result,omitempty"` // win, draw, bye, no-show or seed
+	Games   int    `json:"games"`            // Games played, including replays after draws
+	RoomID  string `json:"roomId,omitempty"`
+}
+
+// TournamentUpdateResponse is the full state of a tournament (TOURNAMENT_UPDATE). It is
+// sent to the organizer, the registered players and the watchers after every change
+type TournamentUpdateResponse struct {
+	Type         string             `json:"type"`
+	TournamentID string             `json:"tournamentId"`
+	Name         string             `json:"name"`
+	Format       string             `json:"format"`
+	Status       string             `json:"status"` // registering, running or finished
+	OrganizerID  string             `json:"organizerId"`
+	Settings     RoomSettings       `json:"settings"`
+	MaxPlayers   int                `json:"maxPlayers"`
+	Round        int                `json:"round"`
+	Rounds       int                `json:"rounds,omitempty"` // Swiss: total rounds, known once it starts
+	Winner       string             `json:"winner,omitempty"`
+	Standings    []TournamentPlayer `json:"standings"`
+	Matches      []TournamentMatch  `json:"matches"`
+	CreatedAt    int64              `json:"createdAt"`            // Unix milliseconds
+	StartedAt    int64              `json:"startedAt,omitempty"`  // Unix milliseconds
+	EndsAt       int64              `json:"endsAt,omitempty"`     // Arena: Unix milliseconds after which no new games are paired
+	FinishedAt   int64              `json:"finishedAt,omitempty"` // Unix milliseconds
+}
+
+// TournamentSummary describes a tournament in TOURNAMENT_LIST
+type TournamentSummary struct {
+	TournamentID string `json:"tournamentId"`
+	Name         string `json:"name"`
+	Format       string `json:"format"`
+	Status       string `json:"status"`
+	OrganizerID  string `json:"organizerId"`
+	Variant      string `json:"variant"`
+	Players      int    `json:"players"`
+	MaxPlayers   int    `json:"maxPlayers"`
+	Round        int    `json:"round"`
+}
+
+// TournamentListResponse lists the tournaments, sent in answer to LIST_TOURNAMENTS
+type TournamentListResponse struct {
+	Type        string              `json:"type"`
+	Tournaments []TournamentSummary `json:"tournaments"`
+}
+
+// TournamentMatchResponse tells a player that their next tournament game has a room
+// (TOURNAMENT_MATCH). The player is seated automatically if connected
+type TournamentMatchResponse struct {
+	Type         string `json:"type"`
+	TournamentID string `json:"tournamentId"`
+	MatchID      string `json:"matchId"`
+	Round        int    `json:"round"`
+	Game         int    `json:"game"` // 1 for the first game; higher after drawn games are replayed
+	RoomID       string `json:"roomId"`
+	RoomCode     string `json:"roomCode"`
+	OpponentID   string `json:"opponentId"`
+	Deadline     int64  `json:"deadline"` // Unix milliseconds; absent players forfeit after it
+}
+
+// ChallengePlayerPayload challenges a connected player to a game (CHALLENGE_PLAYER)
+type ChallengePlayerPayload struct {
+	TargetID string       `json:"targetId"`
+	Settings RoomSettings `json:"settings"` // Settings for the game; the room is always private
+}
+
+// ChallengePayload identifies a pending challenge (ACCEPT_CHALLENGE and DECLINE_CHALLENGE)
+type ChallengePayload struct {
+	ChallengeID string `json:"challengeId"`
+}
+
+// ChallengeResponse describes a pending challenge. It is sent to the challenger as
+// CHALLENGE_SENT and to the challenged player as CHALLENGE_RECEIVED
+type ChallengeResponse struct {
+	Type              string         `json:"type"`
+	ChallengeID       string         `json:"challengeId"`
+	ChallengerID      string         `json:"challengerId"`
+	TargetID          string         `json:"targetId"`
+	ChallengerProfile *PlayerProfile `json:"challengerProfile,omitempty"`
+	TargetProfile     *PlayerProfile `json:"targetProfile,omitempty"`
+	Settings          RoomSettings   `json:"settings"`
+	ExpiresAt         int64          `json:"expiresAt"` // Unix milliseconds
+}
+
+// ChallengeClosedResponse tells both players that a challenge is no longer pending
+// (CHALLENGE_DECLINED or CHALLENGE_CANCELLED)
+type ChallengeClosedResponse struct {
+	Type        string `json:"type"`
+	ChallengeID string `json:"challengeId"`
+	Reason      string `json:"reason"` // declined, withdrawn, expired, disconnected or busy
+}
+
+// ChallengeAcceptedResponse tells both players that a challenge was accepted and which
+// private room the game is played in (CHALLENGE_ACCEPTED). Both players are seated
+// automatically
+type ChallengeAcceptedResponse struct {
+	Type            string         `json:"type"`
+	ChallengeID     string         `json:"challengeId"`
+	RoomID          string         `json:"roomId"`
+	RoomCode        string         `json:"roomCode"`
+	PlayerID        string         `json:"playerId"`
+	OpponentID      string         `json:"opponentId"`
+	OpponentProfile *PlayerProfile `json:"opponentProfile,omitempty"`
+	Symbol          string         `json:"symbol"`
+	Settings        RoomSettings   `json:"settings"`
+}
+
+// PlayerProfile is how a player is shown to others. Clients should fall back to the
+// player ID for players without a profile
+type PlayerProfile struct {
+	DisplayName string `json:"displayName"`
+	Avatar      string `json:"avatar,omitempty"`  // Avatar identifier chosen by the client
+	Country     string `json:"country,omitempty"` // ISO 3166-1 alpha-2 code, upper case
+}
+
+// SetProfilePayload sets the sender's profile (SET_PROFILE)
+type SetProfilePayload struct {
+	DisplayName string `json:"displayName"`
+	Avatar      string `json:"avatar,omitempty"`
+	Country     string `json:"country,omitempty"`
+}
+
+// ProfileResponse confirms the sender's normalized profile (PROFILE_UPDATED)
+type ProfileResponse struct {
+	Type     string        `json:"type"`
+	PlayerID string        `json:"playerId"`
+	Profile  PlayerProfile `json:"profile"`
+}
+
+// FriendPayload identifies another player (ADD_FRIEND and REMOVE_FRIEND)
+type FriendPayload struct {
+	PlayerID string `json:"playerId"`
+}
+
+// SetPresencePayload chooses how the sender appears to their friends (SET_PRESENCE)
+type SetPresencePayload struct {
+	Status string `json:"status"` // online, away or invisible
+}
+
+// FriendInfo is a player in a friend list. Presence is only shared between mutual friends,
+// so status and roomId are absent otherwise
+type FriendInfo struct {
+	PlayerID string         `json:"playerId"`
+	Profile  *PlayerProfile `json:"profile,omitempty"`
+	Mutual   bool           `json:"mutual"`           // Both players added each other
+	Status   string         `json:"status,omitempty"` // online, in-game, away or offline
+	RoomID   string         `json:"roomId,omitempty"` // Only while in game in a public room
+}
+
+// FriendListResponse lists the sender's friends and the players who added them without
+// being added back (FRIEND_LIST)
+type FriendListResponse struct {
+	Type     string       `json:"type"`
+	Presence string       `json:"presence"` // The sender's own choice: online, away or invisible
+	Friends  []FriendInfo `json:"friends"`
+	Requests []FriendInfo `json:"requests"`
+}
+
+// FriendResponse carries a single friend list change (FRIEND_ADDED, FRIEND_REMOVED and
+// FRIEND_REQUEST)
+type FriendResponse struct {
+	Type   string     `json:"type"`
+	Friend FriendInfo `json:"friend"`
+}
+
+// FriendPresenceResponse is pushed when a mutual friend's presence changes (FRIEND_PRESENCE)
+type FriendPresenceResponse struct {
+	Type     string `json:"type"`
+	PlayerID string `json:"playerId"`
+	Status   string `json:"status"`           // online, in-game, away or offline
+	RoomID   string `json:"roomId,omitempty"` // Only while in game in a public room
+}
+
+// PresenceResponse confirms the sender's presence choice (PRESENCE_UPDATED)
+type PresenceResponse struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
 }